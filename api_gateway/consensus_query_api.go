@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_gateway
+
+import (
+	"github.com/it-chain/engine/consensus/pbft"
+)
+
+// PBFT replica의 진행 중인 state를 읽는 저장소
+type ConsensusStateRepository interface {
+	Load() (pbft.State, error)
+}
+
+type ConsensusQueryApi struct {
+	stateRepository ConsensusStateRepository
+}
+
+func NewConsensusQueryApi(stateRepository ConsensusStateRepository) ConsensusQueryApi {
+	return ConsensusQueryApi{
+		stateRepository: stateRepository,
+	}
+}
+
+// 진행 중인 state가 없으면 IdleStage인 빈 state를 반환한다.
+func (q ConsensusQueryApi) GetState() (pbft.StateView, error) {
+
+	state, err := q.stateRepository.Load()
+	if err == pbft.ErrEmptyRepo {
+		return pbft.NewStateView(pbft.State{}), nil
+	}
+
+	if err != nil {
+		return pbft.StateView{}, err
+	}
+
+	return pbft.NewStateView(state), nil
+}
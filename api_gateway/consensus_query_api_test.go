@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_gateway
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusQueryApi_GetState(t *testing.T) {
+
+	tests := map[string]struct {
+		input  *pbft.State
+		output pbft.StateView
+	}{
+		"state in progress": {
+			input: &pbft.State{
+				StateID:         pbft.StateID{"state1"},
+				LeaderID:        "leader",
+				Representatives: []*pbft.Representative{pbft.NewRepresentative("leader")},
+				CurrentStage:    pbft.PREPARE_STAGE,
+			},
+			output: pbft.StateView{
+				StateID:         "state1",
+				LeaderID:        "leader",
+				Stage:           pbft.PREPARE_STAGE,
+				Representatives: []string{"leader"},
+				PrepareSenders:  []string{},
+				CommitSenders:   []string{},
+			},
+		},
+		"no state": {
+			input: nil,
+			output: pbft.StateView{
+				Stage:           pbft.IDLE_STAGE,
+				Representatives: []string{},
+				PrepareSenders:  []string{},
+				CommitSenders:   []string{},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		repo := pbft.NewStateRepository()
+		if test.input != nil {
+			assert.NoError(t, repo.Save(*test.input))
+		}
+
+		queryApi := NewConsensusQueryApi(&repo)
+
+		// when
+		view, err := queryApi.GetState()
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.output, view)
+	}
+}
@@ -53,3 +53,15 @@ func makeFindAllMetaEndpoint(i ICodeQueryApi) endpoint.Endpoint {
 		return metas, nil
 	}
 }
+
+// consensus
+func makeGetConsensusStateEndpoint(c ConsensusQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		state, err := c.GetState()
+		if err != nil {
+			logger.Error(&logger.Fields{"err_message": err.Error()}, "error while get consensus state endpoint")
+			return nil, err
+		}
+		return state, nil
+	}
+}
@@ -17,6 +17,7 @@
 package api_gateway
 
 import (
+	"sort"
 	"sync"
 
 	"errors"
@@ -60,6 +61,31 @@ func (pqa *PeerQueryApi) GetPeerList() ([]p2p.Peer, error) {
 	return peerList, nil
 }
 
+// handshake로 받은 height가 주어진 height 보다 높은 peer 들을 height가 높은 순서로 반환한다.
+// block sync 할 peer를 고르는데 쓰인다.
+func (pqa *PeerQueryApi) FindPeersHigherThan(height uint64) ([]p2p.Peer, error) {
+
+	peerList, err := pqa.GetPeerList()
+
+	if err != nil {
+		return nil, err
+	}
+
+	higherPeers := make([]p2p.Peer, 0)
+
+	for _, peer := range peerList {
+		if peer.Metadata.Height > height {
+			higherPeers = append(higherPeers, peer)
+		}
+	}
+
+	sort.Slice(higherPeers, func(i, j int) bool {
+		return higherPeers[i].Metadata.Height > higherPeers[j].Metadata.Height
+	})
+
+	return higherPeers, nil
+}
+
 func (pqa *PeerQueryApi) GetLeader() (p2p.Leader, error) {
 
 	return pqa.peerRepository.GetLeader()
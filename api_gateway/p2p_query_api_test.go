@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_gateway
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/mem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerQueryApi_FindPeersHigherThan(t *testing.T) {
+
+	// given
+	peerRepository := mem.NewPeerReopository()
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "1"}, IpAddress: "1", Metadata: p2p.NodeMetadata{Height: 3}})
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2", Metadata: p2p.NodeMetadata{Height: 10}})
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "3"}, IpAddress: "3", Metadata: p2p.NodeMetadata{Height: 7}})
+
+	peerQueryApi := NewPeerQueryApi(&peerRepository)
+
+	// when
+	peers, err := peerQueryApi.FindPeersHigherThan(5)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(peers))
+	assert.Equal(t, "2", peers[0].GetID())
+	assert.Equal(t, "3", peers[1].GetID())
+}
@@ -64,6 +64,24 @@ func ICodeApiHandler(api ICodeQueryApi, logger kitlog.Logger) http.Handler {
 	return r
 }
 
+func ConsensusApiHandler(api ConsensusQueryApi, logger kitlog.Logger) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorLogger(logger),
+	}
+
+	getStateHandler := kithttp.NewServer(
+		makeGetConsensusStateEndpoint(api),
+		decodeGetConsensusStateRequest,
+		encodeResponse,
+		opts...,
+	)
+	r := mux.NewRouter()
+
+	r.Handle("/consensus/state", getStateHandler).Methods("GET")
+
+	return r
+}
+
 // this return nil because this request body is empty
 func decodeFindAllUncommittedTransactionsRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	return nil, nil
@@ -77,6 +95,10 @@ func decodeFindAllMetaRequest(_ context.Context, r *http.Request) (interface{},
 	return nil, nil
 }
 
+func decodeGetConsensusStateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
 func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
 
 	if e, ok := response.(errorer); ok && e.error() != nil {
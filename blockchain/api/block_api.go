@@ -17,6 +17,7 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/it-chain/engine/blockchain"
@@ -87,14 +88,12 @@ func (bApi BlockApi) CommitGenesisBlock(GenesisConfPath string) error {
 	return bApi.eventService.Publish("block.committed", commitEvent)
 }
 
-func (bApi BlockApi) CommitProposedBlock(txList []*blockchain.DefaultTransaction) error {
-	logger.Info(nil, "[Blockchain] Committing proposed block")
-
-	// create
+// 마지막 block 다음에 올 block을 생성한다. 생성된 block은 저장되지 않는다.
+func (bApi BlockApi) CreateProposedBlock(txList []*blockchain.DefaultTransaction) (blockchain.DefaultBlock, error) {
 	lastBlock, err := bApi.blockRepository.FindLast()
 
 	if err != nil {
-		return ErrGetLastBlock
+		return blockchain.DefaultBlock{}, ErrGetLastBlock
 	}
 
 	prevSeal := lastBlock.GetSeal()
@@ -106,30 +105,71 @@ func (bApi BlockApi) CommitProposedBlock(txList []*blockchain.DefaultTransaction
 	ProposedBlock, err := blockchain.CreateProposedBlock(prevSeal, height, txList, []byte(creator))
 
 	if err != nil {
-		return ErrCreateProposedBlock
+		return blockchain.DefaultBlock{}, ErrCreateProposedBlock
 	}
 
+	return ProposedBlock, nil
+}
+
+// consensus에서 합의된 block을 stage 한 뒤 commit 한다.
+// leader를 포함한 모든 replica는 합의된 block을 그대로 저장하므로 같은 blockchain을 가진다.
+func (bApi BlockApi) CommitConfirmedBlock(block blockchain.DefaultBlock) error {
+	logger.Info(nil, fmt.Sprintf("[Blockchain] Committing confirmed block - seal: [%x]", block.Seal))
+
+	// stage
+	if err := bApi.validateConfirmedBlock(block); err != nil {
+		return err
+	}
+
+	block.SetState(blockchain.Staged)
+
 	// save(commit)
-	ProposedBlock.SetState(blockchain.Committed)
+	block.SetState(blockchain.Committed)
 
-	err = bApi.blockRepository.Save(ProposedBlock)
+	err := bApi.blockRepository.Save(block)
 
 	if err != nil {
 		return ErrSaveBlock
 	}
 
 	// publish
-	commitEvent, err := createBlockCommittedEvent(ProposedBlock)
+	commitEvent, err := createBlockCommittedEvent(block)
 
 	if err != nil {
 		return ErrCreateEvent
 	}
 
-	logger.Info(nil, fmt.Sprintf("[Blockchain] Proposed block has Committed - seal: [%x],  height: [%d]", ProposedBlock.Seal, ProposedBlock.Height))
+	logger.Info(nil, fmt.Sprintf("[Blockchain] Confirmed block has Committed - seal: [%x], height: [%d]", block.Seal, block.Height))
 
 	return bApi.eventService.Publish("block.committed", commitEvent)
 }
 
+func (bApi BlockApi) validateConfirmedBlock(block blockchain.DefaultBlock) error {
+	lastBlock, err := bApi.blockRepository.FindLast()
+
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	if block.GetHeight() != lastBlock.GetHeight()+1 {
+		return ErrInvalidBlockHeight
+	}
+
+	if !bytes.Equal(block.GetPrevSeal(), lastBlock.GetSeal()) {
+		return ErrInvalidPrevSeal
+	}
+
+	validator := blockchain.DefaultValidator{}
+
+	valid, err := validator.ValidateSeal(block.GetSeal(), &block)
+
+	if err != nil || !valid {
+		return ErrInvalidSeal
+	}
+
+	return nil
+}
+
 func createBlockCommittedEvent(block blockchain.DefaultBlock) (event.BlockCommitted, error) {
 
 	txList := blockchain.ConvBackFromTransactionList(block.TxList)
@@ -23,7 +23,6 @@ import (
 	"io/ioutil"
 	"os"
 	"sync"
-	"time"
 
 	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/blockchain/api"
@@ -108,92 +107,6 @@ func TestBlockApi_SyncIsProgressing(t *testing.T) {
 	assert.Equal(t, blockchain.DONE, state)
 }
 
-// TODO: Write real situation test code, after finishing implementing api_gatey block_query_api.go
-func TestBlockApi_CommitProposedBlock(t *testing.T) {
-
-	lastBlock := blockchain.DefaultBlock{
-		Seal:     []byte("seal"),
-		PrevSeal: []byte("prevSeal"),
-		Height:   uint64(11),
-		TxList: []*blockchain.DefaultTransaction{
-			{
-				ID:        "tx01",
-				ICodeID:   "ICodeID",
-				PeerID:    "junksound",
-				Timestamp: time.Now().Round(0),
-				Jsonrpc:   "",
-				Function:  "",
-				Args:      make([]string, 0),
-				Signature: []byte("Signature"),
-			},
-		},
-		TxSeal:    nil,
-		Timestamp: time.Time{},
-		Creator:   nil,
-		State:     "",
-	}
-
-	txList := []*blockchain.DefaultTransaction{
-		{
-			ID:        "tx02",
-			ICodeID:   "ICodeID",
-			PeerID:    "junksound",
-			Timestamp: time.Now().Round(0),
-			Jsonrpc:   "",
-			Function:  "",
-			Args:      make([]string, 0),
-			Signature: []byte("Signature"),
-		},
-	}
-
-	//set subscriber
-	var wg sync.WaitGroup
-	wg.Add(1)
-
-	subscriber := pubsub.NewTopicSubscriber("", "Event")
-	defer subscriber.Close()
-
-	handler := &mock.CommitEventHandler{}
-
-	handler.HandleFunc = func(event event.BlockCommitted) {
-		assert.Equal(t, "tx02", event.TxList[0].ID)
-		assert.Equal(t, blockchain.Committed, event.State)
-		wg.Done()
-	}
-
-	subscriber.SubscribeTopic("block.*", handler)
-
-	//set api param
-
-	publisherID := "junksound"
-
-	blockRepo := mock.BlockRepository{}
-
-	blockRepo.FindLastFunc = func() (blockchain.DefaultBlock, error) {
-		return lastBlock, nil
-	}
-
-	blockRepo.SaveFunc = func(block blockchain.DefaultBlock) error {
-
-		assert.Equal(t, "tx02", block.GetTxList()[0].GetID())
-
-		return nil
-	}
-
-	eventService := common.NewEventService("", "Event")
-
-	bApi, err := api.NewBlockApi(publisherID, blockRepo, eventService)
-
-	assert.NoError(t, err)
-
-	// when
-	err = bApi.CommitProposedBlock(txList)
-
-	// then
-	assert.NoError(t, err)
-	wg.Wait()
-}
-
 func TestBlockApi_CommitGenesisBlock(t *testing.T) {
 	GenesisFilePath := "./Genesis.conf"
 	defer os.Remove(GenesisFilePath)
@@ -246,3 +159,73 @@ func TestBlockApi_CommitGenesisBlock(t *testing.T) {
 	assert.NoError(t, err)
 	wg.Wait()
 }
+
+func TestBlockApi_CommitConfirmedBlock(t *testing.T) {
+	lastBlock := mock.GetNewBlock([]byte("genesis"), 0)
+	confirmedBlock := mock.GetNewBlock(lastBlock.GetSeal(), 1)
+
+	wrongHeightBlock := mock.GetNewBlock(lastBlock.GetSeal(), 3)
+	wrongPrevSealBlock := mock.GetNewBlock([]byte("another"), 1)
+
+	wrongSealBlock := mock.GetNewBlock(lastBlock.GetSeal(), 1)
+	wrongSealBlock.SetSeal([]byte("wrong seal"))
+
+	tests := map[string]struct {
+		input struct {
+			block blockchain.DefaultBlock
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				block blockchain.DefaultBlock
+			}{block: *confirmedBlock},
+			err: nil,
+		},
+		"wrong height": {
+			input: struct {
+				block blockchain.DefaultBlock
+			}{block: *wrongHeightBlock},
+			err: api.ErrInvalidBlockHeight,
+		},
+		"wrong prev seal": {
+			input: struct {
+				block blockchain.DefaultBlock
+			}{block: *wrongPrevSealBlock},
+			err: api.ErrInvalidPrevSeal,
+		},
+		"wrong seal": {
+			input: struct {
+				block blockchain.DefaultBlock
+			}{block: *wrongSealBlock},
+			err: api.ErrInvalidSeal,
+		},
+	}
+
+	blockRepo := mock.BlockRepository{}
+	blockRepo.FindLastFunc = func() (blockchain.DefaultBlock, error) {
+		return *lastBlock, nil
+	}
+	blockRepo.SaveFunc = func(block blockchain.DefaultBlock) error {
+		assert.Equal(t, confirmedBlock.GetSeal(), block.GetSeal())
+		assert.Equal(t, blockchain.Committed, block.GetState())
+		return nil
+	}
+
+	eventService := mock.EventService{}
+	eventService.PublishFunc = func(topic string, e interface{}) error {
+		assert.Equal(t, "block.committed", topic)
+		assert.Equal(t, confirmedBlock.GetSeal(), e.(event.BlockCommitted).Seal)
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepo, eventService)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		err := blockApi.CommitConfirmedBlock(test.input.block)
+
+		assert.Equal(t, test.err, err)
+	}
+}
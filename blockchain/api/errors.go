@@ -23,3 +23,6 @@ var ErrSaveBlock = errors.New("Error in saving block")
 var ErrCreateEvent = errors.New("Error in creating event")
 var ErrGetLastBlock = errors.New("Error in getting last block")
 var ErrCreateProposedBlock = errors.New("Error in creating proposed block")
+var ErrInvalidBlockHeight = errors.New("Error block height is not next to last block")
+var ErrInvalidPrevSeal = errors.New("Error prev seal is not same with last block seal")
+var ErrInvalidSeal = errors.New("Error invalid block seal")
@@ -17,11 +17,14 @@
 package blockchain
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"time"
 
 	"encoding/json"
+
+	"github.com/it-chain/engine/consensus"
 )
 
 func CreateGenesisBlock(genesisconfFilePath string) (DefaultBlock, error) {
@@ -85,6 +88,23 @@ func setBlockWithConfig(filePath string, block *DefaultBlock) error {
 	block.SetPrevSeal(make([]byte, 0))
 	block.SetHeight(uint64(GenesisConfig.Height))
 	block.SetTxSeal(make([][]byte, 0))
+
+	// genesis의 validator들은 membership transaction으로 genesis block에 기록된다.
+	if len(GenesisConfig.Validators) != 0 {
+		txList := createValidatorTxList(GenesisConfig.Validators, timeStamp, GenesisConfig.Creator)
+		for _, tx := range txList {
+			block.PutTx(tx)
+		}
+
+		validator := DefaultValidator{}
+		txSeal, err := validator.BuildTxSeal(ConvertTxType(txList))
+		if err != nil {
+			return err
+		}
+
+		block.SetTxSeal(txSeal)
+	}
+
 	block.SetTimestamp(timeStamp)
 	block.SetCreator([]byte(GenesisConfig.Creator))
 	block.SetState(Created)
@@ -98,6 +118,25 @@ type GenesisConfig struct {
 	Height       int
 	TimeStamp    string
 	Creator      string
+	Validators   []string
+}
+
+// 모든 노드가 같은 genesis block을 만들 수 있도록 transaction의 내용은 genesis config 로부터만 정해진다.
+func createValidatorTxList(validators []string, timeStamp time.Time, creator string) []*DefaultTransaction {
+	txList := make([]*DefaultTransaction, 0)
+
+	for i, validator := range validators {
+		txList = append(txList, &DefaultTransaction{
+			ID:        fmt.Sprintf("genesis-validator-%d", i),
+			ICodeID:   consensus.MembershipICodeID,
+			PeerID:    creator,
+			Timestamp: timeStamp,
+			Function:  consensus.Join,
+			Args:      []string{validator},
+		})
+	}
+
+	return txList
 }
 
 func CreateProposedBlock(prevSeal []byte, height uint64, txList []*DefaultTransaction, Creator []byte) (DefaultBlock, error) {
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/consensus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -120,6 +121,45 @@ func TestCreateGenesisBlock(t *testing.T) {
 
 }
 
+func TestCreateGenesisBlock_WithValidators(t *testing.T) {
+
+	//given
+	GenesisFilePath := "./GenesisBlockValidatorConfig.json"
+
+	defer os.Remove(GenesisFilePath)
+
+	GenesisBlockConfigJson := []byte(`{
+									"Orgainaization":"Default",
+									"NetworkId":"Default",
+								  	"Height":0,
+								  	"TimeStamp":"Jan 1, 2018 at 0:00am (KST)",
+								  	"Creator":"junksound",
+								  	"Validators":["peer1","peer2"]
+								}`)
+
+	err := ioutil.WriteFile(GenesisFilePath, GenesisBlockConfigJson, 0644)
+	assert.NoError(t, err)
+
+	//when
+	GenesisBlock1, err := blockchain.CreateGenesisBlock(GenesisFilePath)
+	assert.NoError(t, err)
+
+	GenesisBlock2, err := blockchain.CreateGenesisBlock(GenesisFilePath)
+	assert.NoError(t, err)
+
+	//then
+	txList := GenesisBlock1.GetTxList()
+	assert.Equal(t, 2, len(txList))
+	assert.Equal(t, consensus.MembershipICodeID, txList[0].(*blockchain.DefaultTransaction).ICodeID)
+	assert.Equal(t, consensus.Join, txList[0].(*blockchain.DefaultTransaction).Function)
+	assert.Equal(t, []string{"peer1"}, txList[0].(*blockchain.DefaultTransaction).Args)
+	assert.Equal(t, []string{"peer2"}, txList[1].(*blockchain.DefaultTransaction).Args)
+	assert.NotEmpty(t, GenesisBlock1.GetTxSeal())
+
+	// 모든 노드가 같은 genesis block을 만든다.
+	assert.Equal(t, GenesisBlock1.GetSeal(), GenesisBlock2.GetSeal())
+}
+
 func TestCreateProposedBlock(t *testing.T) {
 
 	//given
@@ -23,21 +23,22 @@ import (
 )
 
 type BlockCommitApi interface {
-	CommitProposedBlock(txList []*blockchain.DefaultTransaction) error
+	CreateProposedBlock(txList []*blockchain.DefaultTransaction) (blockchain.DefaultBlock, error)
 }
 
 type BlockProposeCommandHandler struct {
-	blockApi   BlockCommitApi
-	engineMode string
+	blockApi         BlockCommitApi
+	consensusService blockchain.ConsensusService
 }
 
-func NewBlockProposeCommandHandler(blockApi BlockCommitApi, engineMode string) *BlockProposeCommandHandler {
+func NewBlockProposeCommandHandler(blockApi BlockCommitApi, consensusService blockchain.ConsensusService) *BlockProposeCommandHandler {
 	return &BlockProposeCommandHandler{
-		blockApi:   blockApi,
-		engineMode: engineMode,
+		blockApi:         blockApi,
+		consensusService: consensusService,
 	}
 }
 
+// 생성한 block을 합의에 올린다. block은 합의가 끝난 뒤 deliver hook에서 commit 된다.
 func (h *BlockProposeCommandHandler) HandleProposeBlockCommand(command command.ProposeBlock) (struct{}, rpc.Error) {
 	if err := validateCommand(command); err != nil {
 		return struct{}{}, rpc.Error{Message: err.Error()}
@@ -47,16 +48,14 @@ func (h *BlockProposeCommandHandler) HandleProposeBlockCommand(command command.P
 
 	defaultTxList := getBackTxList(txList)
 
-	if h.engineMode == "solo" {
+	proposedBlock, err := h.blockApi.CreateProposedBlock(defaultTxList)
 
-		//commit
-		err := h.blockApi.CommitProposedBlock(defaultTxList)
-
-		if err != nil {
-			return struct{}{}, rpc.Error{Message: err.Error()}
-		}
+	if err != nil {
+		return struct{}{}, rpc.Error{Message: err.Error()}
+	}
 
-		return struct{}{}, rpc.Error{}
+	if err := h.consensusService.ConsentBlock(proposedBlock); err != nil {
+		return struct{}{}, rpc.Error{Message: err.Error()}
 	}
 
 	return struct{}{}, rpc.Error{}
@@ -35,6 +35,7 @@ import (
 	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/common/event"
 	"github.com/it-chain/engine/common/rabbitmq/pubsub"
+	"github.com/it-chain/engine/consensus/solo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -80,7 +81,11 @@ func TestBlockProposeCommandHandler_HandleProposeBlockCommand(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	commandHandler := adapter.NewBlockProposeCommandHandler(bApi, "solo")
+	// solo 합의는 제안된 block을 곧바로 deliver hook으로 전달한다.
+	soloConsensus := solo.NewConsensus()
+	soloConsensus.OnDeliver(adapter.NewConfirmedBlockHandler(bApi).HandleConfirmedBlock)
+
+	commandHandler := adapter.NewBlockProposeCommandHandler(bApi, adapter.NewConsensusService(soloConsensus))
 
 	//when
 	_, errRPC := commandHandler.HandleProposeBlockCommand(command.ProposeBlock{TxList: nil})
@@ -145,3 +150,45 @@ func TestBlockProposeCommandHandler_HandleProposeBlockCommand(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestBlockProposeCommandHandler_HandleProposeBlockCommand_Consensus(t *testing.T) {
+	// given
+	proposedBlock := mock.GetNewBlock([]byte("genesis"), 1)
+
+	blockApi := mock.BlockApi{}
+	blockApi.CreateProposedBlockFunc = func(txList []*blockchain.DefaultTransaction) (blockchain.DefaultBlock, error) {
+		assert.Equal(t, "tx01", txList[0].ID)
+		return *proposedBlock, nil
+	}
+
+	consented := false
+
+	consensusService := mock.ConsensusService{}
+	consensusService.ConsentBlockFunc = func(block blockchain.DefaultBlock) error {
+		consented = true
+		assert.Equal(t, proposedBlock.Seal, block.Seal)
+		return nil
+	}
+
+	commandHandler := adapter.NewBlockProposeCommandHandler(blockApi, consensusService)
+
+	// when
+	_, errRPC := commandHandler.HandleProposeBlockCommand(command.ProposeBlock{
+		TxList: []command.Tx{
+			{
+				ID:        "tx01",
+				ICodeID:   "ICodeID",
+				PeerID:    "2",
+				TimeStamp: time.Now().Round(0),
+				Jsonrpc:   "123",
+				Function:  "function1",
+				Args:      []string{"arg1", "arg2"},
+				Signature: []byte{0x1},
+			},
+		},
+	})
+
+	// then
+	assert.Equal(t, rpc.Error{}, errRPC)
+	assert.True(t, consented)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/consensus"
+)
+
+type ConfirmedBlockCommitApi interface {
+	CommitConfirmedBlock(block blockchain.DefaultBlock) error
+}
+
+type ConfirmedBlockHandler struct {
+	blockApi ConfirmedBlockCommitApi
+	mutex    *sync.Mutex
+}
+
+func NewConfirmedBlockHandler(blockApi ConfirmedBlockCommitApi) *ConfirmedBlockHandler {
+	return &ConfirmedBlockHandler{
+		blockApi: blockApi,
+		mutex:    &sync.Mutex{},
+	}
+}
+
+// consensus의 deliver hook으로 등록되어, 합의된 block을 받아 stage, commit 한다.
+func (h *ConfirmedBlockHandler) HandleConfirmedBlock(proposedBlock consensus.ProposedBlock) error {
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	block, err := createConfirmedBlock(proposedBlock)
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[Blockchain] Fail to restore confirmed block - seal: [%x], err: [%s]", proposedBlock.Seal, err.Error()))
+		return err
+	}
+
+	if err := h.blockApi.CommitConfirmedBlock(block); err != nil {
+		logger.Error(nil, fmt.Sprintf("[Blockchain] Fail to commit confirmed block - seal: [%x], err: [%s]", block.Seal, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func createConfirmedBlock(proposedBlock consensus.ProposedBlock) (blockchain.DefaultBlock, error) {
+	if proposedBlock.Body == nil {
+		return blockchain.DefaultBlock{}, ErrBlockNil
+	}
+
+	// common.Deserialize는 잘못된 body에 panic 하므로 직접 decode 한다.
+	startConsensusCommand := command.StartConsensus{}
+	if err := json.Unmarshal(proposedBlock.Body, &startConsensusCommand); err != nil {
+		return blockchain.DefaultBlock{}, err
+	}
+
+	if !bytes.Equal(proposedBlock.Seal, startConsensusCommand.Seal) {
+		return blockchain.DefaultBlock{}, ErrSealNotSame
+	}
+
+	return blockchain.DefaultBlock{
+		Seal:      startConsensusCommand.Seal,
+		PrevSeal:  startConsensusCommand.PrevSeal,
+		Height:    startConsensusCommand.Height,
+		TxList:    getBackTxList(startConsensusCommand.TxList),
+		TxSeal:    startConsensusCommand.TxSeal,
+		Timestamp: startConsensusCommand.Timestamp,
+		Creator:   startConsensusCommand.Creator,
+		State:     blockchain.Created,
+	}, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmedBlockHandler_HandleConfirmedBlock(t *testing.T) {
+	// given
+	timestamp := time.Now().Round(0)
+	startConsensusCommand := command.StartConsensus{
+		Seal:     []byte("seal"),
+		PrevSeal: []byte("prevSeal"),
+		Height:   12,
+		TxList: []command.Tx{
+			{
+				ID:       "tx01",
+				ICodeID:  "ICodeID",
+				Function: "function1",
+				Args:     []string{"arg1"},
+			},
+		},
+		TxSeal:    [][]byte{[]byte("txSeal")},
+		Timestamp: timestamp,
+		Creator:   []byte("creator"),
+	}
+
+	body, err := common.Serialize(startConsensusCommand)
+	assert.NoError(t, err)
+
+	called := 0
+
+	blockApi := mock.BlockApi{}
+	blockApi.CommitConfirmedBlockFunc = func(block blockchain.DefaultBlock) error {
+		called++
+
+		assert.Equal(t, startConsensusCommand.Seal, block.Seal)
+		assert.Equal(t, startConsensusCommand.PrevSeal, block.PrevSeal)
+		assert.Equal(t, startConsensusCommand.Height, block.Height)
+		assert.Equal(t, startConsensusCommand.TxSeal, block.TxSeal)
+		assert.Equal(t, startConsensusCommand.Creator, block.Creator)
+		assert.True(t, timestamp.Equal(block.Timestamp))
+		assert.Equal(t, "tx01", block.TxList[0].ID)
+
+		return nil
+	}
+
+	handler := adapter.NewConfirmedBlockHandler(blockApi)
+
+	malformedErr := json.Unmarshal([]byte("{"), &command.StartConsensus{})
+
+	tests := map[string]struct {
+		input struct {
+			block consensus.ProposedBlock
+		}
+		output struct {
+			err    error
+			called int
+		}
+	}{
+		"success": {
+			input: struct {
+				block consensus.ProposedBlock
+			}{block: consensus.ProposedBlock{Seal: []byte("seal"), Body: body}},
+			output: struct {
+				err    error
+				called int
+			}{err: nil, called: 1},
+		},
+		"empty body": {
+			input: struct {
+				block consensus.ProposedBlock
+			}{block: consensus.ProposedBlock{Seal: []byte("seal")}},
+			output: struct {
+				err    error
+				called int
+			}{err: adapter.ErrBlockNil, called: 0},
+		},
+		"malformed body": {
+			input: struct {
+				block consensus.ProposedBlock
+			}{block: consensus.ProposedBlock{Seal: []byte("seal"), Body: []byte("{")}},
+			output: struct {
+				err    error
+				called int
+			}{err: malformedErr, called: 0},
+		},
+		"seal not same": {
+			input: struct {
+				block consensus.ProposedBlock
+			}{block: consensus.ProposedBlock{Seal: []byte("otherSeal"), Body: body}},
+			output: struct {
+				err    error
+				called int
+			}{err: adapter.ErrSealNotSame, called: 0},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		called = 0
+
+		// when
+		err := handler.HandleConfirmedBlock(test.input.block)
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.Equal(t, test.output.called, called)
+	}
+
+	// given (commit failure is returned to consensus)
+	commitErr := errors.New("commit failed")
+	blockApi.CommitConfirmedBlockFunc = func(block blockchain.DefaultBlock) error {
+		return commitErr
+	}
+
+	handler = adapter.NewConfirmedBlockHandler(blockApi)
+
+	// when
+	err = handler.HandleConfirmedBlock(consensus.ProposedBlock{Seal: []byte("seal"), Body: body})
+
+	// then
+	assert.Equal(t, commitErr, err)
+}
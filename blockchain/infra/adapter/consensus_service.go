@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/consensus"
+)
+
+// 설정된 합의 방식(solo, pbft, raft)에 block을 제안한다.
+type ConsensusService struct {
+	consensus consensus.Consensus
+}
+
+func NewConsensusService(consensus consensus.Consensus) *ConsensusService {
+	return &ConsensusService{
+		consensus: consensus,
+	}
+}
+
+func (s ConsensusService) ConsentBlock(block blockchain.DefaultBlock) error {
+	proposedBlock, err := createProposedBlock(block)
+	if err != nil {
+		return err
+	}
+
+	if err := s.consensus.Propose(proposedBlock); err != nil {
+		logger.Error(nil, fmt.Sprintf("[Blockchain] Fail to propose block - seal: [%x], err: [%s]", block.Seal, err.Error()))
+		return err
+	}
+
+	logger.Info(nil, fmt.Sprintf("[Blockchain] Block has proposed - seal: [%x]", block.Seal))
+
+	return nil
+}
+
+// block 정보 전체를 StartConsensus command 형식으로 직렬화하여 proposed block의 body에 담는다.
+func createProposedBlock(block blockchain.DefaultBlock) (consensus.ProposedBlock, error) {
+	body, err := common.Serialize(createStartConsensusCommand(block))
+	if err != nil {
+		return consensus.ProposedBlock{}, err
+	}
+
+	return consensus.ProposedBlock{
+		Seal: block.Seal,
+		Body: body,
+	}, nil
+}
+
+func createStartConsensusCommand(block blockchain.DefaultBlock) command.StartConsensus {
+	txList := make([]command.Tx, 0)
+
+	for _, tx := range block.TxList {
+		txList = append(txList, command.Tx{
+			ID:        tx.ID,
+			ICodeID:   tx.ICodeID,
+			PeerID:    tx.PeerID,
+			TimeStamp: tx.Timestamp,
+			Jsonrpc:   tx.Jsonrpc,
+			Function:  tx.Function,
+			Args:      tx.Args,
+			Signature: tx.Signature,
+		})
+	}
+
+	return command.StartConsensus{
+		Seal:      block.Seal,
+		PrevSeal:  block.PrevSeal,
+		Height:    block.Height,
+		TxList:    txList,
+		TxSeal:    block.TxSeal,
+		Timestamp: block.Timestamp,
+		Creator:   block.Creator,
+	}
+}
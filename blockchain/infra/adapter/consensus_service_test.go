@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusService_ConsentBlock(t *testing.T) {
+	// given
+	block := mock.GetNewBlock([]byte("genesis"), 1)
+	proposeErr := errors.New("propose failed")
+
+	tests := map[string]struct {
+		input struct {
+			proposeErr error
+		}
+		output struct {
+			err error
+		}
+	}{
+		"success": {
+			input: struct {
+				proposeErr error
+			}{proposeErr: nil},
+			output: struct {
+				err error
+			}{err: nil},
+		},
+		"propose error": {
+			input: struct {
+				proposeErr error
+			}{proposeErr: proposeErr},
+			output: struct {
+				err error
+			}{err: proposeErr},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		proposed := false
+
+		mockConsensus := mock.Consensus{}
+		mockConsensus.ProposeFunc = func(proposedBlock consensus.ProposedBlock) error {
+			proposed = true
+			assert.Equal(t, block.Seal, proposedBlock.Seal)
+
+			startConsensusCommand := command.StartConsensus{}
+			assert.NoError(t, common.Deserialize(proposedBlock.Body, &startConsensusCommand))
+			assert.Equal(t, block.Height, startConsensusCommand.Height)
+			assert.Equal(t, block.PrevSeal, startConsensusCommand.PrevSeal)
+
+			return test.input.proposeErr
+		}
+
+		consensusService := adapter.NewConsensusService(mockConsensus)
+
+		// when
+		err := consensusService.ConsentBlock(*block)
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.True(t, proposed)
+	}
+}
@@ -25,3 +25,4 @@ var ErrTxHasMissingProperties = errors.New("Tx has missing properties")
 var ErrBlockIdNil = errors.New("Error command model ID is nil")
 var ErrTxResultsLengthOfZero = errors.New("Error length of tx results is zero")
 var ErrTxResultsFail = errors.New("Error not all tx results success")
+var ErrSealNotSame = errors.New("Seal of confirmed block is not same with seal in body")
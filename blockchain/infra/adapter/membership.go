@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/consensus"
+)
+
+// 저장된 block에 기록된 membership 변경을 height 순서대로 다시 적용하여 재시작 전의 validator 구성을 복원한다.
+// genesis block의 validator도 같은 방식으로 등록된다.
+// genesis block 이후의 변경은 합의 중에 적용할 때와 같이 그 시점 validator의 과반이 승인한 경우에만 적용한다.
+func RestoreMembership(blockRepository blockchain.BlockRepository, c consensus.Consensus, verifier consensus.ApprovalVerifier) error {
+
+	blocks, err := blockRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Height < blocks[j].Height
+	})
+
+	for _, block := range blocks {
+		for _, tx := range block.TxList {
+			change, ok := consensus.NewMembershipChange(tx.ICodeID, tx.Function, tx.Args, tx.Signature)
+			if !ok {
+				continue
+			}
+
+			if block.Height != 0 {
+				if err := consensus.AuthorizeMembershipChange(change, c.Members(), c.Epoch(), verifier); err != nil {
+					logger.Warn(nil, fmt.Sprintf("[Blockchain] Membership change is not restored - height: [%d], member: [%s], err: [%s]", block.Height, change.MemberID, err.Error()))
+					continue
+				}
+			}
+
+			if err := consensus.ApplyMembershipChange(c, change); err != nil {
+				logger.Error(nil, fmt.Sprintf("[Blockchain] Fail to restore membership - height: [%d], member: [%s], err: [%s]", block.Height, change.MemberID, err.Error()))
+				return err
+			}
+		}
+	}
+
+	logger.Info(nil, fmt.Sprintf("[Blockchain] Membership is restored - members: [%d], epoch: [%d]", len(c.Members()), c.Epoch()))
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/consensus"
+	consensusMock "github.com/it-chain/engine/consensus/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreMembership(t *testing.T) {
+
+	// given
+	membershipTx := func(function string, memberID string, epoch uint64, signerIDs ...string) *blockchain.DefaultTransaction {
+		change := consensus.MembershipChange{Function: function, MemberID: memberID, Epoch: epoch}
+		return &blockchain.DefaultTransaction{
+			ICodeID:   consensus.MembershipICodeID,
+			Function:  function,
+			Args:      consensus.MembershipArgs(memberID, epoch),
+			Signature: consensusMock.ApprovalSignature(change, signerIDs...),
+		}
+	}
+
+	// genesis block의 변경은 approval 없이 적용하고, 그 이후의 변경은 그 epoch에서 validator 과반이 승인한 경우에만 적용한다.
+	blocks := []blockchain.DefaultBlock{
+		{
+			Height: 3,
+			TxList: []*blockchain.DefaultTransaction{
+				membershipTx(consensus.Join, "peer1", 2, "peer2"),
+			},
+		},
+		{
+			Height: 2,
+			TxList: []*blockchain.DefaultTransaction{
+				membershipTx(consensus.Join, "peer3", 3, "peer3"),
+			},
+		},
+		{
+			Height: 1,
+			TxList: []*blockchain.DefaultTransaction{
+				membershipTx(consensus.Leave, "peer1", 2, "peer1", "peer2"),
+				{ICodeID: "ICodeID", Function: consensus.Join, Args: []string{"peer4"}},
+			},
+		},
+		{
+			Height: 0,
+			TxList: []*blockchain.DefaultTransaction{
+				membershipTx(consensus.Join, "peer1", 0),
+				membershipTx(consensus.Join, "peer2", 0),
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		input struct {
+			blocks  []blockchain.DefaultBlock
+			findErr error
+		}
+		output struct {
+			changes []string
+			err     error
+		}
+	}{
+		"restore in height order": {
+			input: struct {
+				blocks  []blockchain.DefaultBlock
+				findErr error
+			}{blocks: blocks},
+			output: struct {
+				changes []string
+				err     error
+			}{changes: []string{"join peer1", "join peer2", "leave peer1"}},
+		},
+		"fail to find blocks": {
+			input: struct {
+				blocks  []blockchain.DefaultBlock
+				findErr error
+			}{findErr: errors.New("find error")},
+			output: struct {
+				changes []string
+				err     error
+			}{changes: []string{}, err: errors.New("find error")},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		changes := make([]string, 0)
+		members := make([]string, 0)
+		epoch := uint64(0)
+
+		blockRepository := mock.BlockRepository{}
+		blockRepository.FindAllFunc = func() ([]blockchain.DefaultBlock, error) {
+			return test.input.blocks, test.input.findErr
+		}
+
+		c := mock.Consensus{}
+		c.AddMemberFunc = func(memberID string) error {
+			changes = append(changes, "join "+memberID)
+			members = append(members, memberID)
+			epoch++
+			return nil
+		}
+		c.RemoveMemberFunc = func(memberID string) error {
+			changes = append(changes, "leave "+memberID)
+			remained := make([]string, 0)
+			for _, m := range members {
+				if m != memberID {
+					remained = append(remained, m)
+				}
+			}
+			members = remained
+			epoch++
+			return nil
+		}
+		c.MembersFunc = func() []string {
+			return members
+		}
+		c.EpochFunc = func() uint64 {
+			return epoch
+		}
+
+		// when
+		err := adapter.RestoreMembership(blockRepository, c, consensusMock.ApprovalVerifier{})
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.Equal(t, test.output.changes, changes)
+	}
+}
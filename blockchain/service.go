@@ -19,3 +19,8 @@ package blockchain
 type EventService interface {
 	Publish(topic string, event interface{}) error
 }
+
+// 생성된 block에 대한 합의를 consensus component에 요청한다.
+type ConsensusService interface {
+	ConsentBlock(block DefaultBlock) error
+}
@@ -24,7 +24,8 @@ import (
 type BlockApi struct {
 	AddBlockToPoolFunc            func(block blockchain.Block) error
 	CheckAndSaveBlockFromPoolFunc func(height blockchain.BlockHeight) error
-	CommitProposedBlockFunc       func(txList []*blockchain.DefaultTransaction) error
+	CreateProposedBlockFunc       func(txList []*blockchain.DefaultTransaction) (blockchain.DefaultBlock, error)
+	CommitConfirmedBlockFunc      func(block blockchain.DefaultBlock) error
 }
 
 func (api BlockApi) AddBlockToPool(block blockchain.Block) error {
@@ -35,8 +36,12 @@ func (api BlockApi) CheckAndSaveBlockFromPool(height blockchain.BlockHeight) err
 	return api.CheckAndSaveBlockFromPoolFunc(height)
 }
 
-func (api BlockApi) CommitProposedBlock(txList []*blockchain.DefaultTransaction) error {
-	return api.CommitProposedBlockFunc(txList)
+func (api BlockApi) CreateProposedBlock(txList []*blockchain.DefaultTransaction) (blockchain.DefaultBlock, error) {
+	return api.CreateProposedBlockFunc(txList)
+}
+
+func (api BlockApi) CommitConfirmedBlock(block blockchain.DefaultBlock) error {
+	return api.CommitConfirmedBlockFunc(block)
 }
 
 type MockSyncBlockApi struct {
@@ -15,7 +15,10 @@
  */
 package mock
 
-import "github.com/it-chain/engine/blockchain"
+import (
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/consensus"
+)
 
 type BlockQueryService struct {
 	GetStagedBlockByHeightFunc   func(height blockchain.BlockHeight) (blockchain.DefaultBlock, error)
@@ -72,3 +75,49 @@ type EventService struct {
 func (s EventService) Publish(topic string, event interface{}) error {
 	return s.PublishFunc(topic, event)
 }
+
+type ConsensusService struct {
+	ConsentBlockFunc func(block blockchain.DefaultBlock) error
+}
+
+func (s ConsensusService) ConsentBlock(block blockchain.DefaultBlock) error {
+	return s.ConsentBlockFunc(block)
+}
+
+type Consensus struct {
+	ProposeFunc      func(block consensus.ProposedBlock) error
+	OnDeliverFunc    func(handler consensus.DeliverHandler)
+	IsProposerFunc   func() bool
+	AddMemberFunc    func(memberID string) error
+	RemoveMemberFunc func(memberID string) error
+	MembersFunc      func() []string
+	EpochFunc        func() uint64
+}
+
+func (c Consensus) Propose(block consensus.ProposedBlock) error {
+	return c.ProposeFunc(block)
+}
+
+func (c Consensus) OnDeliver(handler consensus.DeliverHandler) {
+	c.OnDeliverFunc(handler)
+}
+
+func (c Consensus) IsProposer() bool {
+	return c.IsProposerFunc()
+}
+
+func (c Consensus) AddMember(memberID string) error {
+	return c.AddMemberFunc(memberID)
+}
+
+func (c Consensus) RemoveMember(memberID string) error {
+	return c.RemoveMemberFunc(memberID)
+}
+
+func (c Consensus) Members() []string {
+	return c.MembersFunc()
+}
+
+func (c Consensus) Epoch() uint64 {
+	return c.EpochFunc()
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package membership
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/conf"
+	"github.com/it-chain/engine/consensus"
+	consensusAdapter "github.com/it-chain/engine/consensus/infra/adapter"
+	grpcGatewayInfra "github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/urfave/cli"
+)
+
+// validator는 자신의 node key로 membership 변경을 승인하고, 출력된 approval을 변경을 제출할 node에게 전달한다.
+// epoch는 node가 membership 변경을 적용할 때 log로 남기는 현재 epoch이다.
+func ApproveCmd() cli.Command {
+	return cli.Command{
+		Name:  "approve",
+		Usage: "it-chain membership approve [join|leave] [member id] [epoch]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 3 {
+				return errors.New("not enough args")
+			}
+
+			return approve(c.Args().Get(0), c.Args().Get(1), c.Args().Get(2))
+		},
+	}
+}
+
+func approve(function string, memberID string, epoch string) error {
+
+	change, ok := consensus.NewMembershipChange(consensus.MembershipICodeID, function, []string{memberID, epoch}, nil)
+	if !ok {
+		return consensus.ErrInvalidMembershipChange
+	}
+
+	config := conf.GetConfiguration()
+	priKey, _ := grpcGatewayInfra.LoadKeyPair(config.Engine.KeyPath, "ECDSA256")
+
+	signer, err := consensusAdapter.NewECDSAApprovalSigner(priKey)
+	if err != nil {
+		return err
+	}
+
+	approval, err := signer.Approve(change)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encodeApproval(approval)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof(nil, "[Cmd] Membership change is approved - function: [%s], member: [%s], epoch: [%d]", function, memberID, change.Epoch)
+	fmt.Println(encoded)
+
+	return nil
+}
+
+func encodeApproval(approval consensus.Approval) (string, error) {
+
+	b, err := json.Marshal(approval)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func decodeApproval(encoded string) (consensus.Approval, error) {
+
+	approval := consensus.Approval{}
+
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return approval, err
+	}
+
+	err = json.Unmarshal(b, &approval)
+
+	return approval, err
+}
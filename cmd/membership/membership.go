@@ -0,0 +1,31 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package membership
+
+import "github.com/urfave/cli"
+
+var membershipCmd = cli.Command{
+	Name:        "membership",
+	Usage:       "options for validator membership",
+	Subcommands: []cli.Command{},
+}
+
+func MembershipCmd() cli.Command {
+	membershipCmd.Subcommands = append(membershipCmd.Subcommands, ApproveCmd())
+	membershipCmd.Subcommands = append(membershipCmd.Subcommands, SubmitCmd())
+	return membershipCmd
+}
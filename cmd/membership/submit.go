@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package membership
+
+import (
+	"errors"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/conf"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/txpool"
+	"github.com/rs/xid"
+	"github.com/urfave/cli"
+)
+
+// 현재 validator 과반의 approval을 모아 membership 변경 transaction을 만든다.
+func SubmitCmd() cli.Command {
+	return cli.Command{
+		Name:  "submit",
+		Usage: "it-chain membership submit [join|leave] [member id] [epoch] [...approvals]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 4 {
+				return errors.New("not enough args")
+			}
+
+			approvals := make([]consensus.Approval, 0)
+			for i := 3; i < c.NArg(); i++ {
+				approval, err := decodeApproval(c.Args().Get(i))
+				if err != nil {
+					return err
+				}
+
+				approvals = append(approvals, approval)
+			}
+
+			return submit(c.Args().Get(0), c.Args().Get(1), c.Args().Get(2), approvals)
+		},
+	}
+}
+
+func submit(function string, memberID string, epoch string, approvals []consensus.Approval) error {
+
+	change, ok := consensus.NewMembershipChange(consensus.MembershipICodeID, function, []string{memberID, epoch}, nil)
+	if !ok {
+		return consensus.ErrInvalidMembershipChange
+	}
+
+	signature, err := consensus.EncodeApprovals(approvals)
+	if err != nil {
+		return err
+	}
+
+	config := conf.GetConfiguration()
+	client := rpc.NewClient(config.Engine.Amqp)
+
+	defer client.Close()
+
+	createCommand := command.CreateTransaction{
+		TransactionId: xid.New().String(),
+		ICodeID:       consensus.MembershipICodeID,
+		Jsonrpc:       "2.0",
+		Method:        "invoke",
+		Function:      function,
+		Args:          consensus.MembershipArgs(change.MemberID, change.Epoch),
+		Signature:     signature,
+	}
+
+	logger.Infof(nil, "[Cmd] Submit membership change - function: [%s], member: [%s], epoch: [%d], approvals: [%d]", function, memberID, change.Epoch, len(approvals))
+
+	err = client.Call("transaction.create", createCommand, func(transaction txpool.Transaction, err rpc.Error) {
+
+		if !err.IsNil() {
+			logger.Errorf(nil, "[Cmd] Fail to submit membership change - err: [%s]", err.Message)
+			return
+		}
+
+		logger.Infof(nil, "[Cmd] Transaction is created - ID: [%s]", transaction.ID)
+	})
+
+	if err != nil {
+		logger.Fatal(&logger.Fields{"err_msg": err.Error()}, "fatal err in membership submit cmd")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/conf"
+	"github.com/it-chain/engine/p2p"
+	"github.com/urfave/cli"
+)
+
+func BanListCmd() cli.Command {
+	return cli.Command{
+		Name:  "bans",
+		Usage: "it-chain peer bans",
+		Action: func(c *cli.Context) error {
+			listBans()
+			return nil
+		},
+	}
+}
+
+func UnbanCmd() cli.Command {
+	return cli.Command{
+		Name:  "unban",
+		Usage: "it-chain peer unban [peer id] (clear every ban without peer id)",
+		Action: func(c *cli.Context) error {
+			unban(c.Args().Get(0))
+			return nil
+		},
+	}
+}
+
+func listBans() {
+
+	config := conf.GetConfiguration()
+	client := rpc.NewClient(config.Engine.Amqp)
+
+	defer client.Close()
+
+	err := client.Call("peer.ban.list", command.ListBans{}, func(bans []p2p.Ban, err rpc.Error) {
+		if !err.IsNil() {
+			logger.Errorf(nil, "[Cmd] Fail to list bans - err: [%s]", err.Message)
+			return
+		}
+
+		if len(bans) == 0 {
+			logger.Info(nil, "[Cmd] No banned peer")
+			return
+		}
+
+		for _, ban := range bans {
+			logger.Infof(nil, "[Cmd] Banned peer - peer: [%s], reason: [%s], until: [%s]", ban.PeerId, ban.Reason, ban.Until)
+		}
+	})
+
+	if err != nil {
+		logger.Fatal(&logger.Fields{"err_msg": err.Error()}, "fatal err in peer bans cmd")
+	}
+}
+
+func unban(peerId string) {
+
+	config := conf.GetConfiguration()
+	client := rpc.NewClient(config.Engine.Amqp)
+
+	defer client.Close()
+
+	err := client.Call("peer.ban.clear", command.ClearBan{PeerId: peerId}, func(_ struct{}, err rpc.Error) {
+		if !err.IsNil() {
+			logger.Errorf(nil, "[Cmd] Fail to unban - peer: [%s], err: [%s]", peerId, err.Message)
+			return
+		}
+
+		if peerId == "" {
+			logger.Info(nil, "[Cmd] Every ban cleared")
+			return
+		}
+
+		logger.Infof(nil, "[Cmd] Peer unbanned - peer: [%s]", peerId)
+	})
+
+	if err != nil {
+		logger.Fatal(&logger.Fields{"err_msg": err.Error()}, "fatal err in peer unban cmd")
+	}
+}
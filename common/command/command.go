@@ -24,10 +24,16 @@ import (
  * Consensus - pbft
  */
 
-// Blockchain이 consensus를 요청하는 command
+// Blockchain이 consensus에 제안하는 block의 형식
+// 직렬화되어 consensus.ProposedBlock의 Body에 담기며, 합의가 끝난 뒤 모든 replica가 같은 block을 commit 할 수 있도록 block 정보를 모두 담는다.
 type StartConsensus struct {
-	Seal   []byte
-	TxList []Tx
+	Seal      []byte
+	PrevSeal  []byte
+	Height    uint64
+	TxList    []Tx
+	TxSeal    [][]byte
+	Timestamp time.Time
+	Creator   []byte
 }
 
 /*
@@ -41,15 +47,29 @@ type CreateConnection struct {
 
 //Connection close command
 type CloseConnection struct {
-	Address string
+	Address      string
+	ConnectionID string
 }
 
+//connection 별 상태(connecting, up, backing off) 조회 command
+type ListConnectionStates struct{}
+
 //다른 Peer에게 Message전송 command
+//Ack이면 모든 recipient가 message를 받았다고 알리기를 기다려 실패한 recipient를 rpc error로 알린다.
 type DeliverGrpc struct {
 	MessageId     string
 	RecipientList []string
 	Body          []byte
 	Protocol      string
+	Ack           bool
+}
+
+//네트워크 전체에 gossip으로 Message전송 command
+//p2p component가 fanout 만큼의 peer에게 전달하고, 받은 peer가 TTL이 남아있는 동안 다시 전달한다.
+type GossipGrpc struct {
+	MessageId string
+	Body      []byte
+	Protocol  string
 }
 
 //다른 Peer에게 Message수신 command
@@ -60,6 +80,25 @@ type ReceiveGrpc struct {
 	Protocol     string
 }
 
+/*
+ * p2p
+ */
+
+//규칙을 어긴 peer를 p2p component에 알리는 command
+//Violation은 p2p.MalformedMessage, p2p.InvalidSignature, p2p.BadBlock 등이다.
+type ReportPeer struct {
+	PeerId    string
+	Violation string
+}
+
+//ban 된 peer 목록 조회 command
+type ListBans struct{}
+
+//ban 해제 command. PeerId가 비어있으면 모든 ban을 해제한다.
+type ClearBan struct {
+	PeerId string
+}
+
 /*
  * ivm
  */
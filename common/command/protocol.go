@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+// 다른 node로 부터 받은 message는 protocol을 처리하는 component의 topic으로 publish 된다.
+// 각 component는 자신의 protocol만 구독한다. ex) message.receive.p2p.*, message.receive.consensus.*
+const (
+	P2PComponent        = "p2p"
+	ConsensusComponent  = "consensus"
+	TxpoolComponent     = "txpool"
+	BlockchainComponent = "blockchain"
+	UnknownComponent    = "unknown"
+)
+
+var protocolComponents = map[string]string{
+	// p2p
+	"HandshakeProtocol":          P2PComponent,
+	"PLTableDeliverProtocol":     P2PComponent,
+	"LeaderInfoRequestProtocol":  P2PComponent,
+	"LeaderInfoResponseProtocol": P2PComponent,
+	"PeerListRequestProtocol":    P2PComponent,
+	"PeerListResponseProtocol":   P2PComponent,
+	"RequestVoteProtocol":        P2PComponent,
+	"VoteLeaderProtocol":         P2PComponent,
+	"UpdateLeaderProtocol":       P2PComponent,
+	"HeartbeatProtocol":          P2PComponent,
+	"GossipProtocol":             P2PComponent,
+	"PingProtocol":               P2PComponent,
+	"PongProtocol":               P2PComponent,
+
+	// consensus - pbft
+	"PrePrepareMsgProtocol": ConsensusComponent,
+	"PrepareMsgProtocol":    ConsensusComponent,
+	"CommitMsgProtocol":     ConsensusComponent,
+
+	// consensus - raft
+	"AppendEntriesProtocol":    ConsensusComponent,
+	"AppendEntriesAckProtocol": ConsensusComponent,
+
+	// txpool
+	"SendLeaderTransactionsProtocol": TxpoolComponent,
+
+	// blockchain
+	"BlockProtocol": BlockchainComponent,
+}
+
+// protocol을 처리하는 component. 등록되지 않은 protocol은 UnknownComponent 이다.
+func ComponentOf(protocol string) string {
+
+	component, ok := protocolComponents[protocol]
+
+	if !ok {
+		return UnknownComponent
+	}
+
+	return component
+}
+
+// ReceiveGrpc command를 publish 할 topic
+func ReceiveTopic(protocol string) string {
+	return "message.receive." + ComponentOf(protocol) + "." + protocol
+}
+
+// component의 모든 protocol을 구독하는 topic
+func ReceiveTopicOf(component string) string {
+	return "message.receive." + component + ".*"
+}
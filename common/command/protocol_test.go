@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveTopic(t *testing.T) {
+
+	tests := map[string]struct {
+		input  string
+		output string
+	}{
+		"p2p protocol": {
+			input:  "PingProtocol",
+			output: "message.receive.p2p.PingProtocol",
+		},
+		"consensus protocol": {
+			input:  "PrePrepareMsgProtocol",
+			output: "message.receive.consensus.PrePrepareMsgProtocol",
+		},
+		"unknown protocol": {
+			input:  "testProtocol",
+			output: "message.receive.unknown.testProtocol",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		assert.Equal(t, test.output, command.ReceiveTopic(test.input))
+	}
+}
+
+func TestReceiveTopicOf(t *testing.T) {
+	assert.Equal(t, "message.receive.p2p.*", command.ReceiveTopicOf(command.P2PComponent))
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+
+	"github.com/it-chain/heimdall/key"
+	"github.com/jbenet/go-base58"
+)
+
+var ErrInvalidKeyPEM = errors.New("invalid key pem")
+var ErrNotECDSAKey = errors.New("not ecdsa key")
+var ErrInvalidSignature = errors.New("invalid signature")
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// heimdall private key를 ecdsa key로 바꾸고, 서명과 함께 보낼 PEM public key를 만든다.
+func ParseECDSAPrivateKey(priKey key.PriKey) (*ecdsa.PrivateKey, []byte, error) {
+
+	priPEM, err := priKey.ToPEM()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(priPEM)
+
+	if block == nil {
+		return nil, nil, ErrInvalidKeyPEM
+	}
+
+	ecdsaKey, err := x509.ParseECPrivateKey(block.Bytes)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ecdsaKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+func ParseECDSAPublicKey(pubPEM []byte) (*ecdsa.PublicKey, error) {
+
+	block, _ := pem.Decode(pubPEM)
+
+	if block == nil {
+		return nil, ErrInvalidKeyPEM
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+
+	if !ok {
+		return nil, ErrNotECDSAKey
+	}
+
+	return ecdsaPub, nil
+}
+
+// heimdall과 같은 방식으로 SKI(sha256 of marshaled public key)를 구해 base58로 인코딩한다.
+func NodeIdFromECDSAPublicKey(pubKey *ecdsa.PublicKey) string {
+
+	ski := sha256.Sum256(elliptic.Marshal(pubKey.Curve, pubKey.X, pubKey.Y))
+
+	return base58.Encode(ski[:])
+}
+
+func SignDigest(priKey *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+
+	r, s, err := ecdsa.Sign(rand.Reader, priKey, digest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(ecdsaSignature{R: r, S: s})
+}
+
+// 서명을 검증하고, 서명한 key로부터 만든 node id를 반환한다.
+func VerifyDigest(pubPEM []byte, signature []byte, digest []byte) (string, error) {
+
+	pubKey, err := ParseECDSAPublicKey(pubPEM)
+
+	if err != nil {
+		return "", err
+	}
+
+	sig := ecdsaSignature{}
+
+	if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	if sig.R == nil || sig.S == nil || !ecdsa.Verify(pubKey, digest, sig.R, sig.S) {
+		return "", ErrInvalidSignature
+	}
+
+	return NodeIdFromECDSAPublicKey(pubKey), nil
+}
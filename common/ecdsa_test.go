@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyDigest(t *testing.T) {
+
+	// given
+	priKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&priKey.PublicKey)
+	assert.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	digest := []byte("digest")
+	signature, err := SignDigest(priKey, digest)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input struct {
+			pubPEM    []byte
+			signature []byte
+			digest    []byte
+		}
+		nodeId string
+		err    error
+	}{
+		"valid signature": {
+			input: struct {
+				pubPEM    []byte
+				signature []byte
+				digest    []byte
+			}{pubPEM, signature, digest},
+			nodeId: NodeIdFromECDSAPublicKey(&priKey.PublicKey),
+			err:    nil,
+		},
+		"other digest": {
+			input: struct {
+				pubPEM    []byte
+				signature []byte
+				digest    []byte
+			}{pubPEM, signature, []byte("other")},
+			err: ErrInvalidSignature,
+		},
+		"malformed signature": {
+			input: struct {
+				pubPEM    []byte
+				signature []byte
+				digest    []byte
+			}{pubPEM, []byte("signature"), digest},
+			err: ErrInvalidSignature,
+		},
+		"invalid pem": {
+			input: struct {
+				pubPEM    []byte
+				signature []byte
+				digest    []byte
+			}{[]byte("pem"), signature, digest},
+			err: ErrInvalidKeyPEM,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		nodeId, err := VerifyDigest(test.input.pubPEM, test.input.signature, test.input.digest)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.nodeId, nodeId)
+	}
+}
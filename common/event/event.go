@@ -20,24 +20,6 @@ import (
 	"time"
 )
 
-/*
- * consensus
- */
-
-// todo 블록 정보 가지고 있어야 함
-// consensus가 끝났다는 event
-// true면 블록 저장, false면 블록 저장 안함
-type ConsensusFinished struct {
-	Seal      []byte
-	PrevSeal  []byte
-	Height    uint64
-	TxList    []Tx
-	TxSeal    [][]byte
-	Timestamp time.Time
-	Creator   []byte
-	State     string
-}
-
 /*
  * grpc-gateway
  */
@@ -148,6 +130,7 @@ type PeerDeleted struct {
 // handle leader received event
 type LeaderUpdated struct {
 	LeaderId string
+	Term     uint64
 }
 
 type LeaderDelivered struct {
@@ -160,7 +143,7 @@ type LeaderDeleted struct {
 
 //Connection
 
-// connection 생성
+// connection 생성, ConnectionID는 상대 노드의 public key로 부터 만든 node id 이다.
 type ConnectionCreated struct {
 	ConnectionID string
 	Address      string
@@ -170,3 +153,18 @@ type ConnectionCreated struct {
 type ConnectionClosed struct {
 	ConnectionId string
 }
+
+// 끊어진 connection에 다시 dial 한다. Attempt는 끊어진 후 몇 번째 시도인지 나타낸다.
+type ConnectionReconnecting struct {
+	ConnectionID string
+	Address      string
+	Attempt      int
+}
+
+// 다시 dial 하지 못해 Delay 후에 다시 시도한다.
+type ConnectionBackingOff struct {
+	ConnectionID string
+	Address      string
+	Attempt      int
+	Delay        time.Duration
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/it-chain/engine/conf/model"
+	"github.com/it-chain/engine/consensus"
 	"github.com/spf13/viper" //viper는 go 어플리케이션의 각종 설정을 담당하는 lib이다.
 	// 각종 형태의 설정파일을 찾고, 로드하는 것이 주 역할이다.
 )
@@ -45,13 +46,6 @@ type Configuration struct {
 	ApiGateway  model.ApiGatewayConfiguration
 }
 
-// EngineConfiguration Mode용
-var modeConsts = [...]string{
-	"solo",
-	"test",
-	"pbft",
-}
-
 // it-chain의 각종 설정을 받아온다.
 func SetConfigName(name string) {
 	instance.configName = name
@@ -87,13 +81,7 @@ func GetConfiguration() *Configuration {
 	return instance
 }
 
+// engine mode는 consensus component가 제공하는 합의 방식 중 하나여야 한다.
 func HasValidMode(c *Configuration) bool {
-
-	for _, modeConst := range modeConsts {
-		if instance.Engine.Mode == modeConst {
-			return true
-		}
-	}
-	return false
-
+	return consensus.IsValidMode(c.Engine.Mode)
 }
@@ -53,6 +53,7 @@ func TestGetConfiguration(t *testing.T) {
   consensus:
     batchtime: 3
     maxtransactions: 100
+    statedbpath: .it-chain/consensus/state
   blockchain:
     genesisconfpath: ./Genesis.conf
   peer:
@@ -19,11 +19,15 @@ package model
 type ConsensusConfiguration struct {
 	BatchTime       int
 	MaxTransactions int
+	StateDBPath     string
+	Validators      []string
 }
 
 func NewConsensusConfiguration() ConsensusConfiguration {
 	return ConsensusConfiguration{
 		BatchTime:       3,
 		MaxTransactions: 100,
+		StateDBPath:     ".it-chain/consensus/state",
+		Validators:      []string{},
 	}
 }
@@ -17,13 +17,70 @@
 package model
 
 type GrpcGatewayConfiguration struct {
-	Address string
-	Port    string
+	Address     string
+	Port        string
+	MaxInbound  int
+	MaxOutbound int
+	AllowList   []string
+	DenyList    []string
+	Tls         TlsConfiguration
+	// dial 한 connection이 끊기면 jitter를 더한 exponential backoff로 다시 연결한다.
+	ReconnectInitialBackoffMs int
+	ReconnectMaxBackoffMs     int
+	ReconnectJitter           float64
+	// connection 마다 보낼 message를 SendQueueSize 만큼 쌓아둔다.
+	// SendQueuePolicy는 queue가 가득 찼을 때 block(SendBlockTimeoutMs 동안 기다림), drop-newest, drop-oldest 중 하나이다.
+	SendQueueSize      int
+	SendQueuePolicy    string
+	SendBlockTimeoutMs int
+	AckTimeoutMs       int
+	// 압축하기 전 body의 최대 크기(byte)로 보낼 때와 받을 때 모두 확인한다. 0이면 제한하지 않는다.
+	MaxMessageSize int
+	// protocol 별 압축 방법(none, gzip, snappy)이다. 없는 protocol은 DefaultCompression을 사용한다.
+	Compression        map[string]string
+	DefaultCompression string
+}
+
+// 다른 노드와의 grpc connection에 사용할 TLS 설정이다.
+// 인증서는 node key로 발급하며, Mutual이면 다른 노드가 dial 할 때도 인증서를 요구한다.
+type TlsConfiguration struct {
+	Enabled  bool
+	CertPath string
+	KeyPath  string
+	CaPath   string
+	Mutual   bool
 }
 
 func NewGrpcGatewayConfiguration() GrpcGatewayConfiguration {
 	return GrpcGatewayConfiguration{
-		Address: "127.0.0.1",
-		Port:    "13579",
+		Address:     "127.0.0.1",
+		Port:        "13579",
+		MaxInbound:  64,
+		MaxOutbound: 32,
+		AllowList:   []string{},
+		DenyList:    []string{},
+		Tls: TlsConfiguration{
+			Enabled:  false,
+			CertPath: ".it-chain/tls/node.crt",
+			KeyPath:  ".it-chain/tls/node.key",
+			CaPath:   ".it-chain/tls/ca.crt",
+			Mutual:   true,
+		},
+		ReconnectInitialBackoffMs: 1000,
+		ReconnectMaxBackoffMs:     60000,
+		ReconnectJitter:           0.2,
+		SendQueueSize:             256,
+		SendQueuePolicy:           "block",
+		SendBlockTimeoutMs:        1000,
+		AckTimeoutMs:              5000,
+		MaxMessageSize:            4 * 1024 * 1024,
+		Compression: map[string]string{
+			"PrePrepareMsgProtocol":          "snappy",
+			"AppendEntriesProtocol":          "snappy",
+			"SendLeaderTransactionsProtocol": "snappy",
+			"BlockProtocol":                  "snappy",
+			"PLTableDeliverProtocol":         "gzip",
+		},
+		DefaultCompression: "none",
 	}
 }
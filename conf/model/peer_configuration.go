@@ -17,11 +17,39 @@
 package model
 
 type PeerConfiguration struct {
-	LeaderElection string
+	LeaderElection         string
+	DBPath                 string
+	RedialInitialBackoffMs int
+	RedialMaxBackoffMs     int
+	UnreachableTimeoutSec  int
+	GossipFanout           int
+	GossipTTL              int
+	PingIntervalMs         int
+	MaxMissedPings         int
+	BanThreshold           int
+	BanDurationSec         int
+	RequestTimeoutMs       int
+	TargetPeerCount        int
+	ElectionMinPeers       int
+	NetworkId              string
 }
 
 func NewPeerConfiguration() PeerConfiguration {
 	return PeerConfiguration{
-		LeaderElection: "RAFT",
+		LeaderElection:         "RAFT",
+		DBPath:                 ".it-chain/p2p/peer",
+		RedialInitialBackoffMs: 1000,
+		RedialMaxBackoffMs:     60000,
+		UnreachableTimeoutSec:  86400,
+		GossipFanout:           3,
+		GossipTTL:              6,
+		PingIntervalMs:         1000,
+		MaxMissedPings:         3,
+		BanThreshold:           0,
+		BanDurationSec:         3600,
+		RequestTimeoutMs:       3000,
+		TargetPeerCount:        16,
+		ElectionMinPeers:       1,
+		NetworkId:              "Default",
 	}
 }
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/it-chain/engine/common/command"
+)
+
+var ErrUnknownMode = errors.New("Unknown consensus mode")
+var ErrInvalidBlockBody = errors.New("Body of proposed block is invalid")
+var ErrSealNotSame = errors.New("Seal of proposed block is not same with seal in body")
+
+// engine mode로 선택할 수 있는 합의 방식
+const (
+	Solo = "solo"
+	Pbft = "pbft"
+	Raft = "raft"
+)
+
+var modes = []string{Solo, Pbft, Raft}
+
+func IsValidMode(mode string) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 합의에 올라가는 block이다.
+// Body는 각 합의 방식이 해석하지 않고 그대로 전달하며, block을 만든 component가 복원한다.
+type ProposedBlock struct {
+	Seal []byte
+	Body []byte
+}
+
+// block의 body는 blockchain component가 직렬화한 command.StartConsensus 이다.
+// body는 leader가 보낸 값이므로 잘못된 body에 panic 하지 않고 error를 반환한다.
+func DecodeBlockBody(block ProposedBlock) (command.StartConsensus, error) {
+
+	startConsensusCommand := command.StartConsensus{}
+	if len(block.Body) == 0 || json.Unmarshal(block.Body, &startConsensusCommand) != nil {
+		return command.StartConsensus{}, ErrInvalidBlockBody
+	}
+
+	if !bytes.Equal(block.Seal, startConsensusCommand.Seal) {
+		return command.StartConsensus{}, ErrSealNotSame
+	}
+
+	return startConsensusCommand, nil
+}
+
+// 합의가 끝난 block을 전달받는 hook이다.
+type DeliverHandler func(block ProposedBlock) error
+
+// blockchain, txpool component가 의존하는 합의 방식의 interface이다.
+// solo, pbft, raft가 이 interface를 구현하며, engine mode에 따라 하나가 선택된다.
+type Consensus interface {
+	// block을 합의에 올린다. 합의가 끝난 block은 OnDeliver로 등록된 hook으로 전달된다.
+	Propose(block ProposedBlock) error
+
+	// 합의가 끝난 block을 합의된 순서대로 전달받을 hook을 등록한다.
+	OnDeliver(handler DeliverHandler)
+
+	// 이 node가 block을 제안할 수 있는지 확인한다.
+	IsProposer() bool
+
+	// 합의에 참여하는 member가 추가되거나 빠졌을 때 호출된다. 호출될 때마다 epoch가 1 증가한다.
+	AddMember(memberID string) error
+	RemoveMember(memberID string) error
+
+	// 현재 합의에 참여하는 member 목록을 반환한다. membership 변경은 이 member들의 과반이 승인해야 한다.
+	Members() []string
+
+	// 지금까지 적용된 membership 변경의 수를 반환한다. membership 변경은 이 epoch에서 승인되어야 한다.
+	Epoch() uint64
+}
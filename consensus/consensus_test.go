@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBlockBody(t *testing.T) {
+
+	body, err := common.Serialize(command.StartConsensus{Seal: []byte("seal"), Height: 3})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input  consensus.ProposedBlock
+		output struct {
+			height uint64
+			err    error
+		}
+	}{
+		"valid body": {
+			input: consensus.ProposedBlock{Seal: []byte("seal"), Body: body},
+			output: struct {
+				height uint64
+				err    error
+			}{height: 3, err: nil},
+		},
+		"empty body": {
+			input: consensus.ProposedBlock{Seal: []byte("seal")},
+			output: struct {
+				height uint64
+				err    error
+			}{height: 0, err: consensus.ErrInvalidBlockBody},
+		},
+		"malformed body": {
+			input: consensus.ProposedBlock{Seal: []byte("seal"), Body: []byte("{")},
+			output: struct {
+				height uint64
+				err    error
+			}{height: 0, err: consensus.ErrInvalidBlockBody},
+		},
+		"seal not same with body": {
+			input: consensus.ProposedBlock{Seal: []byte("other seal"), Body: body},
+			output: struct {
+				height uint64
+				err    error
+			}{height: 0, err: consensus.ErrSealNotSame},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		startConsensusCommand, err := consensus.DecodeBlockBody(test.input)
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.Equal(t, test.output.height, startConsensusCommand.Height)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/heimdall/key"
+)
+
+var ErrInvalidApproval = errors.New("Invalid membership approval")
+
+// validator node의 heimdall private key로 membership 변경을 승인한다.
+type ECDSAApprovalSigner struct {
+	priKey *ecdsa.PrivateKey
+	pubPEM []byte
+}
+
+func NewECDSAApprovalSigner(priKey key.PriKey) (*ECDSAApprovalSigner, error) {
+
+	ecdsaKey, pubPEM, err := common.ParseECDSAPrivateKey(priKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECDSAApprovalSigner{
+		priKey: ecdsaKey,
+		pubPEM: pubPEM,
+	}, nil
+}
+
+func (s *ECDSAApprovalSigner) Approve(change consensus.MembershipChange) (consensus.Approval, error) {
+
+	signature, err := common.SignDigest(s.priKey, change.Digest())
+
+	if err != nil {
+		return consensus.Approval{}, err
+	}
+
+	return consensus.Approval{
+		PubKey:    s.pubPEM,
+		Signature: signature,
+	}, nil
+}
+
+// approval의 서명을 검증하고, 서명한 key로부터 만든 node id를 반환한다.
+type ECDSAApprovalVerifier struct{}
+
+func NewECDSAApprovalVerifier() *ECDSAApprovalVerifier {
+	return &ECDSAApprovalVerifier{}
+}
+
+func (v *ECDSAApprovalVerifier) Verify(digest []byte, approval consensus.Approval) (string, error) {
+
+	nodeId, err := common.VerifyDigest(approval.PubKey, approval.Signature, digest)
+
+	if err != nil {
+		return "", ErrInvalidApproval
+	}
+
+	return nodeId, nil
+}
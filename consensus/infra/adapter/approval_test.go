@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/infra/adapter"
+	"github.com/it-chain/heimdall/key"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePriKey struct {
+	priKey *ecdsa.PrivateKey
+}
+
+func (k fakePriKey) SKI() []byte                  { return nil }
+func (fakePriKey) Algorithm() key.KeyGenOpts      { return key.ECDSA256 }
+func (fakePriKey) Type() key.KeyType              { return "" }
+func (fakePriKey) PublicKey() (key.PubKey, error) { return nil, nil }
+func (k fakePriKey) ToPEM() ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(k.priKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSignerWithNodeId(t *testing.T) (*adapter.ECDSAApprovalSigner, string) {
+
+	priKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := adapter.NewECDSAApprovalSigner(fakePriKey{priKey: priKey})
+	assert.NoError(t, err)
+
+	return signer, common.NodeIdFromECDSAPublicKey(&priKey.PublicKey)
+}
+
+func TestECDSAApprovalVerifier_Verify(t *testing.T) {
+
+	// given
+	signer, nodeId := newSignerWithNodeId(t)
+	otherSigner, _ := newSignerWithNodeId(t)
+
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "node1"}
+	leave := consensus.MembershipChange{Function: consensus.Leave, MemberID: "node1"}
+
+	approval, err := signer.Approve(join)
+	assert.NoError(t, err)
+
+	otherApproval, err := otherSigner.Approve(join)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input struct {
+			change   consensus.MembershipChange
+			approval consensus.Approval
+		}
+		output struct {
+			signerID string
+			err      error
+		}
+	}{
+		"approved change": {
+			input: struct {
+				change   consensus.MembershipChange
+				approval consensus.Approval
+			}{join, approval},
+			output: struct {
+				signerID string
+				err      error
+			}{nodeId, nil},
+		},
+		"approval of other change": {
+			input: struct {
+				change   consensus.MembershipChange
+				approval consensus.Approval
+			}{leave, approval},
+			output: struct {
+				signerID string
+				err      error
+			}{"", adapter.ErrInvalidApproval},
+		},
+		"signature of other node": {
+			input: struct {
+				change   consensus.MembershipChange
+				approval consensus.Approval
+			}{join, consensus.Approval{PubKey: approval.PubKey, Signature: otherApproval.Signature}},
+			output: struct {
+				signerID string
+				err      error
+			}{"", adapter.ErrInvalidApproval},
+		},
+		"not signed": {
+			input: struct {
+				change   consensus.MembershipChange
+				approval consensus.Approval
+			}{join, consensus.Approval{PubKey: approval.PubKey}},
+			output: struct {
+				signerID string
+				err      error
+			}{"", adapter.ErrInvalidApproval},
+		},
+	}
+
+	verifier := adapter.NewECDSAApprovalVerifier()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		signerID, err := verifier.Verify(test.input.change.Digest(), test.input.approval)
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.Equal(t, test.output.signerID, signerID)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/it-chain/engine/common/command"
+)
+
+var ErrInvalidMembershipChange = errors.New("Invalid membership change")
+var ErrMembershipNotApproved = errors.New("Membership change is not approved by a majority of validators")
+var ErrStaleMembershipChange = errors.New("Membership change is approved for another epoch")
+
+// validator의 참여와 탈퇴는 이 icode id를 가진 transaction으로 block에 기록된다.
+// 변경은 그 block이 확정된 뒤, 다음 block의 합의부터 적용된다.
+const MembershipICodeID = "consensus.membership"
+
+const (
+	Join  = "join"
+	Leave = "leave"
+)
+
+// Epoch는 변경을 승인할 때의 validator 구성이다. 승인된 변경은 같은 epoch에서 한 번만 적용될 수 있다.
+type MembershipChange struct {
+	Function  string
+	MemberID  string
+	Epoch     uint64
+	Approvals []Approval
+}
+
+// validator가 membership 변경을 승인한 서명이다.
+// PubKey는 validator node key의 PEM이며, 서명한 validator의 id는 이 key로부터 만든다.
+type Approval struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// approval의 서명을 검증하고 서명한 node의 id를 반환한다.
+type ApprovalVerifier interface {
+	Verify(digest []byte, approval Approval) (string, error)
+}
+
+// transaction이 membership 변경이면 변경 내용을 반환한다.
+// args는 member id와 승인한 epoch이며, 변경을 승인한 approval 목록은 transaction의 signature에 담긴다.
+// genesis block의 변경은 승인을 확인하지 않으므로 epoch 없이 기록되며 epoch 0으로 본다.
+func NewMembershipChange(iCodeID string, function string, args []string, signature []byte) (MembershipChange, bool) {
+	if iCodeID != MembershipICodeID {
+		return MembershipChange{}, false
+	}
+
+	if (function != Join && function != Leave) || len(args) < 1 || len(args) > 2 || args[0] == "" {
+		return MembershipChange{}, false
+	}
+
+	epoch := uint64(0)
+	if len(args) == 2 {
+		parsed, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return MembershipChange{}, false
+		}
+
+		epoch = parsed
+	}
+
+	return MembershipChange{
+		Function:  function,
+		MemberID:  args[0],
+		Epoch:     epoch,
+		Approvals: DecodeApprovals(signature),
+	}, true
+}
+
+// membership 변경 transaction의 args
+func MembershipArgs(memberID string, epoch uint64) []string {
+	return []string{memberID, strconv.FormatUint(epoch, 10)}
+}
+
+// validator가 서명하는 변경 내용
+// epoch를 포함하므로 이전 epoch에서 받은 approval로 같은 변경을 다시 적용할 수 없다.
+func (c MembershipChange) Digest() []byte {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s:%d", MembershipICodeID, c.Function, c.MemberID, c.Epoch)))
+	return digest[:]
+}
+
+func EncodeApprovals(approvals []Approval) ([]byte, error) {
+	return json.Marshal(approvals)
+}
+
+// 잘못된 signature는 approval이 없는 것으로 본다.
+func DecodeApprovals(signature []byte) []Approval {
+	if len(signature) == 0 {
+		return nil
+	}
+
+	approvals := make([]Approval, 0)
+	if err := json.Unmarshal(signature, &approvals); err != nil {
+		return nil
+	}
+
+	return approvals
+}
+
+// 현재 epoch에서 현재 validator의 과반이 승인한 변경인지 확인한다.
+// validator 중 f명이 byzantine 이더라도 과반에는 정직한 validator가 포함된다.
+func AuthorizeMembershipChange(change MembershipChange, validators []string, epoch uint64, verifier ApprovalVerifier) error {
+	if change.Epoch != epoch {
+		return ErrStaleMembershipChange
+	}
+
+	isValidator := make(map[string]bool)
+	for _, v := range validators {
+		isValidator[v] = true
+	}
+
+	approved := make(map[string]struct{})
+	for _, approval := range change.Approvals {
+		signerID, err := verifier.Verify(change.Digest(), approval)
+		if err != nil || !isValidator[signerID] {
+			continue
+		}
+
+		approved[signerID] = struct{}{}
+	}
+
+	if len(approved) < len(validators)/2+1 {
+		return ErrMembershipNotApproved
+	}
+
+	return nil
+}
+
+// 확정된 block의 transaction 중 membership 변경을 순서대로 반환한다.
+// block의 body는 blockchain component가 직렬화한 command.StartConsensus 이다.
+func MembershipChangesOf(block ProposedBlock) ([]MembershipChange, error) {
+
+	// common.Deserialize는 잘못된 body에 panic 하므로 직접 decode 한다.
+	startConsensusCommand := command.StartConsensus{}
+	if err := json.Unmarshal(block.Body, &startConsensusCommand); err != nil {
+		return nil, err
+	}
+
+	changes := make([]MembershipChange, 0)
+	for _, tx := range startConsensusCommand.TxList {
+		change, ok := NewMembershipChange(tx.ICodeID, tx.Function, tx.Args, tx.Signature)
+		if !ok {
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// membership 변경을 합의 방식의 membership hook으로 전달한다.
+// genesis block의 validator와 같이 이미 승인된 변경에만 사용한다.
+func ApplyMembershipChange(c Consensus, change MembershipChange) error {
+	switch change.Function {
+	case Join:
+		return c.AddMember(change.MemberID)
+	case Leave:
+		return c.RemoveMember(change.MemberID)
+	default:
+		return ErrInvalidMembershipChange
+	}
+}
+
+// 현재 validator의 과반이 승인한 경우에만 membership 변경을 적용한다.
+func ApplyApprovedMembershipChange(c Consensus, change MembershipChange, verifier ApprovalVerifier) error {
+	if err := AuthorizeMembershipChange(change, c.Members(), c.Epoch(), verifier); err != nil {
+		return err
+	}
+
+	return ApplyMembershipChange(c, change)
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMembershipChange(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			iCodeID  string
+			function string
+			args     []string
+		}
+		output struct {
+			change consensus.MembershipChange
+			ok     bool
+		}
+	}{
+		"join": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, consensus.Join, []string{"node1"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{Function: consensus.Join, MemberID: "node1"}, true},
+		},
+		"leave": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, consensus.Leave, []string{"node1"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{Function: consensus.Leave, MemberID: "node1"}, true},
+		},
+		"join with epoch": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, consensus.Join, []string{"node1", "3"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{Function: consensus.Join, MemberID: "node1", Epoch: 3}, true},
+		},
+		"invalid epoch": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, consensus.Join, []string{"node1", "epoch"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{}, false},
+		},
+		"other icode": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{"icode1", consensus.Join, []string{"node1"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{}, false},
+		},
+		"unknown function": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, "kick", []string{"node1"}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{}, false},
+		},
+		"no member id": {
+			input: struct {
+				iCodeID  string
+				function string
+				args     []string
+			}{consensus.MembershipICodeID, consensus.Join, []string{}},
+			output: struct {
+				change consensus.MembershipChange
+				ok     bool
+			}{consensus.MembershipChange{}, false},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		change, ok := consensus.NewMembershipChange(test.input.iCodeID, test.input.function, test.input.args, nil)
+
+		// then
+		assert.Equal(t, test.output.ok, ok)
+		assert.Equal(t, test.output.change, change)
+	}
+}
+
+func TestMembershipChangesOf(t *testing.T) {
+
+	// given
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "node1"}
+	signature := mock.ApprovalSignature(join, "v1")
+
+	body, err := common.Serialize(command.StartConsensus{
+		Seal: []byte("seal"),
+		TxList: []command.Tx{
+			{ID: "tx1", ICodeID: "icode1", Function: "invoke"},
+			{ID: "tx2", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"node1"}, Signature: signature},
+			{ID: "tx3", ICodeID: consensus.MembershipICodeID, Function: consensus.Leave, Args: []string{"node2"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	// when
+	changes, err := consensus.MembershipChangesOf(consensus.ProposedBlock{Seal: []byte("seal"), Body: body})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []consensus.MembershipChange{
+		{Function: consensus.Join, MemberID: "node1", Approvals: consensus.DecodeApprovals(signature)},
+		{Function: consensus.Leave, MemberID: "node2"},
+	}, changes)
+
+	// when : body가 StartConsensus가 아닌 경우
+	_, err = consensus.MembershipChangesOf(consensus.ProposedBlock{Seal: []byte("seal"), Body: []byte("body")})
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestAuthorizeMembershipChange(t *testing.T) {
+
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "node1", Epoch: 3}
+	leave := consensus.MembershipChange{Function: consensus.Leave, MemberID: "node1", Epoch: 3}
+	staleJoin := consensus.MembershipChange{Function: consensus.Join, MemberID: "node1", Epoch: 2}
+	validators := []string{"v1", "v2", "v3", "v4"}
+
+	tests := map[string]struct {
+		input struct {
+			epoch     uint64
+			signature []byte
+		}
+		err error
+	}{
+		"approved by a majority": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(join, "v1", "v2", "v3")},
+			err: nil,
+		},
+		"approved by a half": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(join, "v1", "v2")},
+			err: consensus.ErrMembershipNotApproved,
+		},
+		"approved by a non validator": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(join, "v1", "v2", "node1")},
+			err: consensus.ErrMembershipNotApproved,
+		},
+		"approved twice by the same validator": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(join, "v1", "v2", "v2")},
+			err: consensus.ErrMembershipNotApproved,
+		},
+		"approvals of other change": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(leave, "v1", "v2", "v3")},
+			err: consensus.ErrMembershipNotApproved,
+		},
+		"approvals of previous epoch": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, mock.ApprovalSignature(staleJoin, "v1", "v2", "v3")},
+			err: consensus.ErrMembershipNotApproved,
+		},
+		"change of previous epoch": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{2, mock.ApprovalSignature(staleJoin, "v1", "v2", "v3")},
+			err: consensus.ErrStaleMembershipChange,
+		},
+		"no approval": {
+			input: struct {
+				epoch     uint64
+				signature []byte
+			}{3, nil},
+			err: consensus.ErrMembershipNotApproved,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		change, ok := consensus.NewMembershipChange(consensus.MembershipICodeID, consensus.Join, consensus.MembershipArgs("node1", test.input.epoch), test.input.signature)
+		assert.True(t, ok)
+
+		// when
+		err := consensus.AuthorizeMembershipChange(change, validators, 3, mock.ApprovalVerifier{})
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
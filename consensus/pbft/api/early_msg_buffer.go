@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"sync"
+
+	"github.com/it-chain/engine/consensus/pbft"
+)
+
+// 끝난 라운드나 오지 않을 pre-prepare의 msg가 계속 쌓이지 않도록 보관하는 state 수를 제한한다.
+const maxEarlyStates = 8
+
+type earlyMsgs struct {
+	prepares []pbft.PrepareMsg
+	commits  []pbft.CommitMsg
+}
+
+// pre-prepare 보다 먼저 도착한 prepare, commit msg를 StateID 별로 보관한다.
+// sender 마다 가장 먼저 받은 msg 하나만 보관하고, 보관하는 state 수를 넘으면 가장 오래된 state의 msg부터 버린다.
+type earlyMsgBuffer struct {
+	mutex    sync.Mutex
+	stateIDs []string
+	msgs     map[string]*earlyMsgs
+}
+
+func newEarlyMsgBuffer() *earlyMsgBuffer {
+	return &earlyMsgBuffer{
+		stateIDs: make([]string, 0),
+		msgs:     make(map[string]*earlyMsgs),
+	}
+}
+
+func (b *earlyMsgBuffer) addPrepare(msg pbft.PrepareMsg) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	msgs := b.get(msg.StateID.ID)
+	for _, saved := range msgs.prepares {
+		if saved.SenderID == msg.SenderID {
+			return
+		}
+	}
+
+	msgs.prepares = append(msgs.prepares, msg)
+}
+
+func (b *earlyMsgBuffer) addCommit(msg pbft.CommitMsg) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	msgs := b.get(msg.StateID.ID)
+	for _, saved := range msgs.commits {
+		if saved.SenderID == msg.SenderID {
+			return
+		}
+	}
+
+	msgs.commits = append(msgs.commits, msg)
+}
+
+// 보관된 msg를 꺼내고 buffer에서 지운다.
+func (b *earlyMsgBuffer) take(stateID string) earlyMsgs {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	msgs, ok := b.msgs[stateID]
+	if !ok {
+		return earlyMsgs{}
+	}
+
+	delete(b.msgs, stateID)
+	for i, id := range b.stateIDs {
+		if id == stateID {
+			b.stateIDs = append(b.stateIDs[:i], b.stateIDs[i+1:]...)
+			break
+		}
+	}
+
+	return *msgs
+}
+
+func (b *earlyMsgBuffer) get(stateID string) *earlyMsgs {
+
+	if msgs, ok := b.msgs[stateID]; ok {
+		return msgs
+	}
+
+	if len(b.stateIDs) == maxEarlyStates {
+		delete(b.msgs, b.stateIDs[0])
+		b.stateIDs = b.stateIDs[1:]
+	}
+
+	msgs := &earlyMsgs{}
+	b.stateIDs = append(b.stateIDs, stateID)
+	b.msgs[stateID] = msgs
+
+	return msgs
+}
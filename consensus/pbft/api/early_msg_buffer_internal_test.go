@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarlyMsgBuffer_Take(t *testing.T) {
+
+	// given
+	buffer := newEarlyMsgBuffer()
+	buffer.addPrepare(pbft.PrepareMsg{StateID: pbft.StateID{"state"}, SenderID: "user1", BlockHash: []byte{1}})
+	buffer.addPrepare(pbft.PrepareMsg{StateID: pbft.StateID{"state"}, SenderID: "user1", BlockHash: []byte{2}})
+	buffer.addPrepare(pbft.PrepareMsg{StateID: pbft.StateID{"state"}, SenderID: "user2", BlockHash: []byte{1}})
+	buffer.addCommit(pbft.CommitMsg{StateID: pbft.StateID{"state"}, SenderID: "user1", BlockHash: []byte{1}})
+	buffer.addCommit(pbft.CommitMsg{StateID: pbft.StateID{"other"}, SenderID: "user1", BlockHash: []byte{1}})
+
+	// when
+	msgs := buffer.take("state")
+
+	// then
+	assert.Len(t, msgs.prepares, 2)
+	assert.Equal(t, []byte{1}, msgs.prepares[0].BlockHash)
+	assert.Len(t, msgs.commits, 1)
+	assert.Empty(t, buffer.take("state").prepares)
+	assert.Len(t, buffer.take("other").commits, 1)
+}
+
+func TestEarlyMsgBuffer_Evict(t *testing.T) {
+
+	// given
+	buffer := newEarlyMsgBuffer()
+
+	// when
+	for i := 0; i <= maxEarlyStates; i++ {
+		buffer.addPrepare(pbft.PrepareMsg{StateID: pbft.StateID{fmt.Sprintf("state%d", i)}, SenderID: "user1"})
+	}
+
+	// then
+	assert.Len(t, buffer.msgs, maxEarlyStates)
+	assert.Empty(t, buffer.take("state0").prepares)
+	assert.Len(t, buffer.take(fmt.Sprintf("state%d", maxEarlyStates)).prepares, 1)
+}
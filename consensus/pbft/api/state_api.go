@@ -18,7 +18,11 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/it-chain/engine/common/logger"
 	"github.com/it-chain/engine/consensus/pbft"
 )
 
@@ -27,6 +31,7 @@ type StateApi interface {
 	HandlePrePrepareMsg(msg pbft.PrePrepareMsg) error
 	HandlePrepareMsg(msg pbft.PrepareMsg) error
 	HandleCommitMsg(msg pbft.CommitMsg) error
+	RecoverState() error
 }
 
 type StateApiImpl struct {
@@ -35,21 +40,31 @@ type StateApiImpl struct {
 	eventService      pbft.EventService
 	parliamentService pbft.ParliamentService
 	repo              *pbft.StateRepository
+	metrics           pbft.Metrics
+	roundID           string
+	roundStartedAt    time.Time
+	mutex             *sync.Mutex
+	earlyMsgs         *earlyMsgBuffer
 }
 
 var ConsensusCreateError = errors.New("Consensus can't be created")
 
 func NewStateApi(publisherID string, propagateService pbft.PropagateService,
-	eventService pbft.EventService, parliamentService pbft.ParliamentService, repo *pbft.StateRepository) StateApiImpl {
+	eventService pbft.EventService, parliamentService pbft.ParliamentService, repo *pbft.StateRepository, metrics pbft.Metrics) StateApiImpl {
 	return StateApiImpl{
 		publisherID:       publisherID,
 		propagateService:  propagateService,
 		eventService:      eventService,
 		parliamentService: parliamentService,
 		repo:              repo,
+		metrics:           metrics,
+		mutex:             &sync.Mutex{},
+		earlyMsgs:         newEarlyMsgBuffer(),
 	}
 }
 
+// state는 message를 broadcast 하기 전에 먼저 저장한다.
+// replica가 broadcast 도중 재시작 되더라도 저장된 state로부터 같은 message를 다시 보낼 수 있다.
 func (cApi *StateApiImpl) StartConsensus(proposedBlock pbft.ProposedBlock) error {
 
 	peerList, err := cApi.parliamentService.RequestPeerList()
@@ -61,18 +76,20 @@ func (cApi *StateApiImpl) StartConsensus(proposedBlock pbft.ProposedBlock) error
 		return ConsensusCreateError
 	}
 
-	createdState, err := pbft.NewState(peerList, proposedBlock)
+	createdState, err := pbft.NewState(cApi.publisherID, peerList, proposedBlock)
 	if err != nil {
 		return err
 	}
 
-	createdPrePrepareMsg := pbft.NewPrePrepareMsg(createdState, cApi.publisherID)
-	if err := cApi.propagateService.BroadcastPrePrepareMsg(*createdPrePrepareMsg); err != nil {
+	createdState.Start()
+	if err := cApi.repo.Save(*createdState); err != nil {
 		return err
 	}
 
-	createdState.Start()
-	if err := cApi.repo.Save(*createdState); err != nil {
+	cApi.startRound(createdState.StateID.ID)
+
+	createdPrePrepareMsg := pbft.NewPrePrepareMsg(createdState, cApi.publisherID)
+	if err := cApi.propagateService.BroadcastPrePrepareMsg(*createdPrePrepareMsg, createdState.Representatives); err != nil {
 		return err
 	}
 
@@ -87,74 +104,282 @@ func (cApi *StateApiImpl) HandlePrePrepareMsg(msg pbft.PrePrepareMsg) error {
 	}
 
 	if lid.ToString() != msg.SenderID {
-		return pbft.InvalidLeaderIdError
+		return cApi.reject("pre-prepare", pbft.InvalidLeaderIdError)
 	}
 
-	builtState, err := pbft.BuildState(msg)
+	// 이미 prepare 한 state에 대해 다른 block으로 pre-prepare 되는 경우 다시 prepare 하지 않는다.
+	if loadedState, err := cApi.repo.Load(); err == nil && loadedState.StateID.ID == msg.StateID.ID {
+		if !loadedState.IsSameBlock(msg.ProposedBlock.Seal) {
+			logEquivocation(loadedState, msg.SenderID, msg.ProposedBlock.Seal, "pre-prepare")
+			return cApi.reject("pre-prepare", pbft.ErrBlockHashNotSame)
+		}
+
+		return nil
+	}
+
+	peerList, err := cApi.parliamentService.RequestPeerList()
 	if err != nil {
 		return err
 	}
 
-	prepareMsg := pbft.NewPrepareMsg(builtState, cApi.publisherID)
-	if err := cApi.propagateService.BroadcastPrepareMsg(*prepareMsg); err != nil {
+	builtState, err := pbft.BuildState(msg, peerList)
+	if err == pbft.ErrRepresentativesNotSame {
+		return cApi.reject("pre-prepare", err)
+	}
+
+	if err != nil {
 		return err
 	}
+
 	builtState.ToPrepareStage()
 
+	// 자신의 prepare msg도 prepare 조건에 포함된다.
+	prepareMsg := pbft.NewPrepareMsg(builtState, cApi.publisherID)
+	if err := builtState.SavePrepareMsg(prepareMsg); err != nil {
+		return err
+	}
+
 	if err := cApi.repo.Save(*builtState); err != nil {
 		return err
 	}
 
+	cApi.startRound(builtState.StateID.ID)
+
+	if err := cApi.propagateService.BroadcastPrepareMsg(*prepareMsg, builtState.Representatives); err != nil {
+		return err
+	}
+
+	cApi.replayEarlyMsgs(builtState.StateID.ID)
+
 	return nil
 }
 
 func (cApi *StateApiImpl) HandlePrepareMsg(msg pbft.PrepareMsg) error {
 
 	loadedState, err := cApi.repo.Load()
+	if isEarly(loadedState, err, msg.StateID) {
+		if !cApi.isParliamentMember(msg.SenderID) {
+			return cApi.reject("prepare", pbft.ErrNotRepresentative)
+		}
+
+		cApi.earlyMsgs.addPrepare(msg)
+		return nil
+	}
+
 	if err != nil {
 		return err
 	}
 
 	if err := loadedState.SavePrepareMsg(&msg); err != nil {
-		return err
+		if err == pbft.ErrBlockHashNotSame {
+			logEquivocation(loadedState, msg.SenderID, msg.BlockHash, "prepare")
+		}
+		return cApi.reject("prepare", err)
 	}
 
-	if !loadedState.CheckPrepareCondition() {
-		return nil
+	if !loadedState.CheckPrepareCondition() || loadedState.IsCommitStage() {
+		return cApi.repo.Save(loadedState)
 	}
 
+	loadedState.ToCommitStage()
+
+	// 자신의 commit msg도 commit 조건에 포함된다.
 	newCommitMsg := pbft.NewCommitMsg(&loadedState, cApi.publisherID)
-	if err := cApi.propagateService.BroadcastCommitMsg(*newCommitMsg); err != nil {
+	if err := loadedState.SaveCommitMsg(newCommitMsg); err != nil {
 		return err
 	}
-	loadedState.ToCommitStage()
 
 	if err := cApi.repo.Save(loadedState); err != nil {
 		return err
 	}
 
-	return nil
+	if err := cApi.propagateService.BroadcastCommitMsg(*newCommitMsg, loadedState.Representatives); err != nil {
+		return err
+	}
+
+	// prepared 되기 전에 받아둔 commit msg로 이미 commit 조건을 만족할 수 있다.
+	return cApi.confirmIfCommitted(loadedState)
 }
 
 func (cApi *StateApiImpl) HandleCommitMsg(msg pbft.CommitMsg) error {
 
 	loadedState, err := cApi.repo.Load()
+	if isEarly(loadedState, err, msg.StateID) {
+		if !cApi.isParliamentMember(msg.SenderID) {
+			return cApi.reject("commit", pbft.ErrNotRepresentative)
+		}
+
+		cApi.earlyMsgs.addCommit(msg)
+		return nil
+	}
+
 	if err != nil {
 		return err
 	}
 
 	if err := loadedState.SaveCommitMsg(&msg); err != nil {
+		if err == pbft.ErrBlockHashNotSame {
+			logEquivocation(loadedState, msg.SenderID, msg.BlockHash, "commit")
+		}
+		return cApi.reject("commit", err)
+	}
+
+	return cApi.confirmIfCommitted(loadedState)
+}
+
+// 아직 pre-prepare 되지 않은 state의 msg인지 확인한다.
+// 진행 중인 state가 없거나 다른 state가 진행 중이면 다음 라운드의 msg가 먼저 도착한 것일 수 있다.
+func isEarly(loadedState pbft.State, loadErr error, stateID pbft.StateID) bool {
+	if loadErr == pbft.ErrEmptyRepo {
+		return true
+	}
+
+	return loadErr == nil && loadedState.StateID.ID != stateID.ID
+}
+
+// representative는 parliament에서 뽑히므로 parliament member가 아닌 sender의 msg는 보관하지 않는다.
+func (cApi *StateApiImpl) isParliamentMember(senderID string) bool {
+
+	peerList, err := cApi.parliamentService.RequestPeerList()
+	if err != nil {
+		return false
+	}
+
+	for _, memberID := range peerList {
+		if memberID.ToString() == senderID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pre-prepare 보다 먼저 도착해 보관해 둔 msg를 저장된 state에 다시 적용한다.
+// 보관된 msg도 일반 msg와 같은 검증을 거치며, 라운드가 끝나면 남은 msg는 버린다.
+func (cApi *StateApiImpl) replayEarlyMsgs(stateID string) {
+
+	msgs := cApi.earlyMsgs.take(stateID)
+
+	for _, msg := range msgs.prepares {
+		if !cApi.isInProgress(stateID) {
+			return
+		}
+
+		if err := cApi.HandlePrepareMsg(msg); err != nil {
+			logger.Warn(nil, fmt.Sprintf("[PBFT] Early prepare msg is rejected - stateID: [%s], sender: [%s], err: [%s]", stateID, msg.SenderID, err))
+		}
+	}
+
+	for _, msg := range msgs.commits {
+		if !cApi.isInProgress(stateID) {
+			return
+		}
+
+		if err := cApi.HandleCommitMsg(msg); err != nil {
+			logger.Warn(nil, fmt.Sprintf("[PBFT] Early commit msg is rejected - stateID: [%s], sender: [%s], err: [%s]", stateID, msg.SenderID, err))
+		}
+	}
+}
+
+func (cApi *StateApiImpl) isInProgress(stateID string) bool {
+	loadedState, err := cApi.repo.Load()
+	return err == nil && loadedState.StateID.ID == stateID
+}
+
+// commit 조건을 만족하면 block을 확정하고 state를 지운다.
+func (cApi *StateApiImpl) confirmIfCommitted(state pbft.State) error {
+
+	if !state.CheckCommitCondition() {
+		return cApi.repo.Save(state)
+	}
+
+	if err := cApi.eventService.ConfirmBlock(state.Block); err != nil {
 		return err
 	}
 
-	if !loadedState.CheckCommitCondition() {
+	cApi.finishRound(state.StateID.ID)
+
+	return cApi.repo.Remove()
+}
+
+// repository는 한 번에 하나의 state만 가지므로 진행 중인 라운드의 시작 시간도 하나만 기록한다.
+func (cApi *StateApiImpl) startRound(stateID string) {
+
+	cApi.mutex.Lock()
+	defer cApi.mutex.Unlock()
+
+	cApi.roundID = stateID
+	cApi.roundStartedAt = time.Now()
+}
+
+// 재시작 전에 시작된 라운드는 시작 시간을 알 수 없으므로 latency를 기록하지 않는다.
+func (cApi *StateApiImpl) finishRound(stateID string) {
+
+	cApi.mutex.Lock()
+	defer cApi.mutex.Unlock()
+
+	if cApi.roundID != stateID {
+		return
+	}
+
+	cApi.metrics.RoundLatency.Observe(time.Since(cApi.roundStartedAt).Seconds())
+	cApi.roundID = ""
+}
+
+func (cApi *StateApiImpl) reject(msgType string, err error) error {
+	cApi.metrics.RejectedMessages.With("msg_type", msgType).Add(1)
+	return err
+}
+
+// replica가 재시작 되었을 때 저장되어 있던 state의 라운드를 이어서 진행한다.
+// 마지막으로 보냈던 message를 저장된 state로부터 다시 만들어 보내므로, 같은 state에 대해 다른 message를 보내지 않는다.
+// leader가 바뀐 경우 이전 라운드는 더 이상 진행될 수 없으므로 state를 버리고 새 leader의 pre-prepare를 기다린다.
+func (cApi *StateApiImpl) RecoverState() error {
+
+	loadedState, err := cApi.repo.Load()
+	if err == pbft.ErrEmptyRepo {
 		return nil
 	}
 
-	if err := cApi.eventService.ConfirmBlock(loadedState.Block); err != nil {
+	if err != nil {
 		return err
 	}
-	cApi.repo.Remove()
 
-	return nil
+	lid, err := cApi.parliamentService.RequestLeader()
+	if err != nil {
+		return err
+	}
+
+	if lid.ToString() != loadedState.LeaderID {
+		logger.Warn(nil, fmt.Sprintf("[PBFT] Leader has changed while recovering - stateID: [%s], leader: [%s] -> [%s]", loadedState.StateID.ID, loadedState.LeaderID, lid.ToString()))
+		cApi.metrics.ViewChanges.Add(1)
+		return cApi.repo.Remove()
+	}
+
+	logger.Info(nil, fmt.Sprintf("[PBFT] Resuming state - stateID: [%s], stage: [%s]", loadedState.StateID.ID, loadedState.CurrentStage))
+
+	switch loadedState.CurrentStage {
+	case pbft.PREPREPARE_STAGE:
+		return cApi.propagateService.BroadcastPrePrepareMsg(*pbft.NewPrePrepareMsg(&loadedState, cApi.publisherID), loadedState.Representatives)
+
+	case pbft.PREPARE_STAGE:
+		return cApi.propagateService.BroadcastPrepareMsg(*pbft.NewPrepareMsg(&loadedState, cApi.publisherID), loadedState.Representatives)
+
+	case pbft.COMMIT_STAGE:
+		return cApi.propagateService.BroadcastCommitMsg(*pbft.NewCommitMsg(&loadedState, cApi.publisherID), loadedState.Representatives)
+
+	default:
+		return cApi.repo.Remove()
+	}
+}
+
+// pre-prepare 된 block과 다른 block hash를 보낸 member의 정보를 equivocation 증거로 남긴다.
+func logEquivocation(state pbft.State, senderID string, blockHash []byte, msgType string) {
+	logger.Warn(&logger.Fields{
+		"stateID":      state.StateID.ID,
+		"senderID":     senderID,
+		"msgType":      msgType,
+		"expectedHash": fmt.Sprintf("%x", state.Block.Seal),
+		"receivedHash": fmt.Sprintf("%x", blockHash),
+	}, "[PBFT] Equivocation detected - block hash is not same with pre-prepared block")
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/it-chain/engine/consensus/pbft"
@@ -56,10 +57,10 @@ func TestConsensusApi_StartConsensus_State(t *testing.T) {
 func TestConsensusApi_HandlePrePrepareMsg_State(t *testing.T) {
 
 	var validLeaderPrePrepareMsg = pbft.PrePrepareMsg{
-		StateID:        pbft.StateID{},
+		StateID:        pbft.StateID{"newState"},
 		SenderID:       "Leader",
-		Representative: nil,
-		ProposedBlock:  pbft.ProposedBlock{},
+		Representative: representatives(5),
+		ProposedBlock:  normalBlock,
 	}
 
 	tests := map[string]struct {
@@ -98,7 +99,7 @@ func TestConsensusApi_HandlePrepareMsg_State(t *testing.T) {
 	var validPrepareMsg = pbft.PrepareMsg{
 		StateID:   pbft.StateID{"state"},
 		SenderID:  "user1",
-		BlockHash: []byte{1, 2, 3, 5},
+		BlockHash: []byte{1, 2, 3, 4},
 	}
 
 	tests := map[string]struct {
@@ -132,11 +133,97 @@ func TestConsensusApi_HandlePrepareMsg_State(t *testing.T) {
 	}
 
 }
+func TestConsensusApi_HandleEarlyMsgs_State(t *testing.T) {
+
+	prepareMsgs := func(stateID string, senderIDs ...string) []pbft.PrepareMsg {
+		msgs := make([]pbft.PrepareMsg, 0)
+		for _, senderID := range senderIDs {
+			msgs = append(msgs, pbft.PrepareMsg{StateID: pbft.StateID{stateID}, SenderID: senderID, BlockHash: normalBlock.Seal})
+		}
+		return msgs
+	}
+	commitMsgs := func(stateID string, senderIDs ...string) []pbft.CommitMsg {
+		msgs := make([]pbft.CommitMsg, 0)
+		for _, senderID := range senderIDs {
+			msgs = append(msgs, pbft.CommitMsg{StateID: pbft.StateID{stateID}, SenderID: senderID, BlockHash: normalBlock.Seal})
+		}
+		return msgs
+	}
+
+	tests := map[string]struct {
+		input struct {
+			prepareMsgs []pbft.PrepareMsg
+			commitMsgs  []pbft.CommitMsg
+		}
+		confirmed bool
+		stage     pbft.Stage
+	}{
+		"case 1 prepare, commit msg가 모두 pre-prepare 보다 먼저 도착한 경우": {
+			input: struct {
+				prepareMsgs []pbft.PrepareMsg
+				commitMsgs  []pbft.CommitMsg
+			}{prepareMsgs("newState", "Leader", "user2", "user3"), commitMsgs("newState", "Leader", "user2", "user3")},
+			confirmed: true,
+		},
+		"case 2 prepare msg만 pre-prepare 보다 먼저 도착한 경우": {
+			input: struct {
+				prepareMsgs []pbft.PrepareMsg
+				commitMsgs  []pbft.CommitMsg
+			}{prepareMsgs("newState", "user2", "user3"), nil},
+			stage: pbft.COMMIT_STAGE,
+		},
+		"case 3 다른 state의 msg만 먼저 도착한 경우": {
+			input: struct {
+				prepareMsgs []pbft.PrepareMsg
+				commitMsgs  []pbft.CommitMsg
+			}{prepareMsgs("otherState", "user2", "user3"), commitMsgs("otherState", "user2", "user3")},
+			stage: pbft.PREPARE_STAGE,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s ", testName)
+
+		// given
+		cApi := setUpApiCondition(false, 4, true, false, false)
+		confirmed := false
+		cApi.eventService = mock.EventService{ConfirmBlockFunc: func(block pbft.ProposedBlock) error {
+			confirmed = true
+			return nil
+		}}
+
+		for _, msg := range test.input.prepareMsgs {
+			assert.NoError(t, cApi.HandlePrepareMsg(msg))
+		}
+		for _, msg := range test.input.commitMsgs {
+			assert.NoError(t, cApi.HandleCommitMsg(msg))
+		}
+
+		// when
+		err := cApi.HandlePrePrepareMsg(pbft.PrePrepareMsg{
+			StateID:        pbft.StateID{"newState"},
+			SenderID:       "Leader",
+			Representative: representatives(4),
+			ProposedBlock:  normalBlock,
+		})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.confirmed, confirmed)
+		loadedState, err := cApi.repo.Load()
+		if test.confirmed {
+			assert.Equal(t, pbft.ErrEmptyRepo, err)
+			continue
+		}
+		assert.Equal(t, string(test.stage), string(loadedState.CurrentStage))
+	}
+}
+
 func TestConsensusApi_RepositoryClone(t *testing.T) {
 	// stateApi1 에는 setUpApiCondition에 의해 repo가 set된 상황
 	stateApi1 := setUpApiCondition(false, 5, true, false, false)
 	// stateApi2 에는 stateApi1의 Repo가 주입된 상황
-	stateApi2 := NewStateApi("publish2", nil, nil, nil, stateApi1.repo)
+	stateApi2 := NewStateApi("publish2", nil, nil, nil, stateApi1.repo, pbft.NewDiscardMetrics())
 
 	stateApi1.repo.Remove()
 	_, err := stateApi2.repo.Load()
@@ -158,19 +245,92 @@ func TestConsensusApi_RepositoryClone(t *testing.T) {
 
 }
 
+func TestConsensusApi_RecoverState(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			leaderID string
+			stage    pbft.Stage
+			isSaved  bool
+		}
+		broadcasted bool
+		isRemoved   bool
+	}{
+		"Case 1 저장된 state가 없는 경우": {
+			input: struct {
+				leaderID string
+				stage    pbft.Stage
+				isSaved  bool
+			}{"Leader", pbft.PREPARE_STAGE, false},
+			broadcasted: false,
+			isRemoved:   true,
+		},
+		"Case 2 leader가 같고 prepare 단계인 경우 prepare message를 다시 보낸다": {
+			input: struct {
+				leaderID string
+				stage    pbft.Stage
+				isSaved  bool
+			}{"Leader", pbft.PREPARE_STAGE, true},
+			broadcasted: true,
+			isRemoved:   false,
+		},
+		"Case 3 leader가 바뀐 경우 state를 버린다": {
+			input: struct {
+				leaderID string
+				stage    pbft.Stage
+				isSaved  bool
+			}{"OldLeader", pbft.PREPARE_STAGE, true},
+			broadcasted: false,
+			isRemoved:   true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s ", testName)
+
+		// given
+		cApi := setUpApiCondition(false, 5, true, false, false)
+		if test.input.isSaved {
+			cApi.repo.Save(pbft.State{
+				StateID:        pbft.StateID{ID: "state"},
+				LeaderID:       test.input.leaderID,
+				Block:          normalBlock,
+				CurrentStage:   test.input.stage,
+				PrepareMsgPool: pbft.NewPrepareMsgPool(),
+				CommitMsgPool:  pbft.NewCommitMsgPool(),
+			})
+		}
+
+		broadcasted := false
+		propagateService := &mock.MockPropagateService{}
+		propagateService.BroadcastPrepareMsgFunc = func(msg pbft.PrepareMsg, representatives []*pbft.Representative) error {
+			assert.Equal(t, "state", msg.StateID.ID)
+			assert.Equal(t, normalBlock.Seal, msg.BlockHash)
+			broadcasted = true
+			return nil
+		}
+		cApi.propagateService = propagateService
+
+		// when
+		err := cApi.RecoverState()
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.broadcasted, broadcasted)
+		_, err = cApi.repo.Load()
+		assert.Equal(t, test.isRemoved, err == pbft.ErrEmptyRepo)
+	}
+}
+
 func setUpApiCondition(isNeedConsensus bool, peerNum int, isNormalBlock bool,
 	isPrepareConditionSatisfied bool, isCommitConditionSatisfied bool) StateApiImpl {
 
-	reps := make([]*pbft.Representative, 0)
-	for i := 0; i < 6; i++ {
-		reps = append(reps, &pbft.Representative{
-			ID: "user",
-		})
-	}
+	reps := []*pbft.Representative{pbft.NewRepresentative("my"), pbft.NewRepresentative("user1")}
 	prepareMsgPool := pbft.NewPrepareMsgPool()
 	for i := 0; i < 5; i++ {
 		senderStr := "sender"
 		senderStr += string(i)
+		reps = append(reps, pbft.NewRepresentative(senderStr))
 		prepareMsgPool.Save(&pbft.PrepareMsg{
 			StateID:   pbft.StateID{"state"},
 			SenderID:  senderStr,
@@ -183,32 +343,26 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isNormalBlock bool,
 		senderStr := "sender"
 		senderStr += string(i)
 		commitMsgPool.Save(&pbft.CommitMsg{
-			StateID:  pbft.StateID{"state"},
-			SenderID: senderStr,
+			StateID:   pbft.StateID{"state"},
+			SenderID:  senderStr,
+			BlockHash: []byte{1, 2, 3, 4},
 		})
 	}
 
 	propagateService := &mock.MockPropagateService{}
-	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg) error {
+	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
-	propagateService.BroadcastPrepareMsgFunc = func(msg pbft.PrepareMsg) error {
+	propagateService.BroadcastPrepareMsgFunc = func(msg pbft.PrepareMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
-	propagateService.BroadcastCommitMsgFunc = func(msg pbft.CommitMsg) error {
+	propagateService.BroadcastCommitMsgFunc = func(msg pbft.CommitMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
 
 	parliamentService := &mock.MockParliamentService{}
 	parliamentService.RequestPeerListFunc = func() ([]pbft.MemberID, error) {
-		peerList := make([]pbft.MemberID, peerNum)
-		for i := 0; i < peerNum; i++ {
-			userStr := "user"
-			userStr += string(peerNum)
-			peerList = append(peerList, pbft.MemberID(userStr))
-		}
-
-		return peerList, nil
+		return parliament(peerNum), nil
 	}
 	parliamentService.IsNeedConsensusFunc = func() bool {
 		return isNeedConsensus
@@ -249,7 +403,22 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isNormalBlock bool,
 		}
 		repo.Save(savedConsensus)
 	}
-	cApi := NewStateApi("my", propagateService, eventService, parliamentService, &repo)
+	cApi := NewStateApi("my", propagateService, eventService, parliamentService, &repo, pbft.NewDiscardMetrics())
 
 	return cApi
 }
+
+// Leader, my와 나머지 user로 이루어진 peerNum명의 parliament
+func parliament(peerNum int) []pbft.MemberID {
+	peerList := []pbft.MemberID{"Leader", "my"}
+	for i := len(peerList); i < peerNum; i++ {
+		peerList = append(peerList, pbft.MemberID(fmt.Sprintf("user%d", i)))
+	}
+
+	return peerList
+}
+
+func representatives(peerNum int) []*pbft.Representative {
+	reps, _ := pbft.Elect(parliament(peerNum))
+	return reps
+}
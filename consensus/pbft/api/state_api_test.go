@@ -17,11 +17,12 @@
 package api_test
 
 import (
+	"fmt"
 	"testing"
 
+	kitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/it-chain/engine/consensus/pbft"
 	"github.com/it-chain/engine/consensus/pbft/api"
-	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
 	"github.com/it-chain/engine/consensus/pbft/test/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,10 +88,16 @@ func TestConsensusApi_StartConsensus(t *testing.T) {
 func TestConsensusApi_HandlePrePrepareMsg(t *testing.T) {
 
 	var validLeaderPrePrepareMsg = pbft.PrePrepareMsg{
-		StateID:        pbft.StateID{},
+		StateID:        pbft.StateID{"newState"},
 		SenderID:       "Leader",
-		Representative: nil,
-		ProposedBlock:  pbft.ProposedBlock{},
+		Representative: representatives(5),
+		ProposedBlock:  normalBlock,
+	}
+	var otherRepresentativesPrePrepareMsg = pbft.PrePrepareMsg{
+		StateID:        pbft.StateID{"newState"},
+		SenderID:       "Leader",
+		Representative: representatives(4),
+		ProposedBlock:  normalBlock,
 	}
 	var invalidLeaderPrePrepareMsg = pbft.PrePrepareMsg{
 		StateID:        pbft.StateID{},
@@ -134,6 +141,15 @@ func TestConsensusApi_HandlePrePrepareMsg(t *testing.T) {
 			}{invalidLeaderPrePrepareMsg, false, 5, false},
 			err: pbft.InvalidLeaderIdError,
 		},
+		"Case 4 PrePrepareMsg의 Representative가 local validator와 다른 경우": {
+			input: struct {
+				preprePareMsg   pbft.PrePrepareMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+			}{otherRepresentativesPrePrepareMsg, false, 5, false},
+			err: pbft.ErrRepresentativesNotSame,
+		},
 	}
 
 	for testName, test := range tests {
@@ -149,13 +165,23 @@ func TestConsensusApi_HandlePrepareMsg(t *testing.T) {
 	var validPrepareMsg = pbft.PrepareMsg{
 		StateID:   pbft.StateID{"state"},
 		SenderID:  "user1",
-		BlockHash: []byte{1, 2, 3, 5},
+		BlockHash: []byte{1, 2, 3, 4},
 	}
 	var invalidPrepareMsg = pbft.PrepareMsg{
 		StateID:   pbft.StateID{"invalidState"},
+		SenderID:  "user2",
+		BlockHash: []byte{1, 2, 3, 4},
+	}
+	var equivocatedPrepareMsg = pbft.PrepareMsg{
+		StateID:   pbft.StateID{"state"},
 		SenderID:  "user1",
 		BlockHash: []byte{1, 2, 3, 5},
 	}
+	var nonRepresentativePrepareMsg = pbft.PrepareMsg{
+		StateID:   pbft.StateID{"state"},
+		SenderID:  "stranger",
+		BlockHash: []byte{1, 2, 3, 4},
+	}
 
 	tests := map[string]struct {
 		input struct {
@@ -175,23 +201,50 @@ func TestConsensusApi_HandlePrepareMsg(t *testing.T) {
 			}{validPrepareMsg, false, 5, true},
 			err: nil,
 		},
-		"Case 2 PrepareMsg의 Cid와 repo에 저장된 Cid가 다를 경우": {
+		"Case 2 PrepareMsg의 Cid와 repo에 저장된 Cid가 다를 경우 (pre-prepare 될 때까지 보관)": {
 			input: struct {
 				prepareMsg      pbft.PrepareMsg
 				isNeedConsensus bool
 				peerNum         int
 				isRepoFull      bool
 			}{invalidPrepareMsg, false, 5, true},
-			err: pbft.ErrStateIdNotSame,
+			err: nil,
 		},
-		"Case 3 Repo의 state가 empty state일때": {
+		"Case 3 Repo의 state가 empty state일때 (pre-prepare 될 때까지 보관)": {
 			input: struct {
 				prepareMsg      pbft.PrepareMsg
 				isNeedConsensus bool
 				peerNum         int
 				isRepoFull      bool
 			}{invalidPrepareMsg, false, 5, false},
-			err: pbft.ErrEmptyRepo,
+			err: nil,
+		},
+		"Case 4 PrepareMsg의 BlockHash가 pre-prepare된 Block의 Seal과 다를 경우": {
+			input: struct {
+				prepareMsg      pbft.PrepareMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+			}{equivocatedPrepareMsg, false, 5, true},
+			err: pbft.ErrBlockHashNotSame,
+		},
+		"Case 5 PrepareMsg의 sender가 representative가 아닌 경우": {
+			input: struct {
+				prepareMsg      pbft.PrepareMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+			}{nonRepresentativePrepareMsg, false, 5, true},
+			err: pbft.ErrNotRepresentative,
+		},
+		"Case 6 Repo의 state가 empty state이고 sender가 parliament member가 아닌 경우": {
+			input: struct {
+				prepareMsg      pbft.PrepareMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+			}{nonRepresentativePrepareMsg, false, 5, false},
+			err: pbft.ErrNotRepresentative,
 		},
 	}
 
@@ -206,12 +259,24 @@ func TestConsensusApi_HandlePrepareMsg(t *testing.T) {
 func TestConsensusApi_HandleCommitMsg(t *testing.T) {
 
 	var validCommitMsg = pbft.CommitMsg{
-		StateID:  pbft.StateID{"state"},
-		SenderID: "user1",
+		StateID:   pbft.StateID{"state"},
+		SenderID:  "user1",
+		BlockHash: []byte{1, 2, 3, 4},
 	}
 	var invalidCommitMsg = pbft.CommitMsg{
-		StateID:  pbft.StateID{"invalidState"},
-		SenderID: "user2",
+		StateID:   pbft.StateID{"invalidState"},
+		SenderID:  "user2",
+		BlockHash: []byte{1, 2, 3, 4},
+	}
+	var equivocatedCommitMsg = pbft.CommitMsg{
+		StateID:   pbft.StateID{"state"},
+		SenderID:  "user1",
+		BlockHash: []byte{1, 2, 3, 5},
+	}
+	var nonRepresentativeCommitMsg = pbft.CommitMsg{
+		StateID:   pbft.StateID{"state"},
+		SenderID:  "stranger",
+		BlockHash: []byte{1, 2, 3, 4},
 	}
 
 	tests := map[string]struct {
@@ -234,7 +299,7 @@ func TestConsensusApi_HandleCommitMsg(t *testing.T) {
 			}{validCommitMsg, false, 5, true, true},
 			err: nil,
 		},
-		"Case 2 repo에 저장된 state의 cid와 commitMsg의 cid가 일치하지 않은 경우": {
+		"Case 2 repo에 저장된 state의 cid와 commitMsg의 cid가 일치하지 않은 경우 (pre-prepare 될 때까지 보관)": {
 			input: struct {
 				commitMsg       pbft.CommitMsg
 				isNeedConsensus bool
@@ -242,17 +307,47 @@ func TestConsensusApi_HandleCommitMsg(t *testing.T) {
 				isRepoFull      bool
 				isNormalBlock   bool
 			}{invalidCommitMsg, false, 5, true, true},
-			err: pbft.ErrStateIdNotSame,
+			err: nil,
+		},
+		"Case 3 repo에 저장된 state가 empty state일때 (pre-prepare 될 때까지 보관)": {
+			input: struct {
+				commitMsg       pbft.CommitMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+				isNormalBlock   bool
+			}{invalidCommitMsg, false, 5, false, false},
+			err: nil,
+		},
+		"Case 4 commitMsg의 BlockHash가 pre-prepare된 Block의 Seal과 다를 경우": {
+			input: struct {
+				commitMsg       pbft.CommitMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+				isNormalBlock   bool
+			}{equivocatedCommitMsg, false, 5, true, true},
+			err: pbft.ErrBlockHashNotSame,
 		},
-		"Case 3 repo에 저장된 state가 empty state일때": {
+		"Case 5 commitMsg의 sender가 representative가 아닌 경우": {
 			input: struct {
 				commitMsg       pbft.CommitMsg
 				isNeedConsensus bool
 				peerNum         int
 				isRepoFull      bool
 				isNormalBlock   bool
-			}{validCommitMsg, false, 5, false, false},
-			err: pbft.ErrEmptyRepo,
+			}{nonRepresentativeCommitMsg, false, 5, true, true},
+			err: pbft.ErrNotRepresentative,
+		},
+		"Case 6 repo에 저장된 state가 empty state이고 sender가 parliament member가 아닌 경우": {
+			input: struct {
+				commitMsg       pbft.CommitMsg
+				isNeedConsensus bool
+				peerNum         int
+				isRepoFull      bool
+				isNormalBlock   bool
+			}{nonRepresentativeCommitMsg, false, 5, false, false},
+			err: pbft.ErrNotRepresentative,
 		},
 	}
 
@@ -267,9 +362,7 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isRepoFull bool, isNor
 
 	reps := make([]*pbft.Representative, 0)
 	for i := 0; i < 6; i++ {
-		reps = append(reps, &pbft.Representative{
-			ID: "user",
-		})
+		reps = append(reps, pbft.NewRepresentative(fmt.Sprintf("user%d", i)))
 	}
 
 	commitMsgPool := pbft.NewCommitMsgPool()
@@ -277,32 +370,26 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isRepoFull bool, isNor
 		senderStr := "sender"
 		senderStr += string(i)
 		commitMsgPool.Save(&pbft.CommitMsg{
-			StateID:  pbft.StateID{"state"},
-			SenderID: senderStr,
+			StateID:   pbft.StateID{"state"},
+			SenderID:  senderStr,
+			BlockHash: normalBlock.Seal,
 		})
 	}
 
 	propagateService := &mock.MockPropagateService{}
-	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg) error {
+	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
-	propagateService.BroadcastPrepareMsgFunc = func(msg pbft.PrepareMsg) error {
+	propagateService.BroadcastPrepareMsgFunc = func(msg pbft.PrepareMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
-	propagateService.BroadcastCommitMsgFunc = func(msg pbft.CommitMsg) error {
+	propagateService.BroadcastCommitMsgFunc = func(msg pbft.CommitMsg, representatives []*pbft.Representative) error {
 		return nil
 	}
 
 	parliamentService := &mock.MockParliamentService{}
 	parliamentService.RequestPeerListFunc = func() ([]pbft.MemberID, error) {
-		peerList := make([]pbft.MemberID, peerNum)
-		for i := 0; i < peerNum; i++ {
-			userStr := "user"
-			userStr += string(peerNum)
-			peerList = append(peerList, pbft.MemberID(userStr))
-		}
-
-		return peerList, nil
+		return parliament(peerNum), nil
 	}
 	parliamentService.IsNeedConsensusFunc = func() bool {
 		return isNeedConsensus
@@ -311,9 +398,10 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isRepoFull bool, isNor
 		return "Leader", nil
 	}
 
-	eventService := adapter.NewEventService(func(topic string, data interface{}) (err error) {
+	eventService := &mock.EventService{}
+	eventService.ConfirmBlockFunc = func(block pbft.ProposedBlock) error {
 		return nil
-	})
+	}
 
 	repo := pbft.NewStateRepository()
 	if isRepoFull && isNormalBlock {
@@ -339,6 +427,114 @@ func setUpApiCondition(isNeedConsensus bool, peerNum int, isRepoFull bool, isNor
 		}
 		repo.Save(savedConsensus)
 	}
-	cApi := api.NewStateApi("my", propagateService, eventService, parliamentService, &repo)
+	cApi := api.NewStateApi("my", propagateService, eventService, parliamentService, &repo, pbft.NewDiscardMetrics())
 	return cApi
 }
+
+func TestStateApi_Metrics(t *testing.T) {
+
+	// given
+	rejected := &recordingCounter{}
+	viewChanges := &recordingCounter{}
+	latency := &recordingHistogram{observed: make([]float64, 0)}
+	metrics := pbft.Metrics{
+		RoundLatency:     latency,
+		ViewChanges:      viewChanges,
+		RejectedMessages: rejected,
+	}
+
+	propagateService := &mock.MockPropagateService{}
+	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
+		return nil
+	}
+	propagateService.BroadcastCommitMsgFunc = func(msg pbft.CommitMsg, representatives []*pbft.Representative) error {
+		return nil
+	}
+
+	parliamentService := &mock.MockParliamentService{}
+	parliamentService.RequestPeerListFunc = func() ([]pbft.MemberID, error) {
+		return []pbft.MemberID{"user0", "user1", "user2", "user3"}, nil
+	}
+	parliamentService.IsNeedConsensusFunc = func() bool {
+		return true
+	}
+	parliamentService.RequestLeaderFunc = func() (pbft.MemberID, error) {
+		return "user0", nil
+	}
+
+	eventService := &mock.EventService{}
+	eventService.ConfirmBlockFunc = func(block pbft.ProposedBlock) error {
+		return nil
+	}
+
+	repo := pbft.NewStateRepository()
+	cApi := api.NewStateApi("user0", propagateService, eventService, parliamentService, &repo, metrics)
+
+	assert.NoError(t, cApi.StartConsensus(normalBlock))
+	state, _ := repo.Load()
+
+	// when : non-leader pre-prepare, forged prepare
+	cApi.HandlePrePrepareMsg(pbft.PrePrepareMsg{StateID: state.StateID, SenderID: "user1", ProposedBlock: normalBlock})
+	cApi.HandlePrepareMsg(pbft.PrepareMsg{StateID: state.StateID, SenderID: "user3", BlockHash: []byte("forged")})
+
+	// then
+	assert.Equal(t, float64(2), rejected.value)
+
+	// when : round is confirmed
+	for _, sender := range []string{"user1", "user2"} {
+		assert.NoError(t, cApi.HandlePrepareMsg(pbft.PrepareMsg{StateID: state.StateID, SenderID: sender, BlockHash: normalBlock.Seal}))
+	}
+	for _, sender := range []string{"user1", "user2"} {
+		assert.NoError(t, cApi.HandleCommitMsg(pbft.CommitMsg{StateID: state.StateID, SenderID: sender, BlockHash: normalBlock.Seal}))
+	}
+
+	// then
+	assert.Equal(t, 1, len(latency.observed))
+
+	// when : leader has changed while recovering
+	repo.Save(pbft.State{StateID: pbft.StateID{"old"}, LeaderID: "oldLeader", CurrentStage: pbft.PREPARE_STAGE})
+	assert.NoError(t, cApi.RecoverState())
+
+	// then
+	assert.Equal(t, float64(1), viewChanges.value)
+}
+
+// label과 관계없이 모든 값을 합산한다.
+type recordingCounter struct {
+	value float64
+}
+
+func (c *recordingCounter) With(labelValues ...string) kitmetrics.Counter {
+	return c
+}
+
+func (c *recordingCounter) Add(delta float64) {
+	c.value += delta
+}
+
+type recordingHistogram struct {
+	observed []float64
+}
+
+func (h *recordingHistogram) With(labelValues ...string) kitmetrics.Histogram {
+	return h
+}
+
+func (h *recordingHistogram) Observe(value float64) {
+	h.observed = append(h.observed, value)
+}
+
+// Leader, my와 나머지 user로 이루어진 peerNum명의 parliament
+func parliament(peerNum int) []pbft.MemberID {
+	peerList := []pbft.MemberID{"Leader", "my"}
+	for i := len(peerList); i < peerNum; i++ {
+		peerList = append(peerList, pbft.MemberID(fmt.Sprintf("user%d", i)))
+	}
+
+	return peerList
+}
+
+func representatives(peerNum int) []*pbft.Representative {
+	reps, _ := pbft.Elect(parliament(peerNum))
+	return reps
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/api"
+)
+
+// PBFT로 block의 순서를 합의하는 consensus.Consensus의 구현체이다.
+// 합의가 끝난 block은 등록된 deliver hook으로 전달된다.
+// 전달된 block에 기록된 validator 변경은 그 다음 block의 합의부터 적용된다.
+type Consensus struct {
+	publisherID       string
+	stateApi          api.StateApi
+	repo              *pbft.StateRepository
+	parliamentService pbft.ParliamentService
+	validatorSet      *pbft.ValidatorSet
+	verifier          consensus.ApprovalVerifier
+	deliver           consensus.DeliverHandler
+	mutex             sync.RWMutex
+}
+
+func NewConsensus(publisherID string, propagateService pbft.PropagateService,
+	parliamentService pbft.ParliamentService, repo *pbft.StateRepository, validatorSet *pbft.ValidatorSet, verifier consensus.ApprovalVerifier, metrics pbft.Metrics) *Consensus {
+
+	c := &Consensus{
+		publisherID:       publisherID,
+		parliamentService: parliamentService,
+		repo:              repo,
+		validatorSet:      validatorSet,
+		verifier:          verifier,
+		mutex:             sync.RWMutex{},
+	}
+
+	// state api가 합의를 마친 block은 Consensus의 ConfirmBlock으로 전달된다.
+	stateApi := api.NewStateApi(publisherID, propagateService, c, parliamentService, repo, metrics)
+	c.stateApi = &stateApi
+
+	return c
+}
+
+func (c *Consensus) StateApi() api.StateApi {
+	return c.stateApi
+}
+
+// 진행 중인 state를 조회할 수 있도록 repository를 반환한다.
+func (c *Consensus) StateRepository() *pbft.StateRepository {
+	return c.repo
+}
+
+func (c *Consensus) Propose(block consensus.ProposedBlock) error {
+	return c.stateApi.StartConsensus(pbft.ProposedBlock{
+		Seal: block.Seal,
+		Body: block.Body,
+	})
+}
+
+func (c *Consensus) OnDeliver(handler consensus.DeliverHandler) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.deliver = handler
+}
+
+// validator인 leader만 block을 제안할 수 있다.
+func (c *Consensus) IsProposer() bool {
+
+	leaderID, err := c.parliamentService.RequestLeader()
+	if err != nil {
+		return false
+	}
+
+	return leaderID.ToString() == c.publisherID && c.validatorSet.Contains(pbft.MemberID(c.publisherID))
+}
+
+// block에 기록된 validator 변경이 적용될 때 호출된다.
+func (c *Consensus) AddMember(memberID string) error {
+	c.validatorSet.Add(pbft.MemberID(memberID))
+	logger.Info(nil, fmt.Sprintf("[PBFT] Validator has joined - id: [%s], validators: [%d], epoch: [%d]", memberID, c.validatorSet.Size(), c.validatorSet.Epoch()))
+	return nil
+}
+
+func (c *Consensus) RemoveMember(memberID string) error {
+	c.validatorSet.Remove(pbft.MemberID(memberID))
+	logger.Info(nil, fmt.Sprintf("[PBFT] Validator has left - id: [%s], validators: [%d], epoch: [%d]", memberID, c.validatorSet.Size(), c.validatorSet.Epoch()))
+	return nil
+}
+
+func (c *Consensus) Members() []string {
+	members := make([]string, 0)
+	for _, v := range c.validatorSet.Validators() {
+		members = append(members, v.ToString())
+	}
+
+	return members
+}
+
+func (c *Consensus) Epoch() uint64 {
+	return c.validatorSet.Epoch()
+}
+
+// pbft.EventService
+func (c *Consensus) ConfirmBlock(block pbft.ProposedBlock) error {
+
+	c.mutex.RLock()
+	deliver := c.deliver
+	c.mutex.RUnlock()
+
+	if deliver != nil {
+		if err := deliver(consensus.ProposedBlock{Seal: block.Seal, Body: block.Body}); err != nil {
+			return err
+		}
+	}
+
+	c.applyMembershipChanges(block)
+
+	return nil
+}
+
+// 확정된 block의 transaction 중 validator 과반이 승인한 membership 변경을 validator 목록에 적용한다.
+// leader가 승인되지 않은 변경을 block에 넣더라도 모든 replica가 같은 validator 목록으로 확인하여 같이 무시한다.
+func (c *Consensus) applyMembershipChanges(block pbft.ProposedBlock) {
+
+	changes, err := consensus.MembershipChangesOf(consensus.ProposedBlock{Seal: block.Seal, Body: block.Body})
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[PBFT] Fail to read membership changes - seal: [%x], err: [%s]", block.Seal, err.Error()))
+		return
+	}
+
+	for _, change := range changes {
+		if err := consensus.ApplyApprovedMembershipChange(c, change, c.verifier); err != nil {
+			logger.Warn(nil, fmt.Sprintf("[PBFT] Membership change is rejected - member: [%s], err: [%s]", change.MemberID, err.Error()))
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
+	"github.com/it-chain/engine/consensus/pbft/test/mock"
+	consensusMock "github.com/it-chain/engine/consensus/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensus_Propose(t *testing.T) {
+
+	// given
+	broadcasted := false
+	propagateService := &mock.MockPropagateService{}
+	propagateService.BroadcastPrePrepareMsgFunc = func(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
+		assert.Equal(t, "leader", msg.SenderID)
+		assert.Equal(t, []byte("seal"), msg.ProposedBlock.Seal)
+		assert.Equal(t, 4, len(representatives))
+		broadcasted = true
+		return nil
+	}
+
+	repo := pbft.NewStateRepository()
+	pbftConsensus := adapter.NewConsensus("leader", propagateService, newMockParliamentService("leader"), &repo, newValidatorSet(), consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+
+	// when
+	err := pbftConsensus.Propose(consensus.ProposedBlock{Seal: []byte("seal"), Body: []byte("body")})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, broadcasted)
+
+	state, err := repo.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, pbft.PREPREPARE_STAGE, state.CurrentStage)
+}
+
+func TestConsensus_IsProposer(t *testing.T) {
+
+	repo := pbft.NewStateRepository()
+
+	leaderConsensus := adapter.NewConsensus("leader", &mock.MockPropagateService{}, newMockParliamentService("leader"), &repo, newValidatorSet(), consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+	assert.True(t, leaderConsensus.IsProposer())
+
+	followerConsensus := adapter.NewConsensus("follower", &mock.MockPropagateService{}, newMockParliamentService("leader"), &repo, newValidatorSet(), consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+	assert.False(t, followerConsensus.IsProposer())
+
+	// validator가 아닌 leader는 block을 제안할 수 없다.
+	notValidatorConsensus := adapter.NewConsensus("leader", &mock.MockPropagateService{}, newMockParliamentService("leader"), &repo, pbft.NewValidatorSet([]pbft.MemberID{"user1"}), consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+	assert.False(t, notValidatorConsensus.IsProposer())
+}
+
+func TestConsensus_ConfirmBlock(t *testing.T) {
+
+	// given
+	repo := pbft.NewStateRepository()
+	pbftConsensus := adapter.NewConsensus("leader", &mock.MockPropagateService{}, newMockParliamentService("leader"), &repo, newValidatorSet(), consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+
+	deliveredBlocks := make([]consensus.ProposedBlock, 0)
+	pbftConsensus.OnDeliver(func(block consensus.ProposedBlock) error {
+		deliveredBlocks = append(deliveredBlocks, block)
+		return nil
+	})
+
+	// when
+	err := pbftConsensus.ConfirmBlock(pbft.ProposedBlock{Seal: []byte("seal"), Body: []byte("body")})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(deliveredBlocks))
+	assert.Equal(t, []byte("seal"), deliveredBlocks[0].Seal)
+	assert.Equal(t, []byte("body"), deliveredBlocks[0].Body)
+}
+
+func TestConsensus_ConfirmBlock_MembershipChange(t *testing.T) {
+
+	// given
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "user4"}
+	leave := consensus.MembershipChange{Function: consensus.Leave, MemberID: "user1", Epoch: 1}
+	unapproved := consensus.MembershipChange{Function: consensus.Join, MemberID: "user5", Epoch: 2}
+	rejoin := consensus.MembershipChange{Function: consensus.Join, MemberID: "user1"}
+
+	body, err := common.Serialize(command.StartConsensus{
+		Seal: []byte("seal"),
+		TxList: []command.Tx{
+			{ID: "tx1", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"user4"}, Signature: consensusMock.ApprovalSignature(join, "leader", "user1", "user2")},
+			{ID: "tx2", ICodeID: consensus.MembershipICodeID, Function: consensus.Leave, Args: consensus.MembershipArgs("user1", 1), Signature: consensusMock.ApprovalSignature(leave, "leader", "user2", "user3")},
+			{ID: "tx3", ICodeID: "icode1", Function: "invoke", Args: []string{"user5"}},
+			{ID: "tx4", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: consensus.MembershipArgs("user5", 2), Signature: consensusMock.ApprovalSignature(unapproved, "leader", "user5")},
+			{ID: "tx5", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"user1"}, Signature: consensusMock.ApprovalSignature(rejoin, "leader", "user2", "user3")},
+		},
+	})
+	assert.NoError(t, err)
+
+	validatorSet := newValidatorSet()
+	repo := pbft.NewStateRepository()
+	pbftConsensus := adapter.NewConsensus("leader", &mock.MockPropagateService{}, newMockParliamentService("leader"), &repo, validatorSet, consensusMock.ApprovalVerifier{}, pbft.NewDiscardMetrics())
+
+	// deliver hook이 실패하면 validator 변경을 적용하지 않는다.
+	pbftConsensus.OnDeliver(func(block consensus.ProposedBlock) error {
+		return pbft.ErrEmptyRepo
+	})
+
+	// when
+	err = pbftConsensus.ConfirmBlock(pbft.ProposedBlock{Seal: []byte("seal"), Body: body})
+
+	// then
+	assert.Equal(t, pbft.ErrEmptyRepo, err)
+	assert.Equal(t, []pbft.MemberID{"leader", "user1", "user2", "user3"}, validatorSet.Validators())
+	assert.Equal(t, uint64(0), pbftConsensus.Epoch())
+
+	// given
+	pbftConsensus.OnDeliver(func(block consensus.ProposedBlock) error {
+		return nil
+	})
+
+	// when
+	err = pbftConsensus.ConfirmBlock(pbft.ProposedBlock{Seal: []byte("seal"), Body: body})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []pbft.MemberID{"leader", "user2", "user3", "user4"}, validatorSet.Validators())
+	assert.Equal(t, uint64(2), pbftConsensus.Epoch())
+}
+
+func newValidatorSet() *pbft.ValidatorSet {
+	return pbft.NewValidatorSet([]pbft.MemberID{"leader", "user1", "user2", "user3"})
+}
+
+func newMockParliamentService(leaderID string) *mock.MockParliamentService {
+	parliamentService := &mock.MockParliamentService{}
+	parliamentService.RequestLeaderFunc = func() (pbft.MemberID, error) {
+		return pbft.MemberID(leaderID), nil
+	}
+	parliamentService.RequestPeerListFunc = func() ([]pbft.MemberID, error) {
+		return []pbft.MemberID{"leader", "user1", "user2", "user3"}, nil
+	}
+	parliamentService.IsNeedConsensusFunc = func() bool {
+		return true
+	}
+
+	return parliamentService
+}
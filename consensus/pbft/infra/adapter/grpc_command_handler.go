@@ -16,14 +16,83 @@
 
 package adapter
 
-// todo
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/api"
+)
+
+var ErrSenderNotSame = errors.New("Sender id is not same with connection id")
+
+// 다른 representative로부터 받은 pre-prepare, prepare, commit message를 state api로 전달한다.
+// message의 SenderID는 보낸 쪽이 채우는 값이므로, 인증된 connection의 id와 같은 경우에만 전달한다.
+// pre-prepare의 block은 body를 복원할 수 있는 경우에만 전달한다.
 type GrpcCommandHandler struct {
+	stateApi api.StateApi
 }
 
-func NewGrpcCommandHandler() GrpcCommandHandler {
-	return GrpcCommandHandler{}
+func NewGrpcCommandHandler(stateApi api.StateApi) GrpcCommandHandler {
+	return GrpcCommandHandler{
+		stateApi: stateApi,
+	}
 }
 
-func (g GrpcCommandHandler) HandleGrpcCommand() error {
+func (g *GrpcCommandHandler) HandleGrpcCommand(command command.ReceiveGrpc) error {
+
+	switch command.Protocol {
+
+	case "PrePrepareMsgProtocol":
+		msg := pbft.PrePrepareMsg{}
+		if err := extractMsg(command.Body, &msg); err != nil {
+			return err
+		}
+
+		if msg.SenderID != command.ConnectionID {
+			return ErrSenderNotSame
+		}
+
+		// 복원할 수 없는 block이 합의되면 모든 replica가 commit 하지 못하므로 pre-prepare 단계에서 거절한다.
+		if _, err := consensus.DecodeBlockBody(consensus.ProposedBlock{Seal: msg.ProposedBlock.Seal, Body: msg.ProposedBlock.Body}); err != nil {
+			return err
+		}
+
+		return g.stateApi.HandlePrePrepareMsg(msg)
+
+	case "PrepareMsgProtocol":
+		msg := pbft.PrepareMsg{}
+		if err := extractMsg(command.Body, &msg); err != nil {
+			return err
+		}
+
+		if msg.SenderID != command.ConnectionID {
+			return ErrSenderNotSame
+		}
+
+		return g.stateApi.HandlePrepareMsg(msg)
+
+	case "CommitMsgProtocol":
+		msg := pbft.CommitMsg{}
+		if err := extractMsg(command.Body, &msg); err != nil {
+			return err
+		}
+
+		if msg.SenderID != command.ConnectionID {
+			return ErrSenderNotSame
+		}
+
+		return g.stateApi.HandleCommitMsg(msg)
+	}
+
 	return nil
 }
+
+// propagate service는 message를 body로 한 번만 직렬화 하여 보낸다.
+// common.Deserialize는 잘못된 body에 panic 하므로 직접 decode 한다.
+func extractMsg(body []byte, msg interface{}) error {
+
+	return json.Unmarshal(body, msg)
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
+	"github.com/it-chain/engine/consensus/pbft/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrpcCommandHandler_HandleGrpcCommand(t *testing.T) {
+
+	// given
+	blockBody, err := common.Serialize(command.StartConsensus{Seal: []byte("seal")})
+	assert.NoError(t, err)
+
+	block := pbft.ProposedBlock{Seal: []byte("seal"), Body: blockBody}
+
+	tests := map[string]struct {
+		input struct {
+			protocol string
+			msg      interface{}
+		}
+		connectionID string
+		handled      string
+		err          error
+	}{
+		"pre-prepare message": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"PrePrepareMsgProtocol", pbft.PrePrepareMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "leader", ProposedBlock: block}},
+			connectionID: "leader",
+			handled:      "pre-prepare",
+		},
+		"prepare message": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"PrepareMsgProtocol", pbft.PrepareMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "user1", BlockHash: []byte{1, 2, 3, 4}}},
+			connectionID: "user1",
+			handled:      "prepare",
+		},
+		"commit message": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"CommitMsgProtocol", pbft.CommitMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "user1", BlockHash: []byte{1, 2, 3, 4}}},
+			connectionID: "user1",
+			handled:      "commit",
+		},
+		"pre-prepare message with forged leader id": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"PrePrepareMsgProtocol", pbft.PrePrepareMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "leader", ProposedBlock: block}},
+			connectionID: "user2",
+			handled:      "",
+			err:          adapter.ErrSenderNotSame,
+		},
+		"pre-prepare message with malformed block body": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"PrePrepareMsgProtocol", pbft.PrePrepareMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "leader", ProposedBlock: pbft.ProposedBlock{Seal: []byte("seal"), Body: []byte("{")}}},
+			connectionID: "leader",
+			handled:      "",
+			err:          consensus.ErrInvalidBlockBody,
+		},
+		"pre-prepare message with seal not same with body": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"PrePrepareMsgProtocol", pbft.PrePrepareMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "leader", ProposedBlock: pbft.ProposedBlock{Seal: []byte("other seal"), Body: blockBody}}},
+			connectionID: "leader",
+			handled:      "",
+			err:          consensus.ErrSealNotSame,
+		},
+		"commit message with forged sender id": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"CommitMsgProtocol", pbft.CommitMsg{StateID: pbft.StateID{ID: "state1"}, SenderID: "user1", BlockHash: []byte{1, 2, 3, 4}}},
+			connectionID: "user2",
+			handled:      "",
+			err:          adapter.ErrSenderNotSame,
+		},
+		"unknown protocol": {
+			input: struct {
+				protocol string
+				msg      interface{}
+			}{"UnknownProtocol", pbft.CommitMsg{}},
+			handled: "",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		handled := ""
+		mockStateApi := &mock.MockStateApi{}
+		mockStateApi.HandlePrePrepareMsgFunc = func(msg pbft.PrePrepareMsg) error {
+			assert.Equal(t, "state1", msg.StateID.ID)
+			assert.Equal(t, "leader", msg.SenderID)
+			handled = "pre-prepare"
+			return nil
+		}
+		mockStateApi.HandlePrepareMsgFunc = func(msg pbft.PrepareMsg) error {
+			assert.Equal(t, []byte{1, 2, 3, 4}, msg.BlockHash)
+			handled = "prepare"
+			return nil
+		}
+		mockStateApi.HandleCommitMsgFunc = func(msg pbft.CommitMsg) error {
+			assert.Equal(t, []byte{1, 2, 3, 4}, msg.BlockHash)
+			handled = "commit"
+			return nil
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(mockStateApi)
+
+		body, err := common.Serialize(test.input.msg)
+		assert.NoError(t, err)
+
+		// when
+		err = grpcCommandHandler.HandleGrpcCommand(command.ReceiveGrpc{
+			ConnectionID: test.connectionID,
+			Body:         body,
+			Protocol:     test.input.protocol,
+		})
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.handled, handled)
+	}
+}
@@ -21,13 +21,16 @@ import (
 	"github.com/it-chain/engine/consensus/pbft"
 )
 
+// leader는 p2p의 leader를 따르고, representative는 validator 목록에서 선출한다.
 type ParliamentService struct {
-	pQuery api_gateway.PeerQueryApi
+	pQuery       api_gateway.PeerQueryApi
+	validatorSet *pbft.ValidatorSet
 }
 
-func NewParliamentService(api api_gateway.PeerQueryApi) *ParliamentService {
+func NewParliamentService(api api_gateway.PeerQueryApi, validatorSet *pbft.ValidatorSet) *ParliamentService {
 	return &ParliamentService{
-		pQuery: api,
+		pQuery:       api,
+		validatorSet: validatorSet,
 	}
 }
 
@@ -41,35 +44,12 @@ func (ps *ParliamentService) RequestLeader() (pbft.MemberID, error) {
 	return pbft.MemberID(l.GetID()), nil
 }
 
+// 연결된 peer가 아니라 validator 목록을 반환한다.
 func (ps *ParliamentService) RequestPeerList() ([]pbft.MemberID, error) {
-	pl, err := ps.pQuery.GetPeerList()
-
-	if err != nil {
-		return nil, err
-	}
-
-	peerList := make([]pbft.MemberID, 0)
-
-	for _, p := range pl {
-		peerList = append(peerList, pbft.MemberID(p.GetID()))
-	}
-
-	return peerList, nil
+	return ps.validatorSet.Validators(), nil
 }
 
-func (p *ParliamentService) IsNeedConsensus() bool {
-	peerList, err := p.RequestPeerList()
-
-	if err != nil {
-		return false
-	}
-
-	numOfMember := 0
-	numOfMember = numOfMember + len(peerList)
-
-	if numOfMember >= 4 {
-		return true
-	}
-
-	return false
+// byzantine validator를 한 명 이상 견디려면 3f+1 (f >= 1), 즉 적어도 4명의 validator가 필요하다.
+func (ps *ParliamentService) IsNeedConsensus() bool {
+	return ps.validatorSet.Size() >= 4
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/it-chain/engine/api_gateway"
+	"github.com/it-chain/engine/consensus/pbft"
 	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
 	"github.com/it-chain/engine/p2p"
 	"github.com/it-chain/engine/p2p/infra/mem"
@@ -35,7 +36,7 @@ func TestParliamentService_RequestLeader(t *testing.T) {
 		PeerId:    p2p.PeerId{"p1"},
 	})
 
-	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository))
+	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository), pbft.NewValidatorSet(nil))
 
 	// when
 	l, _ := ps.RequestLeader()
@@ -60,33 +61,28 @@ func TestParliamentService_RequestPeerList(t *testing.T) {
 	// given
 	peerRepository := mem.NewPeerReopository()
 
-	p1 := p2p.Peer{
+	// validator가 아닌 peer는 representative가 될 수 없다.
+	peerRepository.Save(p2p.Peer{
 		IpAddress: "1.1.1.1",
 		PeerId:    p2p.PeerId{"p1"},
-	}
-
-	p2 := p2p.Peer{
-		IpAddress: "2.2.2.2",
-		PeerId:    p2p.PeerId{"p2"},
-	}
-
-	peerRepository.Save(p1)
-	peerRepository.Save(p2)
+	})
 
-	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository))
+	validatorSet := pbft.NewValidatorSet([]pbft.MemberID{"v2", "v1"})
+	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository), validatorSet)
 
 	// when
 	peerList, err := ps.RequestPeerList()
 
 	// then
-	assert.Equal(t, 2, len(peerList))
+	assert.Equal(t, []pbft.MemberID{"v1", "v2"}, peerList)
 	assert.Nil(t, err)
 }
 
 func TestParliamentService_IsNeedConsensus(t *testing.T) {
-	// given (case 1 : no member)
+	// given (case 1 : no validator)
 	peerRepository := mem.NewPeerReopository()
-	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository))
+	validatorSet := pbft.NewValidatorSet(nil)
+	ps := adapter.NewParliamentService(api_gateway.NewPeerQueryApi(&peerRepository), validatorSet)
 
 	// when
 	flag := ps.IsNeedConsensus()
@@ -94,25 +90,10 @@ func TestParliamentService_IsNeedConsensus(t *testing.T) {
 	// then
 	assert.Equal(t, false, flag)
 
-	// given (case 2 : less than 4 members)
-	p1 := p2p.Peer{
-		IpAddress: "1.1.1.1",
-		PeerId:    p2p.PeerId{"p1"},
-	}
-
-	p2 := p2p.Peer{
-		IpAddress: "2.2.2.2",
-		PeerId:    p2p.PeerId{"p2"},
-	}
-
-	p3 := p2p.Peer{
-		IpAddress: "3.3.3.3",
-		PeerId:    p2p.PeerId{"p3"},
-	}
-
-	peerRepository.Save(p1)
-	peerRepository.Save(p2)
-	peerRepository.Save(p3)
+	// given (case 2 : less than 4 validators)
+	validatorSet.Add("v1")
+	validatorSet.Add("v2")
+	validatorSet.Add("v3")
 
 	// when
 	flag = ps.IsNeedConsensus()
@@ -120,13 +101,8 @@ func TestParliamentService_IsNeedConsensus(t *testing.T) {
 	// then
 	assert.Equal(t, false, flag)
 
-	// given (case 3 : equal or moro than 4 members)
-	p4 := p2p.Peer{
-		IpAddress: "4.4.4.4",
-		PeerId:    p2p.PeerId{"p4"},
-	}
-
-	peerRepository.Save(p4)
+	// given (case 3 : 4 validators)
+	validatorSet.Add("v4")
 
 	// when
 	flag = ps.IsNeedConsensus()
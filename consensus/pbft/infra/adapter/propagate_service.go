@@ -30,6 +30,8 @@ var ErrEmptyBlock = errors.New("Block is empty")
 var ErrEmptyBlockHash = errors.New("Block hash is empty")
 var ErrEmptyMsg = errors.New("Message is empty")
 
+type Publish func(topic string, data interface{}) (err error)
+
 type PropagateService struct {
 	publish Publish
 }
@@ -49,13 +51,7 @@ func (ps PropagateService) BroadcastPrePrepareMsg(msg pbft.PrePrepareMsg, repres
 		return ErrEmptyBlock
 	}
 
-	SerializedMsg, err := common.Serialize(msg)
-
-	if err != nil {
-		return err
-	}
-
-	if err = ps.broadcastMsg(SerializedMsg, "PrePrepareMsgProtocol", representatives); err != nil {
+	if err := ps.broadcastMsg(msg, "PrePrepareMsgProtocol", representatives); err != nil {
 		return err
 	}
 
@@ -71,13 +67,7 @@ func (ps PropagateService) BroadcastPrepareMsg(msg pbft.PrepareMsg, representati
 		return ErrEmptyBlockHash
 	}
 
-	SerializedMsg, err := common.Serialize(msg)
-
-	if err != nil {
-		return err
-	}
-
-	if err = ps.broadcastMsg(SerializedMsg, "PrepareMsgProtocol", representatives); err != nil {
+	if err := ps.broadcastMsg(msg, "PrepareMsgProtocol", representatives); err != nil {
 		return err
 	}
 
@@ -89,25 +79,24 @@ func (ps PropagateService) BroadcastCommitMsg(msg pbft.CommitMsg, representative
 		return ErrStateIdEmpty
 	}
 
-	SerializedMsg, err := common.Serialize(msg)
-
-	if err != nil {
-		return err
+	if msg.BlockHash == nil {
+		return ErrEmptyBlockHash
 	}
 
-	if err = ps.broadcastMsg(SerializedMsg, "CommitMsgProtocol", representatives); err != nil {
+	if err := ps.broadcastMsg(msg, "CommitMsgProtocol", representatives); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (ps PropagateService) broadcastMsg(SerializedMsg []byte, protocol string, representatives []*pbft.Representative) error {
-	if SerializedMsg == nil {
+// message는 body로 한 번만 직렬화 한다.
+func (ps PropagateService) broadcastMsg(msg interface{}, protocol string, representatives []*pbft.Representative) error {
+	if msg == nil {
 		return ErrEmptyMsg
 	}
 
-	command, err := createDeliverGrpcCommand(protocol, SerializedMsg)
+	command, err := createDeliverGrpcCommand(protocol, msg)
 
 	if err != nil {
 		return err
@@ -175,8 +175,9 @@ func TestPropagateService_BroadcastCommitMsg(t *testing.T) {
 				msg pbft.CommitMsg
 			}{
 				msg: pbft.CommitMsg{
-					StateID:  pbft.StateID{"c1"},
-					SenderID: "s1",
+					StateID:   pbft.StateID{"c1"},
+					SenderID:  "s1",
+					BlockHash: make([]byte, 0),
 				},
 			},
 			err: nil,
@@ -186,12 +187,25 @@ func TestPropagateService_BroadcastCommitMsg(t *testing.T) {
 				msg pbft.CommitMsg
 			}{
 				msg: pbft.CommitMsg{
-					StateID:  pbft.StateID{""},
-					SenderID: "s1",
+					StateID:   pbft.StateID{""},
+					SenderID:  "s1",
+					BlockHash: make([]byte, 0),
 				},
 			},
 			err: errors.New("State ID is empty"),
 		},
+		"Block hash empty test": {
+			input: struct {
+				msg pbft.CommitMsg
+			}{
+				msg: pbft.CommitMsg{
+					StateID:   pbft.StateID{"c1"},
+					SenderID:  "s1",
+					BlockHash: nil,
+				},
+			},
+			err: errors.New("Block hash is empty"),
+		},
 	}
 
 	publish := func(topic string, data interface{}) (e error) {
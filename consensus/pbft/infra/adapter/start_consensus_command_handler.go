@@ -38,10 +38,7 @@ func NewStartConsensusCommandHandler(sApi api.StateApi) *StartConsensusCommandHa
 }
 
 func (r StartConsensusCommandHandler) HandleStartConsensusCommand(startConsensusCommand command.StartConsensus) (bool, rpc.Error) {
-	seal := startConsensusCommand.Seal
-	txList := startConsensusCommand.TxList
-
-	proposedBlock, err := extractProposedBlock(seal, txList)
+	proposedBlock, err := extractProposedBlock(startConsensusCommand)
 	if err != nil {
 		return false, rpc.Error{Message: err.Error()}
 	}
@@ -53,18 +50,20 @@ func (r StartConsensusCommandHandler) HandleStartConsensusCommand(startConsensus
 	return true, rpc.Error{}
 }
 
-func extractProposedBlock(Seal []byte, TxList []command.Tx) (pbft.ProposedBlock, error) {
-	if Seal == nil {
+// proposed block의 body에는 command의 block 정보 전체를 담아
+// 합의가 끝난 후 모든 replica가 같은 block을 복원할 수 있도록 한다.
+func extractProposedBlock(startConsensusCommand command.StartConsensus) (pbft.ProposedBlock, error) {
+	if startConsensusCommand.Seal == nil {
 		return pbft.ProposedBlock{}, BlockSealIsNilError
 	}
 
-	body, err := common.Serialize(TxList)
+	body, err := common.Serialize(startConsensusCommand)
 	if err != nil {
 		return pbft.ProposedBlock{}, err
 	}
 
 	return pbft.ProposedBlock{
-		Seal: Seal,
+		Seal: startConsensusCommand.Seal,
 		Body: body,
 	}, nil
 }
@@ -35,11 +35,19 @@ func TestStartConsensusCommandHandler_extractProposedBlock(t *testing.T) {
 		})
 	}
 
+	startConsensusCommand := command.StartConsensus{
+		Seal:     expectedSeal,
+		PrevSeal: []byte{'p', 'r', 'e', 'v'},
+		Height:   1,
+		TxList:   expectedTxList,
+		Creator:  []byte("creator"),
+	}
+
 	// when
-	testBlock, err := extractProposedBlock(expectedSeal, expectedTxList)
+	testBlock, err := extractProposedBlock(startConsensusCommand)
 	assert.NoError(t, err)
 
-	expectedBody, err := common.Serialize(expectedTxList)
+	expectedBody, err := common.Serialize(startConsensusCommand)
 	assert.NoError(t, err)
 
 	// then
@@ -47,10 +55,10 @@ func TestStartConsensusCommandHandler_extractProposedBlock(t *testing.T) {
 	assert.Equal(t, expectedBody, testBlock.Body)
 
 	// given
-	expectedSeal = nil
+	startConsensusCommand.Seal = nil
 
 	// when
-	testBlock, err = extractProposedBlock(expectedSeal, expectedTxList)
+	testBlock, err = extractProposedBlock(startConsensusCommand)
 
 	// then
 	assert.Equal(t, BlockSealIsNilError, err)
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb
+
+import (
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+var stateKey = []byte("state")
+
+// 진행 중인 pbft state를 leveldb에 저장한다.
+// replica가 재시작 되면 저장된 state로부터 진행 중이던 라운드를 복구한다.
+type StateStore struct {
+	leveldb *leveldbwrapper.DB
+}
+
+func NewStateStore(path string) *StateStore {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+	return &StateStore{
+		leveldb: db,
+	}
+}
+
+func (s *StateStore) Save(state pbft.State) error {
+	b, err := common.Serialize(state)
+	if err != nil {
+		return err
+	}
+
+	// 재시작 후에도 state가 남아있도록 sync write 한다.
+	return s.leveldb.Put(stateKey, b, true)
+}
+
+func (s *StateStore) Load() (pbft.State, error) {
+	b, err := s.leveldb.Get(stateKey)
+	if err != nil {
+		return pbft.State{}, err
+	}
+
+	if len(b) == 0 {
+		return pbft.State{}, pbft.ErrEmptyRepo
+	}
+
+	state := pbft.State{}
+	if err := common.Deserialize(b, &state); err != nil {
+		return pbft.State{}, err
+	}
+
+	return state, nil
+}
+
+func (s *StateStore) Remove() error {
+	return s.leveldb.Delete(stateKey, true)
+}
+
+func (s *StateStore) Close() {
+	s.leveldb.Close()
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/infra/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateStore(t *testing.T) {
+	dbPath := "./.db"
+	defer os.RemoveAll(dbPath)
+
+	store := leveldb.NewStateStore(dbPath)
+
+	// case 1 : empty store
+	_, err := store.Load()
+	assert.Equal(t, pbft.ErrEmptyRepo, err)
+
+	// case 2 : save and load
+	state := pbft.State{
+		StateID:  pbft.NewStateID("state1"),
+		LeaderID: "leader",
+		Representatives: []*pbft.Representative{
+			pbft.NewRepresentative("leader"),
+			pbft.NewRepresentative("member1"),
+		},
+		Block: pbft.ProposedBlock{
+			Seal: []byte("seal"),
+			Body: []byte("body"),
+		},
+		CurrentStage:   pbft.PREPARE_STAGE,
+		PrepareMsgPool: pbft.NewPrepareMsgPool(),
+		CommitMsgPool:  pbft.NewCommitMsgPool(),
+	}
+	state.SavePrepareMsg(&pbft.PrepareMsg{
+		StateID:   pbft.NewStateID("state1"),
+		SenderID:  "member1",
+		BlockHash: []byte("seal"),
+	})
+
+	err = store.Save(state)
+	assert.NoError(t, err)
+
+	// case 3 : state is recovered after the store is reopened
+	store.Close()
+	store = leveldb.NewStateStore(dbPath)
+	defer store.Close()
+
+	loadedState, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "state1", loadedState.StateID.ID)
+	assert.Equal(t, "leader", loadedState.LeaderID)
+	assert.Equal(t, pbft.PREPARE_STAGE, loadedState.CurrentStage)
+	assert.Equal(t, []byte("seal"), loadedState.Block.Seal)
+	assert.Equal(t, 1, len(loadedState.PrepareMsgPool.Get()))
+
+	// case 4 : remove
+	err = store.Remove()
+	assert.NoError(t, err)
+
+	_, err = store.Load()
+	assert.Equal(t, pbft.ErrEmptyRepo, err)
+}
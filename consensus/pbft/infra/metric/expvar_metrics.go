@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metric
+
+import (
+	"github.com/go-kit/kit/metrics/expvar"
+	"github.com/it-chain/engine/consensus/pbft"
+)
+
+const latencyBuckets = 50
+
+// PBFT metric을 expvar로 publish 한다. api gateway의 "/debug/vars" 에서 조회할 수 있다.
+// expvar는 label을 지원하지 않으므로 거절된 msg는 종류와 관계없이 하나의 counter로 합산된다.
+// 같은 prefix로 두 번 생성하면 expvar가 panic 한다.
+func NewExpvarMetrics(prefix string) pbft.Metrics {
+	return pbft.Metrics{
+		RoundLatency:     expvar.NewHistogram(prefix+"_round_latency_seconds", latencyBuckets),
+		ViewChanges:      expvar.NewCounter(prefix + "_view_changes"),
+		RejectedMessages: expvar.NewCounter(prefix + "_rejected_messages"),
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metric_test
+
+import (
+	stdexpvar "expvar"
+	"testing"
+
+	"github.com/it-chain/engine/consensus/pbft/infra/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExpvarMetrics(t *testing.T) {
+
+	// given
+	metrics := metric.NewExpvarMetrics("test_pbft")
+
+	// when
+	metrics.ViewChanges.Add(1)
+	metrics.RejectedMessages.With("msg_type", "prepare").Add(2)
+	metrics.RoundLatency.Observe(0.5)
+
+	// then
+	assert.Equal(t, "1", stdexpvar.Get("test_pbft_view_changes").String())
+	assert.Equal(t, "2", stdexpvar.Get("test_pbft_rejected_messages").String())
+	assert.NotNil(t, stdexpvar.Get("test_pbft_round_latency_seconds.p50"))
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbft
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+)
+
+// 합의가 멈추거나 느려졌을 때 원인을 찾기 위한 metric
+type Metrics struct {
+	// pre-prepare 부터 block이 확정될 때까지 걸린 시간(초)
+	RoundLatency metrics.Histogram
+
+	// leader가 바뀌어 진행 중이던 라운드를 버린 횟수
+	ViewChanges metrics.Counter
+
+	// 저장되지 못하고 거절된 msg의 수, "msg_type" label로 pre-prepare, prepare, commit을 구분한다.
+	RejectedMessages metrics.Counter
+}
+
+// 아무것도 기록하지 않는 metric
+func NewDiscardMetrics() Metrics {
+	return Metrics{
+		RoundLatency:     discard.NewHistogram(),
+		ViewChanges:      discard.NewCounter(),
+		RejectedMessages: discard.NewCounter(),
+	}
+}
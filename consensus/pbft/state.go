@@ -17,6 +17,7 @@
 package pbft
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -37,6 +38,8 @@ var ErrPrepareMsgNil = errors.New("Prepare msg is nil")
 var ErrBlockHashNil = errors.New("Block hash is nil")
 var ErrCommitMsgNil = errors.New("Commit msg is nil")
 var ErrStateIdNotSame = errors.New("State ID is not same")
+var ErrBlockHashNotSame = errors.New("Block hash is not same with pre-prepared block")
+var ErrNotRepresentative = errors.New("Sender is not a representative")
 
 type ProposedBlock struct {
 	Seal []byte
@@ -131,14 +134,16 @@ func (p PrepareMsg) ToByte() ([]byte, error) {
 }
 
 type CommitMsg struct {
-	StateID  StateID
-	SenderID string
+	StateID   StateID
+	SenderID  string
+	BlockHash []byte
 }
 
 func NewCommitMsg(s *State, senderID string) *CommitMsg {
 	return &CommitMsg{
-		StateID:  s.StateID,
-		SenderID: senderID,
+		StateID:   s.StateID,
+		SenderID:  senderID,
+		BlockHash: s.Block.Seal,
 	}
 }
 
@@ -191,6 +196,15 @@ func (p *PrepareMsgPool) Get() []PrepareMsg {
 	return p.messages
 }
 
+// message log도 state와 함께 영속화 될 수 있도록 pool의 message들을 직렬화 한다.
+func (p PrepareMsgPool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.messages)
+}
+
+func (p *PrepareMsgPool) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &p.messages)
+}
+
 func (p *PrepareMsgPool) findIndexOfPrepareMsg(senderID string) int {
 	for i, msg := range p.messages {
 		if msg.SenderID == senderID {
@@ -223,6 +237,12 @@ func (c *CommitMsgPool) Save(commitMsg *CommitMsg) error {
 		return errors.New(fmt.Sprintf("Already exist member [%s]", senderID))
 	}
 
+	blockHash := commitMsg.BlockHash
+
+	if blockHash == nil {
+		return ErrBlockHashNil
+	}
+
 	c.messages = append(c.messages, *commitMsg)
 
 	return nil
@@ -236,6 +256,14 @@ func (c *CommitMsgPool) Get() []CommitMsg {
 	return c.messages
 }
 
+func (c CommitMsgPool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.messages)
+}
+
+func (c *CommitMsgPool) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &c.messages)
+}
+
 func (c *CommitMsgPool) findIndexOfCommitMsg(senderID string) int {
 	for i, msg := range c.messages {
 		if msg.SenderID == senderID {
@@ -258,6 +286,7 @@ func NewStateID(id string) StateID {
 
 type State struct {
 	StateID         StateID
+	LeaderID        string
 	Representatives []*Representative
 	Block           ProposedBlock
 	CurrentStage    Stage
@@ -301,11 +330,22 @@ func (s *State) ToIdleStage() {
 	s.CurrentStage = IDLE_STAGE
 }
 
+// prepare, commit msg는 pre-prepare 된 block의 seal과 같은 block hash를 가지고 있어야 한다.
+// 다른 block hash를 보낸 member는 equivocation으로 간주하고 msg를 저장하지 않는다.
+// representative가 아닌 member의 msg는 quorum에 포함되지 않도록 저장하지 않는다.
 func (s *State) SavePrepareMsg(prepareMsg *PrepareMsg) error {
 	if s.StateID.ID != prepareMsg.StateID.ID {
 		return ErrStateIdNotSame
 	}
 
+	if !s.IsRepresentative(prepareMsg.SenderID) {
+		return ErrNotRepresentative
+	}
+
+	if prepareMsg.BlockHash != nil && !s.IsSameBlock(prepareMsg.BlockHash) {
+		return ErrBlockHashNotSame
+	}
+
 	return s.PrepareMsgPool.Save(prepareMsg)
 }
 
@@ -314,25 +354,57 @@ func (s *State) SaveCommitMsg(commitMsg *CommitMsg) error {
 		return ErrStateIdNotSame
 	}
 
+	if !s.IsRepresentative(commitMsg.SenderID) {
+		return ErrNotRepresentative
+	}
+
+	if commitMsg.BlockHash != nil && !s.IsSameBlock(commitMsg.BlockHash) {
+		return ErrBlockHashNotSame
+	}
+
 	return s.CommitMsgPool.Save(commitMsg)
 }
-func (s *State) CheckPrepareCondition() bool {
-	representativeNum := len(s.Representatives)
-	commitMsgNum := len(s.PrepareMsgPool.Get())
-	satisfyNum := representativeNum / 3
 
-	if commitMsgNum > (satisfyNum + 1) {
-		return true
+func (s *State) IsSameBlock(blockHash []byte) bool {
+	return bytes.Equal(s.Block.Seal, blockHash)
+}
+
+func (s *State) IsRepresentative(id string) bool {
+	for _, r := range s.Representatives {
+		if r.GetID() == id {
+			return true
+		}
 	}
+
 	return false
 }
-func (s *State) CheckCommitCondition() bool {
-	representativeNum := len(s.Representatives)
-	commitMsgNum := len(s.CommitMsgPool.Get())
-	satisfyNum := representativeNum / 3
 
-	if commitMsgNum > (satisfyNum + 1) {
-		return true
+// representative가 n명일 때 f = (n-1)/3 명까지의 장애를 견딘다.
+func (s *State) faultTolerance() int {
+	return (len(s.Representatives) - 1) / 3
+}
+
+// f명이 응답하지 않아도 진행할 수 있도록 n-f명을 quorum으로 한다.
+// n >= 3f+1 이므로 두 quorum은 적어도 n-2f >= f+1명의 representative에서 겹친다. n = 3f+1 이면 2f+1과 같다.
+func (s *State) quorum() int {
+	return len(s.Representatives) - s.faultTolerance()
+}
+
+// pre-prepare를 leader의 prepare로 보고, leader가 아닌 서로 다른 quorum-1명의 representative로부터
+// pre-prepare 된 block과 같은 block hash를 가진 prepare msg를 받으면 prepared 상태가 된다.
+// 자신이 보낸 prepare msg도 포함한다.
+func (s *State) CheckPrepareCondition() bool {
+	prepared := 0
+	for _, msg := range s.PrepareMsgPool.Get() {
+		if msg.SenderID != s.LeaderID {
+			prepared++
+		}
 	}
-	return false
+
+	return prepared >= s.quorum()-1
+}
+
+// 자신을 포함한 quorum명의 representative로부터 commit msg를 받으면 block을 확정한다.
+func (s *State) CheckCommitCondition() bool {
+	return len(s.CommitMsgPool.Get()) >= s.quorum()
 }
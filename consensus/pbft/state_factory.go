@@ -17,11 +17,15 @@
 package pbft
 
 import (
+	"errors"
+
 	"github.com/rs/xid"
 )
 
+var ErrRepresentativesNotSame = errors.New("Representatives are not same with local validators")
+
 // leader
-func NewState(parliament []MemberID, block ProposedBlock) (*State, error) {
+func NewState(leaderID string, parliament []MemberID, block ProposedBlock) (*State, error) {
 	representatives, err := Elect(parliament)
 	if err != nil {
 		return &State{}, err
@@ -29,6 +33,7 @@ func NewState(parliament []MemberID, block ProposedBlock) (*State, error) {
 
 	newState := State{
 		StateID:         NewStateID(xid.New().String()),
+		LeaderID:        leaderID,
 		Representatives: representatives,
 		Block:           block,
 		CurrentStage:    IDLE_STAGE,
@@ -40,10 +45,22 @@ func NewState(parliament []MemberID, block ProposedBlock) (*State, error) {
 }
 
 // member
-func BuildState(msg PrePrepareMsg) (*State, error) {
+// representative는 leader가 보낸 목록이 아니라 자신의 parliament로부터 선출하며,
+// 두 목록이 다르면 quorum을 다르게 세게 되므로 pre-prepare를 거부한다.
+func BuildState(msg PrePrepareMsg, parliament []MemberID) (*State, error) {
+	representatives, err := Elect(parliament)
+	if err != nil {
+		return &State{}, err
+	}
+
+	if !isSameRepresentatives(representatives, msg.Representative) {
+		return &State{}, ErrRepresentativesNotSame
+	}
+
 	newState := &State{
 		StateID:         msg.StateID,
-		Representatives: msg.Representative,
+		LeaderID:        msg.SenderID,
+		Representatives: representatives,
 		Block:           msg.ProposedBlock,
 		CurrentStage:    IDLE_STAGE,
 		PrepareMsgPool:  NewPrepareMsgPool(),
@@ -52,3 +69,22 @@ func BuildState(msg PrePrepareMsg) (*State, error) {
 
 	return newState, nil
 }
+
+func isSameRepresentatives(a []*Representative, b []*Representative) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	ids := make(map[string]struct{})
+	for _, r := range a {
+		ids[r.GetID()] = struct{}{}
+	}
+
+	for _, r := range b {
+		if _, ok := ids[r.GetID()]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
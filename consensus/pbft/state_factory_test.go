@@ -34,7 +34,7 @@ func TestCreateConsensus(t *testing.T) {
 	}
 
 	// when
-	c, err := pbft.NewState(p, b)
+	c, err := pbft.NewState("leader", p, b)
 
 	// then
 	assert.Error(t, err)
@@ -43,11 +43,12 @@ func TestCreateConsensus(t *testing.T) {
 	p = append(p, l)
 	p = append(p, m)
 
-	c, err = pbft.NewState(p, b)
+	c, err = pbft.NewState("leader", p, b)
 
 	// then
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(c.Representatives))
+	assert.Equal(t, "leader", c.LeaderID)
 	assert.Equal(t, b.Seal, c.Block.Seal)
 	assert.Equal(t, b.Body, c.Block.Body)
 }
@@ -71,11 +72,24 @@ func TestConstructConsensus(t *testing.T) {
 	}
 
 	// when
-	c, err := pbft.BuildState(msg)
+	c, err := pbft.BuildState(msg, []pbft.MemberID{"member", "leader"})
 
 	// then
 	assert.NoError(t, err)
 	assert.Equal(t, "consensusID", c.StateID.ID)
+	assert.Equal(t, "me", c.LeaderID)
 	assert.Equal(t, pbft.IDLE_STAGE, c.CurrentStage)
 	assert.Equal(t, 2, len(c.Representatives))
+
+	// when : leader가 보낸 representative에 local validator가 아닌 member가 있는 경우
+	_, err = pbft.BuildState(msg, []pbft.MemberID{"member", "other"})
+
+	// then
+	assert.Equal(t, pbft.ErrRepresentativesNotSame, err)
+
+	// when : leader가 local validator 일부를 빠뜨린 경우
+	_, err = pbft.BuildState(msg, []pbft.MemberID{"member", "leader", "other"})
+
+	// then
+	assert.Equal(t, pbft.ErrRepresentativesNotSame, err)
 }
@@ -23,8 +23,16 @@ import (
 var ErrInvalidSave = errors.New("Invalid Save Error")
 var ErrEmptyRepo = errors.New("Repository has empty state")
 
+// replica가 재시작되어도 진행 중이던 state(view, sequence, message log)를 잃지 않도록 state를 영속화하는 저장소
+type StateStore interface {
+	Save(state State) error
+	Load() (State, error)
+	Remove() error
+}
+
 type StateRepository struct {
 	state State
+	store StateStore
 	sync.RWMutex
 }
 
@@ -34,12 +42,37 @@ func NewStateRepository() StateRepository {
 		RWMutex: sync.RWMutex{},
 	}
 }
+
+// store에 저장되어 있던 state를 복구한 repository를 생성한다.
+// 이후 repository의 모든 변경은 store에 먼저 기록된다.
+func NewPersistentStateRepository(store StateStore) (StateRepository, error) {
+	state, err := store.Load()
+	if err != nil && err != ErrEmptyRepo {
+		return StateRepository{}, err
+	}
+
+	if err == ErrEmptyRepo {
+		state = State{}
+	}
+
+	return StateRepository{
+		state:   state,
+		store:   store,
+		RWMutex: sync.RWMutex{},
+	}, nil
+}
+
 func (repo *StateRepository) Save(state State) error {
 
 	repo.Lock()
 	defer repo.Unlock()
 	id := repo.state.StateID.ID
 	if id == state.StateID.ID || id == "" {
+		if repo.store != nil {
+			if err := repo.store.Save(state); err != nil {
+				return err
+			}
+		}
 		repo.state = state
 		return nil
 	}
@@ -47,6 +80,8 @@ func (repo *StateRepository) Save(state State) error {
 }
 func (repo *StateRepository) Load() (State, error) {
 
+	repo.RLock()
+	defer repo.RUnlock()
 	if repo.state.StateID.ID == "" {
 		return repo.state, ErrEmptyRepo
 	}
@@ -54,6 +89,15 @@ func (repo *StateRepository) Load() (State, error) {
 	return repo.state, nil
 }
 
-func (repo *StateRepository) Remove() {
+func (repo *StateRepository) Remove() error {
+
+	repo.Lock()
+	defer repo.Unlock()
+	if repo.store != nil {
+		if err := repo.store.Remove(); err != nil {
+			return err
+		}
+	}
 	repo.state = State{}
+	return nil
 }
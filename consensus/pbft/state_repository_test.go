@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/test/mock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +67,53 @@ func TestConsensusRepository_Remove(t *testing.T) {
 	assert.Equal(t, pbft.ErrEmptyRepo, err)
 
 }
+
+func TestNewPersistentStateRepository(t *testing.T) {
+	// given
+	var storedState *pbft.State
+
+	store := mock.MockStateStore{}
+	store.LoadFunc = func() (pbft.State, error) {
+		if storedState == nil {
+			return pbft.State{}, pbft.ErrEmptyRepo
+		}
+		return *storedState, nil
+	}
+	store.SaveFunc = func(state pbft.State) error {
+		storedState = &state
+		return nil
+	}
+	store.RemoveFunc = func() error {
+		storedState = nil
+		return nil
+	}
+
+	// case 1 : store has no state
+	repo, err := pbft.NewPersistentStateRepository(store)
+	assert.NoError(t, err)
+
+	_, err = repo.Load()
+	assert.Equal(t, pbft.ErrEmptyRepo, err)
+
+	// case 2 : saved state is written to store
+	err = repo.Save(pbft.State{
+		StateID:      pbft.StateID{"state1"},
+		CurrentStage: pbft.PREPARE_STAGE,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "state1", storedState.StateID.ID)
+
+	// case 3 : restarted repository recovers the state from store
+	recoveredRepo, err := pbft.NewPersistentStateRepository(store)
+	assert.NoError(t, err)
+
+	recoveredState, err := recoveredRepo.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "state1", recoveredState.StateID.ID)
+	assert.Equal(t, pbft.PREPARE_STAGE, recoveredState.CurrentStage)
+
+	// case 4 : removed state is removed from store
+	err = recoveredRepo.Remove()
+	assert.NoError(t, err)
+	assert.Nil(t, storedState)
+}
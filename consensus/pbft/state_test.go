@@ -19,6 +19,9 @@ package pbft_test
 import (
 	"testing"
 
+	"encoding/json"
+	"fmt"
+
 	"github.com/it-chain/engine/consensus/pbft"
 	"github.com/stretchr/testify/assert"
 )
@@ -105,8 +108,9 @@ func TestCommitMsgPool_Save(t *testing.T) {
 
 	// case 1 : save
 	cMsg := pbft.CommitMsg{
-		StateID:  pbft.StateID{"c1"},
-		SenderID: "s1",
+		StateID:   pbft.StateID{"c1"},
+		SenderID:  "s1",
+		BlockHash: make([]byte, 0),
 	}
 
 	// when
@@ -117,8 +121,9 @@ func TestCommitMsgPool_Save(t *testing.T) {
 
 	// case 2 : save
 	cMsg = pbft.CommitMsg{
-		StateID:  pbft.StateID{"c1"},
-		SenderID: "s2",
+		StateID:   pbft.StateID{"c1"},
+		SenderID:  "s2",
+		BlockHash: make([]byte, 0),
 	}
 
 	// when
@@ -129,8 +134,9 @@ func TestCommitMsgPool_Save(t *testing.T) {
 
 	// case 3 : same sender
 	cMsg = pbft.CommitMsg{
-		StateID:  pbft.StateID{"c1"},
-		SenderID: "s2",
+		StateID:   pbft.StateID{"c1"},
+		SenderID:  "s2",
+		BlockHash: make([]byte, 0),
 	}
 
 	// when
@@ -145,8 +151,9 @@ func TestCommitMsgPool_RemoveAllMsgs(t *testing.T) {
 	cPool := pbft.NewCommitMsgPool()
 
 	cMsg := pbft.CommitMsg{
-		StateID:  pbft.StateID{"c1"},
-		SenderID: "s1",
+		StateID:   pbft.StateID{"c1"},
+		SenderID:  "s1",
+		BlockHash: make([]byte, 0),
 	}
 
 	cPool.Save(&cMsg)
@@ -162,7 +169,7 @@ func TestConsensus_SavePrepareMsg(t *testing.T) {
 	// given
 	c := pbft.State{
 		StateID:         pbft.NewStateID("c1"),
-		Representatives: nil,
+		Representatives: []*pbft.Representative{pbft.NewRepresentative("s1"), pbft.NewRepresentative("s2")},
 		Block: pbft.ProposedBlock{
 			Seal: make([]byte, 0),
 		},
@@ -199,13 +206,40 @@ func TestConsensus_SavePrepareMsg(t *testing.T) {
 	//then
 	assert.Error(t, err)
 	assert.Equal(t, 1, len(c.PrepareMsgPool.Get()))
+	// case 3 : block hash is not same with pre-prepared block
+	pMsg = &pbft.PrepareMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s2",
+		BlockHash: []byte("another block"),
+	}
+
+	// when
+	err = c.SavePrepareMsg(pMsg)
+
+	// then
+	assert.Equal(t, pbft.ErrBlockHashNotSame, err)
+	assert.Equal(t, 1, len(c.PrepareMsgPool.Get()))
+
+	// case 4 : sender is not a representative
+	pMsg = &pbft.PrepareMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s3",
+		BlockHash: make([]byte, 0),
+	}
+
+	// when
+	err = c.SavePrepareMsg(pMsg)
+
+	// then
+	assert.Equal(t, pbft.ErrNotRepresentative, err)
+	assert.Equal(t, 1, len(c.PrepareMsgPool.Get()))
 }
 
 func TestConsensus_SaveCommitMsg(t *testing.T) {
 	// given
 	c := pbft.State{
 		StateID:         pbft.NewStateID("c1"),
-		Representatives: nil,
+		Representatives: []*pbft.Representative{pbft.NewRepresentative("s1"), pbft.NewRepresentative("s2")},
 		Block: pbft.ProposedBlock{
 			Seal: make([]byte, 0),
 		},
@@ -216,8 +250,9 @@ func TestConsensus_SaveCommitMsg(t *testing.T) {
 
 	// case 1 : save
 	cMsg := &pbft.CommitMsg{
-		StateID:  pbft.NewStateID("c1"),
-		SenderID: "s1",
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s1",
+		BlockHash: make([]byte, 0),
 	}
 
 	// when
@@ -230,8 +265,9 @@ func TestConsensus_SaveCommitMsg(t *testing.T) {
 
 	// case 2 : incorrect consensus ID
 	cMsg = &pbft.CommitMsg{
-		StateID:  pbft.NewStateID("c2"),
-		SenderID: "s1",
+		StateID:   pbft.NewStateID("c2"),
+		SenderID:  "s1",
+		BlockHash: make([]byte, 0),
 	}
 
 	// when
@@ -240,4 +276,259 @@ func TestConsensus_SaveCommitMsg(t *testing.T) {
 	//then
 	assert.Error(t, err)
 	assert.Equal(t, 1, len(c.CommitMsgPool.Get()))
+	// case 3 : block hash is not same with pre-prepared block
+	cMsg = &pbft.CommitMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s2",
+		BlockHash: []byte("another block"),
+	}
+
+	// when
+	err = c.SaveCommitMsg(cMsg)
+
+	// then
+	assert.Equal(t, pbft.ErrBlockHashNotSame, err)
+	assert.Equal(t, 1, len(c.CommitMsgPool.Get()))
+
+	// case 4 : sender is not a representative
+	cMsg = &pbft.CommitMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s3",
+		BlockHash: make([]byte, 0),
+	}
+
+	// when
+	err = c.SaveCommitMsg(cMsg)
+
+	// then
+	assert.Equal(t, pbft.ErrNotRepresentative, err)
+	assert.Equal(t, 1, len(c.CommitMsgPool.Get()))
+}
+
+func TestState_Serialize(t *testing.T) {
+	// given
+	s := pbft.State{
+		StateID:         pbft.NewStateID("c1"),
+		LeaderID:        "leader",
+		Representatives: []*pbft.Representative{pbft.NewRepresentative("s1"), pbft.NewRepresentative("s2")},
+		Block: pbft.ProposedBlock{
+			Seal: []byte("seal"),
+			Body: []byte("body"),
+		},
+		CurrentStage:   pbft.COMMIT_STAGE,
+		PrepareMsgPool: pbft.NewPrepareMsgPool(),
+		CommitMsgPool:  pbft.NewCommitMsgPool(),
+	}
+
+	s.SavePrepareMsg(&pbft.PrepareMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s1",
+		BlockHash: []byte("seal"),
+	})
+	s.SaveCommitMsg(&pbft.CommitMsg{
+		StateID:   pbft.NewStateID("c1"),
+		SenderID:  "s2",
+		BlockHash: []byte("seal"),
+	})
+
+	// when
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+
+	deserialized := pbft.State{}
+	err = json.Unmarshal(data, &deserialized)
+	assert.NoError(t, err)
+
+	// then
+	assert.Equal(t, "leader", deserialized.LeaderID)
+	assert.Equal(t, pbft.COMMIT_STAGE, deserialized.CurrentStage)
+	assert.Equal(t, 1, len(deserialized.PrepareMsgPool.Get()))
+	assert.Equal(t, "s1", deserialized.PrepareMsgPool.Get()[0].SenderID)
+	assert.Equal(t, 1, len(deserialized.CommitMsgPool.Get()))
+	assert.Equal(t, "s2", deserialized.CommitMsgPool.Get()[0].SenderID)
+}
+
+func TestState_CheckCondition(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			representativeNum int
+			prepareMsgNum     int
+			commitMsgNum      int
+		}
+		output struct {
+			isPrepared  bool
+			isCommitted bool
+		}
+	}{
+		"4 representatives (f=1), 2 prepare, 3 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{4, 2, 3},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{true, true},
+		},
+		"4 representatives (f=1), 1 prepare, 2 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{4, 1, 2},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{false, false},
+		},
+		"7 representatives (f=2), 4 prepare, 5 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{7, 4, 5},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{true, true},
+		},
+		"7 representatives (f=2), 3 prepare, 4 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{7, 3, 4},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{false, false},
+		},
+		"3 representatives (f=0), 2 prepare, 3 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{3, 2, 3},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{true, true},
+		},
+		"3 representatives (f=0), 1 prepare, 2 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{3, 1, 2},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{false, false},
+		},
+		"5 representatives (f=1), 3 prepare, 4 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{5, 3, 4},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{true, true},
+		},
+		"6 representatives (f=1), 4 prepare, 5 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{6, 4, 5},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{true, true},
+		},
+		"6 representatives (f=1), 3 prepare, 4 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{6, 3, 4},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{false, false},
+		},
+		"5 representatives (f=1), 2 prepare, 3 commit": {
+			input: struct {
+				representativeNum int
+				prepareMsgNum     int
+				commitMsgNum      int
+			}{5, 2, 3},
+			output: struct {
+				isPrepared  bool
+				isCommitted bool
+			}{false, false},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		s := pbft.State{
+			StateID:        pbft.NewStateID("c1"),
+			LeaderID:       "r0",
+			Block:          pbft.ProposedBlock{Seal: []byte("seal")},
+			PrepareMsgPool: pbft.NewPrepareMsgPool(),
+			CommitMsgPool:  pbft.NewCommitMsgPool(),
+		}
+
+		for i := 0; i < test.input.representativeNum; i++ {
+			s.Representatives = append(s.Representatives, pbft.NewRepresentative(fmt.Sprintf("r%d", i)))
+		}
+
+		// leader는 prepare msg를 보내지 않으므로 r0를 제외한 representative가 prepare msg를 보낸다.
+		for i := 1; i <= test.input.prepareMsgNum; i++ {
+			s.SavePrepareMsg(&pbft.PrepareMsg{StateID: s.StateID, SenderID: fmt.Sprintf("r%d", i), BlockHash: []byte("seal")})
+		}
+
+		for i := 0; i < test.input.commitMsgNum; i++ {
+			s.SaveCommitMsg(&pbft.CommitMsg{StateID: s.StateID, SenderID: fmt.Sprintf("r%d", i), BlockHash: []byte("seal")})
+		}
+
+		// when & then
+		assert.Equal(t, test.output.isPrepared, s.CheckPrepareCondition())
+		assert.Equal(t, test.output.isCommitted, s.CheckCommitCondition())
+	}
+}
+
+func TestState_CheckPrepareCondition_ExcludesLeader(t *testing.T) {
+
+	// given
+	s := pbft.State{
+		StateID:        pbft.NewStateID("c1"),
+		LeaderID:       "r0",
+		Block:          pbft.ProposedBlock{Seal: []byte("seal")},
+		PrepareMsgPool: pbft.NewPrepareMsgPool(),
+		CommitMsgPool:  pbft.NewCommitMsgPool(),
+	}
+
+	for i := 0; i < 4; i++ {
+		s.Representatives = append(s.Representatives, pbft.NewRepresentative(fmt.Sprintf("r%d", i)))
+	}
+
+	// when : pre-prepare를 보낸 leader의 prepare msg는 quorum에 두 번 포함되지 않는다.
+	s.SavePrepareMsg(&pbft.PrepareMsg{StateID: s.StateID, SenderID: "r0", BlockHash: []byte("seal")})
+	s.SavePrepareMsg(&pbft.PrepareMsg{StateID: s.StateID, SenderID: "r1", BlockHash: []byte("seal")})
+
+	// then
+	assert.False(t, s.CheckPrepareCondition())
+
+	// when
+	s.SavePrepareMsg(&pbft.PrepareMsg{StateID: s.StateID, SenderID: "r2", BlockHash: []byte("seal")})
+
+	// then
+	assert.True(t, s.CheckPrepareCondition())
 }
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbft
+
+import "sort"
+
+// 진행 중인 state를 외부에서 조회하기 위한 형태
+// view는 LeaderID, sequence는 StateID 이며, 어떤 representative가 prepare, commit msg를 보냈는지 보여준다.
+type StateView struct {
+	StateID         string
+	LeaderID        string
+	Stage           Stage
+	Representatives []string
+	PrepareSenders  []string
+	CommitSenders   []string
+}
+
+func NewStateView(state State) StateView {
+
+	stage := state.CurrentStage
+	if stage == "" {
+		stage = IDLE_STAGE
+	}
+
+	view := StateView{
+		StateID:         state.StateID.ID,
+		LeaderID:        state.LeaderID,
+		Stage:           stage,
+		Representatives: make([]string, 0),
+		PrepareSenders:  make([]string, 0),
+		CommitSenders:   make([]string, 0),
+	}
+
+	for _, r := range state.Representatives {
+		view.Representatives = append(view.Representatives, r.GetID())
+	}
+
+	for _, msg := range state.PrepareMsgPool.Get() {
+		view.PrepareSenders = append(view.PrepareSenders, msg.SenderID)
+	}
+
+	for _, msg := range state.CommitMsgPool.Get() {
+		view.CommitSenders = append(view.CommitSenders, msg.SenderID)
+	}
+
+	sort.Strings(view.Representatives)
+	sort.Strings(view.PrepareSenders)
+	sort.Strings(view.CommitSenders)
+
+	return view
+}
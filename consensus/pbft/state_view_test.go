@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbft_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStateView(t *testing.T) {
+
+	// given
+	state := pbft.State{
+		StateID:  pbft.StateID{"state1"},
+		LeaderID: "leader",
+		Representatives: []*pbft.Representative{
+			pbft.NewRepresentative("user2"),
+			pbft.NewRepresentative("leader"),
+			pbft.NewRepresentative("user1"),
+		},
+		Block:          pbft.ProposedBlock{Seal: []byte("seal")},
+		CurrentStage:   pbft.COMMIT_STAGE,
+		PrepareMsgPool: pbft.NewPrepareMsgPool(),
+		CommitMsgPool:  pbft.NewCommitMsgPool(),
+	}
+
+	state.SavePrepareMsg(&pbft.PrepareMsg{StateID: state.StateID, SenderID: "user2", BlockHash: []byte("seal")})
+	state.SavePrepareMsg(&pbft.PrepareMsg{StateID: state.StateID, SenderID: "user1", BlockHash: []byte("seal")})
+	state.SaveCommitMsg(&pbft.CommitMsg{StateID: state.StateID, SenderID: "user1", BlockHash: []byte("seal")})
+
+	tests := map[string]struct {
+		input  pbft.State
+		output pbft.StateView
+	}{
+		"state in progress": {
+			input: state,
+			output: pbft.StateView{
+				StateID:         "state1",
+				LeaderID:        "leader",
+				Stage:           pbft.COMMIT_STAGE,
+				Representatives: []string{"leader", "user1", "user2"},
+				PrepareSenders:  []string{"user1", "user2"},
+				CommitSenders:   []string{"user1"},
+			},
+		},
+		"empty state": {
+			input: pbft.State{},
+			output: pbft.StateView{
+				Stage:           pbft.IDLE_STAGE,
+				Representatives: []string{},
+				PrepareSenders:  []string{},
+				CommitSenders:   []string{},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		view := pbft.NewStateView(test.input)
+
+		// then
+		assert.Equal(t, test.output, view)
+	}
+}
@@ -33,29 +33,21 @@ func (m EventService) ConfirmBlock(block pbft.ProposedBlock) error {
 	return m.ConfirmBlockFunc(block)
 }
 
-type MockConfirmService struct {
-	ConfirmBlockFunc func(block pbft.ProposedBlock) error
-}
-
-func (m MockConfirmService) ConfirmBlock(block pbft.ProposedBlock) error {
-	return m.ConfirmBlockFunc(block)
-}
-
 type MockPropagateService struct {
-	BroadcastPrepareMsgFunc    func(msg pbft.PrepareMsg) error
-	BroadcastPrePrepareMsgFunc func(msg pbft.PrePrepareMsg) error
-	BroadcastCommitMsgFunc     func(msg pbft.CommitMsg) error
+	BroadcastPrepareMsgFunc    func(msg pbft.PrepareMsg, representatives []*pbft.Representative) error
+	BroadcastPrePrepareMsgFunc func(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error
+	BroadcastCommitMsgFunc     func(msg pbft.CommitMsg, representatives []*pbft.Representative) error
 }
 
-func (m MockPropagateService) BroadcastPrepareMsg(msg pbft.PrepareMsg) error {
-	return m.BroadcastPrepareMsgFunc(msg)
+func (m MockPropagateService) BroadcastPrepareMsg(msg pbft.PrepareMsg, representatives []*pbft.Representative) error {
+	return m.BroadcastPrepareMsgFunc(msg, representatives)
 }
 
-func (m MockPropagateService) BroadcastPrePrepareMsg(msg pbft.PrePrepareMsg) error {
-	return m.BroadcastPrePrepareMsgFunc(msg)
+func (m MockPropagateService) BroadcastPrePrepareMsg(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
+	return m.BroadcastPrePrepareMsgFunc(msg, representatives)
 }
-func (m MockPropagateService) BroadcastCommitMsg(msg pbft.CommitMsg) error {
-	return m.BroadcastCommitMsgFunc(msg)
+func (m MockPropagateService) BroadcastCommitMsg(msg pbft.CommitMsg, representatives []*pbft.Representative) error {
+	return m.BroadcastCommitMsgFunc(msg, representatives)
 }
 
 type MockParliamentService struct {
@@ -73,3 +65,21 @@ func (m MockParliamentService) RequestPeerList() ([]pbft.MemberID, error) {
 func (m MockParliamentService) IsNeedConsensus() bool {
 	return m.IsNeedConsensusFunc()
 }
+
+type MockStateStore struct {
+	SaveFunc   func(state pbft.State) error
+	LoadFunc   func() (pbft.State, error)
+	RemoveFunc func() error
+}
+
+func (m MockStateStore) Save(state pbft.State) error {
+	return m.SaveFunc(state)
+}
+
+func (m MockStateStore) Load() (pbft.State, error) {
+	return m.LoadFunc()
+}
+
+func (m MockStateStore) Remove() error {
+	return m.RemoveFunc()
+}
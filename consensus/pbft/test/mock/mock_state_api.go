@@ -25,6 +25,7 @@ type MockStateApi struct {
 	HandlePrePrepareMsgFunc func(msg pbft.PrePrepareMsg) error
 	HandlePrepareMsgFunc    func(msg pbft.PrepareMsg) error
 	HandleCommitMsgFunc     func(msg pbft.CommitMsg) error
+	RecoverStateFunc        func() error
 }
 
 func (mca *MockStateApi) StartConsensus(proposedBlock pbft.ProposedBlock) error {
@@ -44,3 +45,8 @@ func (mca *MockStateApi) HandleCommitMsg(msg pbft.CommitMsg) error {
 
 	return mca.HandleCommitMsgFunc(msg)
 }
+
+func (mca *MockStateApi) RecoverState() error {
+
+	return mca.RecoverStateFunc()
+}
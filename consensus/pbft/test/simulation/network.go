@@ -0,0 +1,313 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/api"
+	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
+)
+
+// replica 사이를 오가는 pre-prepare, prepare, commit msg
+// Msg는 pbft.PrePrepareMsg, pbft.PrepareMsg, pbft.CommitMsg 중 하나이다.
+// From은 grpc gateway가 인증한 connection id와 같이 실제로 보낸 replica이며, Tamper로 바꿀 수 없다.
+type Message struct {
+	From string
+	To   string
+	Msg  interface{}
+}
+
+// msg를 보낼 때 적용되는 규칙
+// drop이 true이면 msg를 버리고, 그렇지 않으면 delay round 만큼 늦게 전달한다.
+type Rule func(msg Message) (drop bool, delay int)
+
+// byzantine replica가 보내는 msg를 바꾼다. 반환한 msg들이 대신 전달된다.
+type Tamper func(msg Message) []Message
+
+// StateApiImpl 하나와 그 replica가 확정한 block 목록
+type Replica struct {
+	ID        string
+	StateApi  *api.StateApiImpl
+	Handler   adapter.GrpcCommandHandler
+	Repo      *pbft.StateRepository
+	Confirmed []pbft.ProposedBlock
+	Errors    []error
+	crashed   bool
+	tamper    Tamper
+}
+
+type scheduledMessage struct {
+	Message
+	round int
+}
+
+// 여러 StateApiImpl을 RabbitMQ 없이 메모리 안에서 연결하는 network
+// msg는 round 단위로 전달된다. round k에서 보낸 msg는 지연이 없으면 round k+1에 전달된다.
+// 같은 round 안의 전달 순서는 seed로 섞을 수 있으며, 같은 seed에 대해서는 항상 같은 결과를 낸다.
+type Network struct {
+	leaderID string
+	ids      []string
+	replicas map[string]*Replica
+	queue    []scheduledMessage
+	rules    []Rule
+	rand     *rand.Rand
+	round    int
+}
+
+func NewNetwork(leaderID string, memberIDs []string) *Network {
+	ids := append([]string{}, memberIDs...)
+	sort.Strings(ids)
+
+	n := &Network{
+		leaderID: leaderID,
+		ids:      ids,
+		replicas: make(map[string]*Replica),
+		queue:    make([]scheduledMessage, 0),
+		rules:    make([]Rule, 0),
+	}
+
+	for _, id := range ids {
+		repo := pbft.NewStateRepository()
+		replica := &Replica{
+			ID:        id,
+			Repo:      &repo,
+			Confirmed: make([]pbft.ProposedBlock, 0),
+			Errors:    make([]error, 0),
+		}
+
+		stateApi := api.NewStateApi(id, &propagateService{network: n, senderID: id}, &eventService{replica: replica}, &parliamentService{network: n}, &repo, pbft.NewDiscardMetrics())
+		replica.StateApi = &stateApi
+		replica.Handler = adapter.NewGrpcCommandHandler(&stateApi)
+
+		n.replicas[id] = replica
+	}
+
+	return n
+}
+
+func (n *Network) Replica(id string) *Replica {
+	return n.replicas[id]
+}
+
+// id 순서로 정렬된 replica 목록
+func (n *Network) Replicas() []*Replica {
+	replicas := make([]*Replica, 0)
+	for _, id := range n.ids {
+		replicas = append(replicas, n.replicas[id])
+	}
+
+	return replicas
+}
+
+// crash 된 replica는 msg를 보내지도 받지도 않는다.
+func (n *Network) Crash(id string) {
+	n.replicas[id].crashed = true
+}
+
+func (n *Network) IsCrashed(id string) bool {
+	return n.replicas[id].crashed
+}
+
+func (n *Network) Byzantine(id string, tamper Tamper) {
+	n.replicas[id].tamper = tamper
+}
+
+func (n *Network) IsByzantine(id string) bool {
+	return n.replicas[id].tamper != nil
+}
+
+func (n *Network) AddRule(rule Rule) {
+	n.rules = append(n.rules, rule)
+}
+
+// 같은 round에 전달될 msg들의 순서를 seed에 따라 섞는다.
+func (n *Network) Shuffle(seed int64) {
+	n.rand = rand.New(rand.NewSource(seed))
+}
+
+// leader가 block을 제안한다.
+func (n *Network) Propose(block pbft.ProposedBlock) error {
+	return n.replicas[n.leaderID].StateApi.StartConsensus(block)
+}
+
+// 전달할 msg가 없을 때까지 또는 maxRound 까지 round를 진행하고, 진행한 round 수를 반환한다.
+func (n *Network) Run(maxRound int) int {
+	for i := 0; i < maxRound; i++ {
+		if len(n.queue) == 0 {
+			return i
+		}
+
+		n.round++
+		for _, msg := range n.takeRound() {
+			n.deliver(msg)
+		}
+	}
+
+	return maxRound
+}
+
+func (n *Network) takeRound() []Message {
+	current := make([]Message, 0)
+	remained := make([]scheduledMessage, 0)
+
+	for _, msg := range n.queue {
+		if msg.round <= n.round {
+			current = append(current, msg.Message)
+			continue
+		}
+
+		remained = append(remained, msg)
+	}
+
+	n.queue = remained
+
+	if n.rand != nil {
+		n.rand.Shuffle(len(current), func(i, j int) {
+			current[i], current[j] = current[j], current[i]
+		})
+	}
+
+	return current
+}
+
+func (n *Network) deliver(msg Message) {
+	replica, ok := n.replicas[msg.To]
+	if !ok || replica.crashed {
+		return
+	}
+
+	// grpc gateway에서 받은 것과 같이 command handler를 거쳐 state api로 전달한다.
+	protocol := ""
+	switch msg.Msg.(type) {
+	case pbft.PrePrepareMsg:
+		protocol = "PrePrepareMsgProtocol"
+	case pbft.PrepareMsg:
+		protocol = "PrepareMsgProtocol"
+	case pbft.CommitMsg:
+		protocol = "CommitMsgProtocol"
+	}
+
+	body, err := json.Marshal(msg.Msg)
+	if err == nil {
+		err = replica.Handler.HandleGrpcCommand(command.ReceiveGrpc{
+			ConnectionID: msg.From,
+			Protocol:     protocol,
+			Body:         body,
+		})
+	}
+
+	if err != nil {
+		replica.Errors = append(replica.Errors, err)
+	}
+}
+
+// grpc gateway와 같이 자기 자신을 제외한 representative에게 msg를 보낸다.
+func (n *Network) send(senderID string, msg interface{}, representatives []*pbft.Representative) {
+	sender := n.replicas[senderID]
+	if sender.crashed {
+		return
+	}
+
+	for _, r := range representatives {
+		if r.GetID() == senderID {
+			continue
+		}
+
+		messages := []Message{{From: senderID, To: r.GetID(), Msg: msg}}
+		if sender.tamper != nil {
+			messages = sender.tamper(messages[0])
+		}
+
+		for _, m := range messages {
+			m.From = senderID
+			n.schedule(m)
+		}
+	}
+}
+
+func (n *Network) schedule(msg Message) {
+	delay := 0
+
+	for _, rule := range n.rules {
+		drop, d := rule(msg)
+		if drop {
+			return
+		}
+
+		delay += d
+	}
+
+	n.queue = append(n.queue, scheduledMessage{
+		Message: msg,
+		round:   n.round + 1 + delay,
+	})
+}
+
+type propagateService struct {
+	network  *Network
+	senderID string
+}
+
+func (ps *propagateService) BroadcastPrePrepareMsg(msg pbft.PrePrepareMsg, representatives []*pbft.Representative) error {
+	ps.network.send(ps.senderID, msg, representatives)
+	return nil
+}
+
+func (ps *propagateService) BroadcastPrepareMsg(msg pbft.PrepareMsg, representatives []*pbft.Representative) error {
+	ps.network.send(ps.senderID, msg, representatives)
+	return nil
+}
+
+func (ps *propagateService) BroadcastCommitMsg(msg pbft.CommitMsg, representatives []*pbft.Representative) error {
+	ps.network.send(ps.senderID, msg, representatives)
+	return nil
+}
+
+type parliamentService struct {
+	network *Network
+}
+
+func (ps *parliamentService) RequestLeader() (pbft.MemberID, error) {
+	return pbft.MemberID(ps.network.leaderID), nil
+}
+
+func (ps *parliamentService) RequestPeerList() ([]pbft.MemberID, error) {
+	peerList := make([]pbft.MemberID, 0)
+	for _, id := range ps.network.ids {
+		peerList = append(peerList, pbft.MemberID(id))
+	}
+
+	return peerList, nil
+}
+
+func (ps *parliamentService) IsNeedConsensus() bool {
+	return len(ps.network.ids) >= 4
+}
+
+type eventService struct {
+	replica *Replica
+}
+
+func (es *eventService) ConfirmBlock(block pbft.ProposedBlock) error {
+	es.replica.Confirmed = append(es.replica.Confirmed, block)
+	return nil
+}
@@ -0,0 +1,516 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/pbft"
+	"github.com/it-chain/engine/consensus/pbft/api"
+	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
+	"github.com/it-chain/engine/consensus/pbft/test/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+const maxRound = 20
+
+var blockX = newBlock("sealX")
+var blockY = newBlock("sealY")
+
+// replica는 body를 복원할 수 있는 block만 pre-prepare 한다.
+func newBlock(seal string) pbft.ProposedBlock {
+	body, _ := common.Serialize(command.StartConsensus{Seal: []byte(seal)})
+	return pbft.ProposedBlock{Seal: []byte(seal), Body: body}
+}
+
+func TestNetwork_NormalCase(t *testing.T) {
+
+	tests := map[string]struct {
+		memberNum int
+	}{
+		"4 replicas":  {memberNum: 4},
+		"5 replicas":  {memberNum: 5},
+		"7 replicas":  {memberNum: 7},
+		"10 replicas": {memberNum: 10},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(test.memberNum))
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		rounds := network.Run(maxRound)
+
+		// then
+		assert.True(t, rounds < maxRound)
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, r.ID)
+
+			_, err := r.Repo.Load()
+			assert.Equal(t, pbft.ErrEmptyRepo, err, r.ID)
+		}
+	}
+}
+
+// byzantine replica를 한 명도 견딜 수 없는 3명 이하의 replica로는 합의를 시작하지 않는다.
+func TestNetwork_TooFewReplicas(t *testing.T) {
+
+	for _, memberNum := range []int{1, 2, 3} {
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(memberNum))
+
+		// when
+		err := network.Propose(blockX)
+
+		// then
+		assert.Equal(t, api.ConsensusCreateError, err, fmt.Sprintf("%d replicas", memberNum))
+	}
+}
+
+func TestNetwork_CrashedReplicas(t *testing.T) {
+
+	tests := map[string]struct {
+		memberNum   int
+		crashed     []string
+		isConfirmed bool
+	}{
+		"4 replicas, 1 crashed follower": {
+			memberNum:   4,
+			crashed:     []string{"r3"},
+			isConfirmed: true,
+		},
+		"7 replicas, 2 crashed followers": {
+			memberNum:   7,
+			crashed:     []string{"r2", "r5"},
+			isConfirmed: true,
+		},
+		"5 replicas, 1 crashed follower": {
+			memberNum:   5,
+			crashed:     []string{"r4"},
+			isConfirmed: true,
+		},
+		"5 replicas, 2 crashed followers": {
+			memberNum:   5,
+			crashed:     []string{"r1", "r3"},
+			isConfirmed: false,
+		},
+		"4 replicas, 2 crashed followers": {
+			memberNum:   4,
+			crashed:     []string{"r1", "r3"},
+			isConfirmed: false,
+		},
+		"7 replicas, 3 crashed followers": {
+			memberNum:   7,
+			crashed:     []string{"r1", "r3", "r6"},
+			isConfirmed: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(test.memberNum))
+		for _, id := range test.crashed {
+			network.Crash(id)
+		}
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		network.Run(maxRound)
+
+		// then
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			if network.IsCrashed(r.ID) || !test.isConfirmed {
+				assert.Empty(t, r.Confirmed, r.ID)
+				continue
+			}
+
+			assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, r.ID)
+		}
+	}
+}
+
+func TestNetwork_DropAndDelay(t *testing.T) {
+
+	tests := map[string]struct {
+		rule simulation.Rule
+	}{
+		"every prepare msg to r1 is dropped": {
+			rule: func(msg simulation.Message) (bool, int) {
+				_, isPrepare := msg.Msg.(pbft.PrepareMsg)
+				return isPrepare && msg.To == "r1", 0
+			},
+		},
+		"every msg from r2 is dropped": {
+			rule: func(msg simulation.Message) (bool, int) {
+				return msg.From == "r2", 0
+			},
+		},
+		"pre-prepare msg to r1 is delayed": {
+			rule: func(msg simulation.Message) (bool, int) {
+				_, isPrePrepare := msg.Msg.(pbft.PrePrepareMsg)
+				if isPrePrepare && msg.To == "r1" {
+					return false, 1
+				}
+				return false, 0
+			},
+		},
+		"every msg from r3 is delayed": {
+			rule: func(msg simulation.Message) (bool, int) {
+				if msg.From == "r3" {
+					return false, 3
+				}
+				return false, 0
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(4))
+		network.AddRule(test.rule)
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		network.Run(maxRound)
+
+		// then
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, r.ID)
+		}
+	}
+}
+
+func TestNetwork_Reordering(t *testing.T) {
+
+	for _, memberNum := range []int{4, 7} {
+		for seed := int64(0); seed < 20; seed++ {
+
+			// given
+			network := simulation.NewNetwork("r0", memberIDs(memberNum))
+			network.Shuffle(seed)
+
+			// when
+			assert.NoError(t, network.Propose(blockX))
+			network.Run(maxRound)
+
+			// then
+			assertSafety(t, network)
+			for _, r := range network.Replicas() {
+				assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, fmt.Sprintf("seed %d, %s", seed, r.ID))
+			}
+		}
+	}
+}
+
+// pre-prepare 보다 먼저 도착한 msg는 보관했다가 다시 적용하므로 임의의 지연에서도 모든 replica가 확정해야 한다.
+// 같은 seed에 대해서는 같은 결과가 나와야 한다.
+func TestNetwork_RandomDelay(t *testing.T) {
+
+	for seed := int64(0); seed < 50; seed++ {
+
+		// given
+		results := make([][]int, 0)
+		for i := 0; i < 2; i++ {
+			network := simulation.NewNetwork("r0", memberIDs(7))
+			network.Shuffle(seed)
+			network.AddRule(randomDelay(seed, 3))
+
+			// when
+			assert.NoError(t, network.Propose(blockX))
+			network.Run(maxRound)
+
+			// then
+			assertSafety(t, network)
+			for _, r := range network.Replicas() {
+				assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, fmt.Sprintf("seed %d, %s", seed, r.ID))
+			}
+			results = append(results, confirmedCounts(network))
+		}
+
+		assert.Equal(t, results[0], results[1], fmt.Sprintf("seed %d", seed))
+	}
+}
+
+func TestNetwork_ByzantineReplica(t *testing.T) {
+
+	tests := map[string]struct {
+		memberNum int
+		byzantine []string
+	}{
+		"4 replicas, 1 byzantine follower": {
+			memberNum: 4,
+			byzantine: []string{"r2"},
+		},
+		"5 replicas, 1 byzantine follower": {
+			memberNum: 5,
+			byzantine: []string{"r3"},
+		},
+		"7 replicas, 2 byzantine followers": {
+			memberNum: 7,
+			byzantine: []string{"r1", "r4"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(test.memberNum))
+		for _, id := range test.byzantine {
+			network.Byzantine(id, forgeBlockHash(blockY.Seal))
+		}
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		network.Run(maxRound)
+
+		// then
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			if network.IsByzantine(r.ID) {
+				continue
+			}
+
+			assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, r.ID)
+			assert.Contains(t, r.Errors, pbft.ErrBlockHashNotSame, r.ID)
+		}
+	}
+}
+
+func TestNetwork_ByzantineLeader(t *testing.T) {
+
+	tests := map[string]struct {
+		memberNum    int
+		receiversOfY []string
+		confirmedX   []string
+	}{
+		"4 replicas, 1 follower receives other block": {
+			memberNum:    4,
+			receiversOfY: []string{"r3"},
+			confirmedX:   []string{"r1", "r2"},
+		},
+		"7 replicas, half of followers receive other block": {
+			memberNum:    7,
+			receiversOfY: []string{"r4", "r5", "r6"},
+			confirmedX:   []string{},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(test.memberNum))
+		network.Byzantine("r0", equivocate(blockY, test.receiversOfY))
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		network.Run(maxRound)
+
+		// then
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			if network.IsByzantine(r.ID) {
+				continue
+			}
+
+			if contains(test.confirmedX, r.ID) {
+				assert.Equal(t, []pbft.ProposedBlock{blockX}, r.Confirmed, r.ID)
+				continue
+			}
+
+			assert.Empty(t, r.Confirmed, r.ID)
+		}
+	}
+}
+
+// byzantine이 아닌 replica들은 서로 다른 block을 확정하지 않아야 한다.
+func TestNetwork_ForgedSenders(t *testing.T) {
+
+	tests := map[string]struct {
+		memberNum    int
+		crashed      []string
+		byzantine    string
+		impersonated []string
+	}{
+		"4 replicas, 1 byzantine follower impersonates 2 crashed followers": {
+			memberNum:    4,
+			crashed:      []string{"r1", "r2"},
+			byzantine:    "r3",
+			impersonated: []string{"r1", "r2"},
+		},
+		"7 replicas, 1 byzantine follower impersonates 3 crashed followers": {
+			memberNum:    7,
+			crashed:      []string{"r1", "r2", "r3"},
+			byzantine:    "r6",
+			impersonated: []string{"r1", "r2", "r3"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		network := simulation.NewNetwork("r0", memberIDs(test.memberNum))
+		for _, id := range test.crashed {
+			network.Crash(id)
+		}
+		network.Byzantine(test.byzantine, impersonate(test.impersonated))
+
+		// when
+		assert.NoError(t, network.Propose(blockX))
+		network.Run(maxRound)
+
+		// then : 위조된 msg가 quorum에 포함되지 않으므로 남은 replica는 block을 확정하지 못한다.
+		assertSafety(t, network)
+		for _, r := range network.Replicas() {
+			if network.IsCrashed(r.ID) || network.IsByzantine(r.ID) {
+				continue
+			}
+
+			assert.Empty(t, r.Confirmed, r.ID)
+			assert.Contains(t, r.Errors, adapter.ErrSenderNotSame, r.ID)
+		}
+	}
+}
+
+func assertSafety(t *testing.T, network *simulation.Network) {
+	var confirmed []pbft.ProposedBlock
+
+	for _, r := range network.Replicas() {
+		if network.IsByzantine(r.ID) {
+			continue
+		}
+
+		assert.True(t, len(r.Confirmed) <= 1, r.ID)
+
+		for _, block := range r.Confirmed {
+			if confirmed == nil {
+				confirmed = []pbft.ProposedBlock{block}
+				continue
+			}
+
+			assert.True(t, bytes.Equal(confirmed[0].Seal, block.Seal), "replica %s confirmed another block", r.ID)
+		}
+	}
+}
+
+func memberIDs(num int) []string {
+	ids := make([]string, 0)
+	for i := 0; i < num; i++ {
+		ids = append(ids, fmt.Sprintf("r%d", i))
+	}
+
+	return ids
+}
+
+func confirmedCounts(network *simulation.Network) []int {
+	counts := make([]int, 0)
+	for _, r := range network.Replicas() {
+		counts = append(counts, len(r.Confirmed))
+	}
+
+	return counts
+}
+
+func randomDelay(seed int64, maxDelay int) simulation.Rule {
+	random := rand.New(rand.NewSource(seed))
+
+	return func(msg simulation.Message) (bool, int) {
+		return false, random.Intn(maxDelay + 1)
+	}
+}
+
+// prepare, commit msg의 block hash를 바꿔서 보낸다.
+func forgeBlockHash(blockHash []byte) simulation.Tamper {
+	return func(msg simulation.Message) []simulation.Message {
+		switch m := msg.Msg.(type) {
+		case pbft.PrepareMsg:
+			m.BlockHash = blockHash
+			msg.Msg = m
+		case pbft.CommitMsg:
+			m.BlockHash = blockHash
+			msg.Msg = m
+		}
+
+		return []simulation.Message{msg}
+	}
+}
+
+// receivers에게는 다른 block으로 pre-prepare 한다.
+func equivocate(block pbft.ProposedBlock, receivers []string) simulation.Tamper {
+	return func(msg simulation.Message) []simulation.Message {
+		m, ok := msg.Msg.(pbft.PrePrepareMsg)
+		if ok && contains(receivers, msg.To) {
+			m.ProposedBlock = block
+			msg.Msg = m
+		}
+
+		return []simulation.Message{msg}
+	}
+}
+
+// 자신의 prepare, commit msg를 senderIDs가 보낸 것처럼 바꾸어 함께 보낸다.
+func impersonate(senderIDs []string) simulation.Tamper {
+	return func(msg simulation.Message) []simulation.Message {
+		messages := []simulation.Message{msg}
+
+		for _, id := range senderIDs {
+			forged := msg
+			switch m := msg.Msg.(type) {
+			case pbft.PrepareMsg:
+				m.SenderID = id
+				forged.Msg = m
+			case pbft.CommitMsg:
+				m.SenderID = id
+				forged.Msg = m
+			default:
+				continue
+			}
+
+			messages = append(messages, forged)
+		}
+
+		return messages
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
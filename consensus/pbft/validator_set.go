@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbft
+
+import (
+	"sort"
+	"sync"
+)
+
+// 합의에 참여할 수 있는 validator 목록
+// 연결된 peer가 아니라 설정이나 genesis block에 등록된 validator 중에서 representative를 선출한다.
+// 목록은 block이 확정될 때에만 바뀌므로, 하나의 합의가 진행되는 동안에는 모든 replica가 같은 목록을 가진다.
+type ValidatorSet struct {
+	validators map[MemberID]struct{}
+	epoch      uint64
+	mutex      sync.RWMutex
+}
+
+func NewValidatorSet(validators []MemberID) *ValidatorSet {
+	vs := &ValidatorSet{
+		validators: make(map[MemberID]struct{}),
+		mutex:      sync.RWMutex{},
+	}
+
+	for _, v := range validators {
+		if v == "" {
+			continue
+		}
+
+		vs.validators[v] = struct{}{}
+	}
+
+	return vs
+}
+
+// id 순서로 정렬된 validator 목록
+func (vs *ValidatorSet) Validators() []MemberID {
+
+	vs.mutex.RLock()
+	defer vs.mutex.RUnlock()
+
+	validators := make([]MemberID, 0)
+	for v := range vs.validators {
+		validators = append(validators, v)
+	}
+
+	sort.Slice(validators, func(i, j int) bool {
+		return validators[i] < validators[j]
+	})
+
+	return validators
+}
+
+func (vs *ValidatorSet) Contains(id MemberID) bool {
+
+	vs.mutex.RLock()
+	defer vs.mutex.RUnlock()
+
+	_, ok := vs.validators[id]
+	return ok
+}
+
+// 같은 block이 다시 적용되어도 결과가 같도록 이미 있는 validator의 추가와 없는 validator의 제거는 무시한다.
+func (vs *ValidatorSet) Add(id MemberID) {
+
+	vs.mutex.Lock()
+	defer vs.mutex.Unlock()
+
+	vs.validators[id] = struct{}{}
+	vs.epoch++
+}
+
+func (vs *ValidatorSet) Remove(id MemberID) {
+
+	vs.mutex.Lock()
+	defer vs.mutex.Unlock()
+
+	delete(vs.validators, id)
+	vs.epoch++
+}
+
+// 적용된 validator 변경의 수
+// 이미 있는 validator의 추가와 같이 구성이 바뀌지 않은 변경도 세므로, 같은 block을 적용한 validator는 같은 epoch를 가진다.
+func (vs *ValidatorSet) Epoch() uint64 {
+
+	vs.mutex.RLock()
+	defer vs.mutex.RUnlock()
+
+	return vs.epoch
+}
+
+func (vs *ValidatorSet) Size() int {
+
+	vs.mutex.RLock()
+	defer vs.mutex.RUnlock()
+
+	return len(vs.validators)
+}
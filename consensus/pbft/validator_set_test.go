@@ -14,32 +14,41 @@
  * limitations under the License.
  */
 
-package adapter_test
+package pbft_test
 
 import (
 	"testing"
 
 	"github.com/it-chain/engine/consensus/pbft"
-	"github.com/it-chain/engine/consensus/pbft/infra/adapter"
-	"github.com/it-chain/engine/consensus/pbft/test/mock"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestEventService_ConfirmBlock(t *testing.T) {
-	mockEventService := mock.EventService{}
-	mockEventService.PublishFunc = func(topic string, event interface{}) error {
-		// todo : 생성된 event 비교해야함
-		assert.Equal(t, "block.confirm", topic)
-		return nil
-	}
+func TestNewValidatorSet(t *testing.T) {
+	// when
+	vs := pbft.NewValidatorSet([]pbft.MemberID{"v3", "v1", "", "v2", "v1"})
 
-	eventService := adapter.NewEventService(mockEventService.PublishFunc)
+	// then
+	assert.Equal(t, []pbft.MemberID{"v1", "v2", "v3"}, vs.Validators())
+	assert.Equal(t, 3, vs.Size())
+	assert.True(t, vs.Contains("v2"))
+	assert.False(t, vs.Contains("v4"))
+}
+
+func TestValidatorSet_AddAndRemove(t *testing.T) {
+	// given
+	vs := pbft.NewValidatorSet([]pbft.MemberID{"v1", "v2"})
+
+	// when
+	vs.Add("v3")
+	vs.Add("v3")
+
+	// then
+	assert.Equal(t, []pbft.MemberID{"v1", "v2", "v3"}, vs.Validators())
 
-	block := pbft.ProposedBlock{
-		Seal: []byte("seal"),
-		Body: []byte("body"),
-	}
+	// when
+	vs.Remove("v1")
+	vs.Remove("v4")
 
-	err := eventService.ConfirmBlock(block)
-	assert.Nil(t, err)
+	// then
+	assert.Equal(t, []pbft.MemberID{"v2", "v3"}, vs.Validators())
 }
@@ -0,0 +1,423 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/raft"
+)
+
+// raft로 block의 순서를 합의하는 consensus.Consensus의 구현체이다.
+type RaftApi struct {
+	replica        *raft.Replica
+	messageService raft.MessageService
+	leaderService  raft.LeaderService
+	store          raft.LogStore
+	verifier       consensus.ApprovalVerifier
+	deliver        consensus.DeliverHandler
+	mutex          sync.Mutex
+
+	// member는 store에 저장되지만, epoch는 재시작 할 때 block의 membership 변경을 다시 적용하며 센다.
+	epoch uint64
+}
+
+func NewRaftApi(nodeID string, messageService raft.MessageService, leaderService raft.LeaderService, verifier consensus.ApprovalVerifier) *RaftApi {
+	return &RaftApi{
+		replica:        raft.NewReplica(nodeID),
+		messageService: messageService,
+		leaderService:  leaderService,
+		verifier:       verifier,
+		mutex:          sync.Mutex{},
+	}
+}
+
+// store에 저장되어 있던 term과 log를 복구한 RaftApi를 생성한다.
+// 이후 term과 log의 모든 변경은 message를 보내기 전에 store에 먼저 기록된다.
+func NewPersistentRaftApi(nodeID string, messageService raft.MessageService, leaderService raft.LeaderService, verifier consensus.ApprovalVerifier, store raft.LogStore) (*RaftApi, error) {
+	a := NewRaftApi(nodeID, messageService, leaderService, verifier)
+	a.store = store
+
+	state, err := store.Load()
+	if err != nil && err != raft.ErrEmptyLog {
+		return nil, err
+	}
+
+	if err == nil {
+		a.replica.Restore(state)
+	}
+
+	return a, nil
+}
+
+func (a *RaftApi) Propose(block consensus.ProposedBlock) error {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.syncLeader()
+
+	if a.isSoleMemberWithPeers() {
+		return raft.ErrSoleMember
+	}
+
+	msg, err := a.replica.Append(block)
+	if err == raft.ErrLeaderNotReady {
+		a.checkFollowers()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := a.persist(); err != nil {
+		return err
+	}
+
+	if err := a.messageService.SendAppendEntries(msg, a.replica.Followers()); err != nil {
+		return err
+	}
+
+	// member가 leader 하나인 경우 바로 commit 된다.
+	return a.deliverCommittedEntries()
+}
+
+func (a *RaftApi) OnDeliver(handler consensus.DeliverHandler) {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.deliver = handler
+}
+
+// 과반수 member의 log를 확인한 leader만 block을 제안할 수 있다.
+func (a *RaftApi) IsProposer() bool {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.syncLeader()
+
+	if !a.replica.IsLeader() || a.isSoleMemberWithPeers() {
+		return false
+	}
+
+	if !a.replica.IsReady() {
+		a.checkFollowers()
+		return false
+	}
+
+	return true
+}
+
+func (a *RaftApi) Members() []string {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.members()
+}
+
+func (a *RaftApi) members() []string {
+	members := make([]string, 0)
+	for memberID := range a.replica.Members {
+		members = append(members, memberID)
+	}
+
+	sort.Strings(members)
+
+	return members
+}
+
+func (a *RaftApi) AddMember(memberID string) error {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.addMember(memberID)
+}
+
+func (a *RaftApi) RemoveMember(memberID string) error {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.removeMember(memberID)
+
+	return nil
+}
+
+func (a *RaftApi) Epoch() uint64 {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.epoch
+}
+
+// 새로운 member는 leader가 보내는 빈 AppendEntriesMsg를 거절하고, leader는 그 응답을 보고 빠진 entry를 다시 보낸다.
+func (a *RaftApi) addMember(memberID string) error {
+
+	a.replica.AddMember(memberID)
+	a.epoch++
+
+	if !a.replica.IsLeader() || memberID == a.replica.ID {
+		return nil
+	}
+
+	return a.messageService.SendAppendEntries(a.replica.AppendEntriesFrom(a.replica.LastIndex()+1), []string{memberID})
+}
+
+func (a *RaftApi) removeMember(memberID string) {
+	a.replica.RemoveMember(memberID)
+	a.epoch++
+}
+
+func (a *RaftApi) Term() uint64 {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.replica.Term
+}
+
+// senderID는 message를 보낸 peer이다.
+// p2p election에서 msg.Term의 leader로 선출된 member가 직접 보낸 message만 받아들인다.
+func (a *RaftApi) HandleAppendEntries(senderID string, msg raft.AppendEntriesMsg) error {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err := a.checkLeader(senderID, msg); err != nil {
+		logger.Warn(nil, fmt.Sprintf("[Raft] Append entries from invalid leader - sender: [%s], leader: [%s], term: [%d], err: [%s]", senderID, msg.LeaderID, msg.Term, err.Error()))
+		return err
+	}
+
+	ack := a.replica.HandleAppendEntries(msg)
+	if !ack.Success {
+		logger.Warn(nil, fmt.Sprintf("[Raft] Append entries is rejected - leader: [%s], term: [%d], prevIndex: [%d]", msg.LeaderID, msg.Term, msg.PrevIndex))
+	}
+
+	// 저장하지 못한 entry에 대해 ack를 보내지 않는다.
+	if err := a.persist(); err != nil {
+		return err
+	}
+
+	if err := a.messageService.SendAck(ack, msg.LeaderID); err != nil {
+		return err
+	}
+
+	return a.deliverCommittedEntries()
+}
+
+func (a *RaftApi) HandleAck(ack raft.AppendEntriesAck) error {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	prevCommitIndex := a.replica.CommitIndex
+	prevLastIndex, prevLastTerm := a.replica.LastIndex(), a.replica.LastTerm()
+	a.replica.HandleAck(ack)
+
+	if err := a.persist(); err != nil {
+		return err
+	}
+
+	if !a.replica.IsLeader() {
+		return nil
+	}
+
+	if !ack.Success {
+		// follower의 더 최신 log를 받아 따라잡은 경우 다른 follower에게도 전달한다.
+		if a.replica.LastIndex() != prevLastIndex || a.replica.LastTerm() != prevLastTerm {
+			logger.Info(nil, fmt.Sprintf("[Raft] Leader has caught up with follower - follower: [%s], lastIndex: [%d]", ack.SenderID, a.replica.LastIndex()))
+			return a.messageService.SendAppendEntries(a.replica.AppendEntriesFrom(a.replica.CommitIndex+1), a.replica.Followers())
+		}
+
+		// follower의 log가 leader와 다른 경우 일치하는 마지막 entry 다음부터 다시 보낸다.
+		if ack.MatchIndex >= a.replica.LastIndex() {
+			logger.Warn(nil, fmt.Sprintf("[Raft] Follower has more committed entries than leader - follower: [%s], matchIndex: [%d]", ack.SenderID, ack.MatchIndex))
+			return nil
+		}
+
+		return a.messageService.SendAppendEntries(a.replica.AppendEntriesFrom(ack.MatchIndex+1), []string{ack.SenderID})
+	}
+
+	if a.replica.CommitIndex == prevCommitIndex {
+		return nil
+	}
+
+	if err := a.deliverCommittedEntries(); err != nil {
+		return err
+	}
+
+	// 새로 commit 된 index를 follower에게 알린다.
+	return a.messageService.SendAppendEntries(a.replica.AppendEntriesFrom(a.replica.LastIndex()+1), a.replica.Followers())
+}
+
+func (a *RaftApi) checkLeader(senderID string, msg raft.AppendEntriesMsg) error {
+
+	if senderID != msg.LeaderID {
+		return raft.ErrInvalidSender
+	}
+
+	if _, ok := a.replica.Members[msg.LeaderID]; !ok {
+		return raft.ErrNotMember
+	}
+
+	leader, err := a.leaderService.RequestLeader()
+	if err != nil {
+		return err
+	}
+
+	if leader.ID != msg.LeaderID || leader.Term != msg.Term {
+		return raft.ErrNotElectedLeader
+	}
+
+	return nil
+}
+
+// p2p component에서 선출된 leader와 term을 따른다.
+// 새로 leader가 되면 follower들의 log를 확인한다.
+func (a *RaftApi) syncLeader() {
+
+	leader, err := a.leaderService.RequestLeader()
+	if err != nil || leader.ID == "" {
+		return
+	}
+
+	if leader.ID == a.replica.ID {
+		if !a.replica.IsLeader() || a.replica.Term < leader.Term {
+			if !a.replica.BecomeLeader(leader.Term) {
+				logger.Warn(nil, fmt.Sprintf("[Raft] Elected term is already used - elected term: [%d], term: [%d]", leader.Term, a.replica.Term))
+				return
+			}
+
+			if err := a.persist(); err != nil {
+				logger.Error(nil, fmt.Sprintf("[Raft] Fail to save term - term: [%d], err: [%s]", a.replica.Term, err.Error()))
+			}
+
+			a.checkFollowers()
+		}
+		return
+	}
+
+	if a.replica.IsLeader() {
+		a.replica.Follow(leader.ID)
+	}
+}
+
+// 아직 log를 확인하지 못한 follower에게 빈 AppendEntriesMsg를 보낸다.
+// follower의 log가 더 최신이면 follower는 거절하면서 leader에게 없는 entry를 보내준다.
+func (a *RaftApi) checkFollowers() {
+
+	followers := a.replica.UncheckedFollowers()
+	if len(followers) == 0 {
+		return
+	}
+
+	if err := a.messageService.SendAppendEntries(a.replica.AppendEntriesFrom(a.replica.LastIndex()+1), followers); err != nil {
+		logger.Error(nil, fmt.Sprintf("[Raft] Fail to check followers - term: [%d], err: [%s]", a.replica.Term, err.Error()))
+	}
+}
+
+// membership이 아직 복구되지 않아 member가 자기 자신뿐이라면, 연결된 peer가 있는 동안에는 혼자 commit 하지 않는다.
+func (a *RaftApi) isSoleMemberWithPeers() bool {
+
+	if len(a.replica.Followers()) != 0 {
+		return false
+	}
+
+	peerIDs, err := a.leaderService.RequestPeerList()
+	if err != nil {
+		return true
+	}
+
+	for _, peerID := range peerIDs {
+		if peerID != a.replica.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *RaftApi) persist() error {
+
+	if a.store == nil {
+		return nil
+	}
+
+	return a.store.Save(a.replica.HardState())
+}
+
+// commit 된 entry의 block을 log 순서대로 전달하고, block에 기록된 membership 변경을 적용한다.
+// commit 된 entry는 되돌릴 수 없으므로 전달에 실패하더라도 다음 entry를 계속 전달한다.
+func (a *RaftApi) deliverCommittedEntries() error {
+
+	entries := a.replica.TakeCommittedEntries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if a.deliver != nil {
+			if err := a.deliver(entry.Block); err != nil {
+				logger.Error(nil, fmt.Sprintf("[Raft] Fail to deliver committed block - index: [%d], seal: [%x], err: [%s]", entry.Index, entry.Block.Seal, err.Error()))
+			}
+		}
+
+		a.applyMembershipChanges(entry)
+	}
+
+	// 전달한 index를 저장하여 재시작 후 같은 block을 다시 전달하지 않는다.
+	return a.persist()
+}
+
+func (a *RaftApi) applyMembershipChanges(entry raft.Entry) {
+
+	changes, err := consensus.MembershipChangesOf(entry.Block)
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[Raft] Fail to read membership changes - index: [%d], err: [%s]", entry.Index, err.Error()))
+		return
+	}
+
+	for _, change := range changes {
+		if err := consensus.AuthorizeMembershipChange(change, a.members(), a.epoch, a.verifier); err != nil {
+			logger.Warn(nil, fmt.Sprintf("[Raft] Membership change is rejected - member: [%s], err: [%s]", change.MemberID, err.Error()))
+			continue
+		}
+
+		switch change.Function {
+		case consensus.Join:
+			err = a.addMember(change.MemberID)
+		case consensus.Leave:
+			a.removeMember(change.MemberID)
+		}
+
+		if err != nil {
+			logger.Error(nil, fmt.Sprintf("[Raft] Fail to apply membership change - member: [%s], err: [%s]", change.MemberID, err.Error()))
+			continue
+		}
+
+		logger.Info(nil, fmt.Sprintf("[Raft] Membership has changed - function: [%s], member: [%s], epoch: [%d]", change.Function, change.MemberID, a.epoch))
+	}
+}
@@ -0,0 +1,444 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/it-chain/engine/consensus/raft/api"
+	"github.com/it-chain/engine/consensus/raft/test/mock"
+	consensusMock "github.com/it-chain/engine/consensus/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// replica 사이의 message를 queue에 쌓아두고 순서대로 전달하는 network
+// down 상태인 replica에게 보내는 message는 버려진다.
+type network struct {
+	apis  map[string]*api.RaftApi
+	down  map[string]bool
+	queue []func() error
+}
+
+func (n *network) messageService(senderID string) mock.MessageService {
+	return mock.MessageService{
+		SendAppendEntriesFunc: func(msg raft.AppendEntriesMsg, recipients []string) error {
+			for _, recipient := range recipients {
+				if n.down[recipient] || n.down[senderID] {
+					continue
+				}
+				raftApi := n.apis[recipient]
+				n.queue = append(n.queue, func() error { return raftApi.HandleAppendEntries(senderID, msg) })
+			}
+			return nil
+		},
+		SendAckFunc: func(ack raft.AppendEntriesAck, recipient string) error {
+			if n.down[recipient] || n.down[senderID] {
+				return nil
+			}
+			raftApi := n.apis[recipient]
+			n.queue = append(n.queue, func() error { return raftApi.HandleAck(ack) })
+			return nil
+		},
+	}
+}
+
+func (n *network) flush(t *testing.T) {
+	for len(n.queue) > 0 {
+		deliver := n.queue[0]
+		n.queue = n.queue[1:]
+		assert.NoError(t, deliver())
+	}
+}
+
+func setUpCluster(memberIDs []string, leader *raft.Leader) (*network, map[string]*[]consensus.ProposedBlock) {
+	n := &network{apis: make(map[string]*api.RaftApi), down: make(map[string]bool), queue: make([]func() error, 0)}
+	delivered := make(map[string]*[]consensus.ProposedBlock)
+
+	leaderService := mock.LeaderService{
+		RequestLeaderFunc: func() (raft.Leader, error) {
+			return *leader, nil
+		},
+		RequestPeerListFunc: func() ([]string, error) {
+			return memberIDs, nil
+		},
+	}
+
+	for _, id := range memberIDs {
+		raftApi := api.NewRaftApi(id, n.messageService(id), leaderService, consensusMock.ApprovalVerifier{})
+		for _, memberID := range memberIDs {
+			raftApi.AddMember(memberID)
+		}
+
+		blocks := make([]consensus.ProposedBlock, 0)
+		delivered[id] = &blocks
+		raftApi.OnDeliver(func(block consensus.ProposedBlock) error {
+			blocks = append(blocks, block)
+			return nil
+		})
+
+		n.apis[id] = raftApi
+	}
+
+	return n, delivered
+}
+
+var noopMessageService = mock.MessageService{
+	SendAppendEntriesFunc: func(msg raft.AppendEntriesMsg, recipients []string) error {
+		return nil
+	},
+	SendAckFunc: func(ack raft.AppendEntriesAck, recipient string) error {
+		return nil
+	},
+}
+
+func TestRaftApi_Propose(t *testing.T) {
+
+	// given
+	leader := raft.Leader{ID: "1", Term: 1}
+	n, delivered := setUpCluster([]string{"1", "2", "3"}, &leader)
+
+	// then : leader는 과반수의 log를 확인하기 전에는 block을 제안하지 않는다.
+	assert.False(t, n.apis["1"].IsProposer())
+	assert.Equal(t, raft.ErrLeaderNotReady, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}))
+	n.flush(t)
+
+	// when
+	assert.True(t, n.apis["1"].IsProposer())
+	assert.False(t, n.apis["2"].IsProposer())
+	assert.NoError(t, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}))
+	assert.NoError(t, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}))
+	n.flush(t)
+
+	// then
+	for _, id := range []string{"1", "2", "3"} {
+		blocks := *delivered[id]
+		assert.Equal(t, 2, len(blocks), "replica %s", id)
+		assert.Equal(t, []byte("seal1"), blocks[0].Seal)
+		assert.Equal(t, []byte("seal2"), blocks[1].Seal)
+	}
+
+	// follower는 block을 제안할 수 없다.
+	assert.Equal(t, raft.ErrNotLeader, n.apis["2"].Propose(consensus.ProposedBlock{Seal: []byte("seal3")}))
+}
+
+func TestRaftApi_AddMember(t *testing.T) {
+
+	// given
+	leader := raft.Leader{ID: "1", Term: 1}
+	n, delivered := setUpCluster([]string{"1", "2", "3"}, &leader)
+	n.apis["1"].IsProposer()
+	n.flush(t)
+	assert.True(t, n.apis["1"].IsProposer())
+	assert.NoError(t, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}))
+	n.flush(t)
+
+	lateBlocks := make([]consensus.ProposedBlock, 0)
+	late := api.NewRaftApi("4", n.messageService("4"), mock.LeaderService{RequestLeaderFunc: func() (raft.Leader, error) {
+		return leader, nil
+	}, RequestPeerListFunc: func() ([]string, error) {
+		return []string{"1", "2", "3", "4"}, nil
+	}}, consensusMock.ApprovalVerifier{})
+	// genesis block으로 복구된 member
+	for _, memberID := range []string{"1", "2", "3"} {
+		late.AddMember(memberID)
+	}
+	late.OnDeliver(func(block consensus.ProposedBlock) error {
+		lateBlocks = append(lateBlocks, block)
+		return nil
+	})
+	n.apis["4"] = late
+
+	// when
+	assert.NoError(t, n.apis["1"].AddMember("4"))
+	n.flush(t)
+	assert.NoError(t, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}))
+	n.flush(t)
+
+	// then : 늦게 들어온 member도 이전 block부터 순서대로 전달받는다.
+	assert.Equal(t, 2, len(*delivered["2"]))
+	assert.Equal(t, 2, len(lateBlocks))
+	assert.Equal(t, []byte("seal1"), lateBlocks[0].Seal)
+	assert.Equal(t, []byte("seal2"), lateBlocks[1].Seal)
+}
+
+func TestRaftApi_LeaderCatchesUp(t *testing.T) {
+
+	// given : 3이 down 된 동안 1과 2만 block을 commit 했다.
+	leader := raft.Leader{ID: "1", Term: 1}
+	n, delivered := setUpCluster([]string{"1", "2", "3"}, &leader)
+	n.down["3"] = true
+
+	n.apis["1"].IsProposer()
+	n.flush(t)
+	assert.NoError(t, n.apis["1"].Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}))
+	n.flush(t)
+	assert.Equal(t, 1, len(*delivered["2"]))
+	assert.Equal(t, 0, len(*delivered["3"]))
+
+	// when : 1이 down 되고, log가 가장 오래된 3이 leader로 선출되었다.
+	n.down["1"] = true
+	n.down["3"] = false
+	leader = raft.Leader{ID: "3", Term: 2}
+
+	assert.False(t, n.apis["3"].IsProposer())
+	n.flush(t)
+
+	// then : 3은 2의 log를 받아 따라잡은 뒤에 block을 제안한다.
+	assert.True(t, n.apis["3"].IsProposer())
+	assert.NoError(t, n.apis["3"].Propose(consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}))
+	n.flush(t)
+
+	for _, id := range []string{"2", "3"} {
+		blocks := *delivered[id]
+		assert.Equal(t, 2, len(blocks), "replica %s", id)
+		assert.Equal(t, []byte("seal1"), blocks[0].Seal)
+		assert.Equal(t, []byte("seal2"), blocks[1].Seal)
+	}
+}
+
+func TestRaftApi_SoleMember(t *testing.T) {
+
+	// given : membership이 복구되지 않아 member가 자기 자신뿐이다.
+	peerIDs := []string{"1", "2"}
+	raftApi := api.NewRaftApi("1", noopMessageService, mock.LeaderService{
+		RequestLeaderFunc: func() (raft.Leader, error) {
+			return raft.Leader{ID: "1", Term: 1}, nil
+		},
+		RequestPeerListFunc: func() ([]string, error) {
+			return peerIDs, nil
+		},
+	}, consensusMock.ApprovalVerifier{})
+
+	// then : 연결된 peer가 있으면 혼자 commit 하지 않는다.
+	assert.False(t, raftApi.IsProposer())
+	assert.Equal(t, raft.ErrSoleMember, raftApi.Propose(consensus.ProposedBlock{Seal: []byte("seal1")}))
+
+	// when : 연결된 peer가 없는 단일 node
+	peerIDs = []string{}
+
+	// then
+	assert.True(t, raftApi.IsProposer())
+}
+
+func TestRaftApi_ApplyMembershipChanges(t *testing.T) {
+
+	// given
+	recipients := make([]string, 0)
+	raftApi := api.NewRaftApi("1", mock.MessageService{
+		SendAppendEntriesFunc: func(msg raft.AppendEntriesMsg, to []string) error {
+			recipients = append(recipients, to...)
+			return nil
+		},
+	}, mock.LeaderService{
+		RequestLeaderFunc: func() (raft.Leader, error) {
+			return raft.Leader{ID: "1", Term: 1}, nil
+		},
+		RequestPeerListFunc: func() ([]string, error) {
+			return []string{}, nil
+		},
+	}, consensusMock.ApprovalVerifier{})
+
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "2"}
+	unapproved := consensus.MembershipChange{Function: consensus.Join, MemberID: "3"}
+	body, err := common.Serialize(command.StartConsensus{
+		Seal: []byte("seal1"),
+		TxList: []command.Tx{
+			{ID: "tx1", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"2"}, Signature: consensusMock.ApprovalSignature(join, "1")},
+			{ID: "tx2", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"3"}, Signature: consensusMock.ApprovalSignature(unapproved, "3")},
+		},
+	})
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, raftApi.Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: body}))
+
+	// then : commit 된 block의 join이 적용되어, 새 member의 log를 확인하기 전에는 block을 제안하지 않는다.
+	assert.Contains(t, recipients, "2")
+	assert.NotContains(t, recipients, "3")
+	assert.Equal(t, []string{"1", "2"}, raftApi.Members())
+	assert.False(t, raftApi.IsProposer())
+}
+
+func TestNewPersistentRaftApi(t *testing.T) {
+
+	// given
+	saved := raft.HardState{}
+	store := mock.LogStore{
+		SaveFunc: func(state raft.HardState) error {
+			saved = state
+			return nil
+		},
+		LoadFunc: func() (raft.HardState, error) {
+			if saved.Term == 0 {
+				return raft.HardState{}, raft.ErrEmptyLog
+			}
+			return saved, nil
+		},
+	}
+	leader := raft.Leader{ID: "1", Term: 1}
+	leaderService := mock.LeaderService{
+		RequestLeaderFunc: func() (raft.Leader, error) {
+			return leader, nil
+		},
+		RequestPeerListFunc: func() ([]string, error) {
+			return []string{}, nil
+		},
+	}
+
+	raftApi, err := api.NewPersistentRaftApi("1", noopMessageService, leaderService, consensusMock.ApprovalVerifier{}, store)
+	assert.NoError(t, err)
+	assert.NoError(t, raftApi.Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}))
+
+	// when : 재시작 후 p2p election에서 다음 term의 leader로 선출되었다.
+	leader = raft.Leader{ID: "1", Term: 2}
+	restarted, err := api.NewPersistentRaftApi("1", noopMessageService, leaderService, consensusMock.ApprovalVerifier{}, store)
+	assert.NoError(t, err)
+
+	delivered := 0
+	restarted.OnDeliver(func(block consensus.ProposedBlock) error {
+		delivered++
+		return nil
+	})
+	assert.NoError(t, restarted.Propose(consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}))
+
+	// then : 저장된 log 뒤에 이어서 추가하고, 이미 전달한 block은 다시 전달하지 않는다.
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 2, len(saved.Log))
+	assert.Equal(t, []byte("seal1"), saved.Log[0].Block.Seal)
+	assert.Equal(t, uint64(2), saved.Term)
+	assert.Equal(t, uint64(2), saved.AppliedIndex)
+}
+
+func TestRaftApi_HandleAppendEntries_InvalidLeader(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			senderID string
+			msg      raft.AppendEntriesMsg
+		}
+		err error
+	}{
+		"sent by other peer": {
+			input: struct {
+				senderID string
+				msg      raft.AppendEntriesMsg
+			}{senderID: "3", msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "1"}},
+			err: raft.ErrInvalidSender,
+		},
+		"leader is not a member": {
+			input: struct {
+				senderID string
+				msg      raft.AppendEntriesMsg
+			}{senderID: "4", msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "4"}},
+			err: raft.ErrNotMember,
+		},
+		"member which is not elected": {
+			input: struct {
+				senderID string
+				msg      raft.AppendEntriesMsg
+			}{senderID: "3", msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "3"}},
+			err: raft.ErrNotElectedLeader,
+		},
+		"term is not elected term": {
+			input: struct {
+				senderID string
+				msg      raft.AppendEntriesMsg
+			}{senderID: "1", msg: raft.AppendEntriesMsg{Term: 100, LeaderID: "1"}},
+			err: raft.ErrNotElectedLeader,
+		},
+		"elected leader": {
+			input: struct {
+				senderID string
+				msg      raft.AppendEntriesMsg
+			}{senderID: "1", msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "1"}},
+			err: nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		acked := make([]string, 0)
+		raftApi := api.NewRaftApi("2", mock.MessageService{
+			SendAckFunc: func(ack raft.AppendEntriesAck, recipient string) error {
+				acked = append(acked, recipient)
+				return nil
+			},
+		}, mock.LeaderService{
+			RequestLeaderFunc: func() (raft.Leader, error) {
+				return raft.Leader{ID: "1", Term: 2}, nil
+			},
+		}, consensusMock.ApprovalVerifier{})
+		for _, memberID := range []string{"1", "2", "3"} {
+			raftApi.AddMember(memberID)
+		}
+
+		// when
+		err := raftApi.HandleAppendEntries(test.input.senderID, test.input.msg)
+
+		// then : 선출된 leader가 아니면 term을 바꾸거나 ack를 보내지 않는다.
+		assert.Equal(t, test.err, err)
+		if test.err != nil {
+			assert.Equal(t, 0, len(acked))
+			assert.Equal(t, uint64(0), raftApi.Term())
+		} else {
+			assert.Equal(t, []string{"1"}, acked)
+			assert.Equal(t, uint64(2), raftApi.Term())
+		}
+	}
+}
+
+func TestRaftApi_ElectedTerm(t *testing.T) {
+
+	// given : 저장된 term은 3이다.
+	store := mock.LogStore{
+		SaveFunc: func(state raft.HardState) error {
+			return nil
+		},
+		LoadFunc: func() (raft.HardState, error) {
+			return raft.HardState{Term: 3}, nil
+		},
+	}
+	leader := raft.Leader{ID: "1", Term: 3}
+	leaderService := mock.LeaderService{
+		RequestLeaderFunc: func() (raft.Leader, error) {
+			return leader, nil
+		},
+		RequestPeerListFunc: func() ([]string, error) {
+			return []string{}, nil
+		},
+	}
+
+	raftApi, err := api.NewPersistentRaftApi("1", noopMessageService, leaderService, consensusMock.ApprovalVerifier{}, store)
+	assert.NoError(t, err)
+
+	// then : 이미 사용한 term으로는 leader가 되지 않는다.
+	assert.False(t, raftApi.IsProposer())
+	assert.Equal(t, raft.ErrNotLeader, raftApi.Propose(consensus.ProposedBlock{Seal: []byte("seal1")}))
+
+	// when : 다음 term의 leader로 선출되었다.
+	leader = raft.Leader{ID: "1", Term: 4}
+
+	// then : 선출된 term을 사용한다.
+	assert.True(t, raftApi.IsProposer())
+	assert.Equal(t, uint64(4), raftApi.Term())
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/raft"
+)
+
+type RaftApi interface {
+	HandleAppendEntries(senderID string, msg raft.AppendEntriesMsg) error
+	HandleAck(ack raft.AppendEntriesAck) error
+}
+
+// 다른 replica로부터 받은 message를 raft api로 전달한다.
+// common.Deserialize는 잘못된 body에 panic 하므로 직접 decode 하고 error를 반환한다.
+type GrpcCommandHandler struct {
+	raftApi RaftApi
+}
+
+func NewGrpcCommandHandler(raftApi RaftApi) GrpcCommandHandler {
+	return GrpcCommandHandler{
+		raftApi: raftApi,
+	}
+}
+
+func (g *GrpcCommandHandler) HandleGrpcCommand(command command.ReceiveGrpc) error {
+
+	switch command.Protocol {
+
+	case "AppendEntriesProtocol":
+		msg := raft.AppendEntriesMsg{}
+		if err := json.Unmarshal(command.Body, &msg); err != nil {
+			return err
+		}
+
+		return g.raftApi.HandleAppendEntries(command.ConnectionID, msg)
+
+	case "AppendEntriesAckProtocol":
+		ack := raft.AppendEntriesAck{}
+		if err := json.Unmarshal(command.Body, &ack); err != nil {
+			return err
+		}
+
+		return g.raftApi.HandleAck(ack)
+	}
+
+	return nil
+}
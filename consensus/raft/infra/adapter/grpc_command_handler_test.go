@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/it-chain/engine/consensus/raft/infra/adapter"
+	"github.com/it-chain/engine/consensus/raft/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrpcCommandHandler_HandleGrpcCommand(t *testing.T) {
+
+	// given
+	tests := map[string]struct {
+		input struct {
+			protocol string
+			body     interface{}
+		}
+		handled string
+	}{
+		"append entries": {
+			input: struct {
+				protocol string
+				body     interface{}
+			}{"AppendEntriesProtocol", raft.AppendEntriesMsg{Term: 1, LeaderID: "leader"}},
+			handled: "append",
+		},
+		"append entries ack": {
+			input: struct {
+				protocol string
+				body     interface{}
+			}{"AppendEntriesAckProtocol", raft.AppendEntriesAck{Term: 1, SenderID: "follower1"}},
+			handled: "ack",
+		},
+		"other protocol": {
+			input: struct {
+				protocol string
+				body     interface{}
+			}{"PrepareMsgProtocol", raft.AppendEntriesAck{}},
+			handled: "",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		handled := ""
+		raftApi := mock.RaftApi{
+			HandleAppendEntriesFunc: func(senderID string, msg raft.AppendEntriesMsg) error {
+				assert.Equal(t, "sender", senderID)
+				assert.Equal(t, "leader", msg.LeaderID)
+				handled = "append"
+				return nil
+			},
+			HandleAckFunc: func(ack raft.AppendEntriesAck) error {
+				assert.Equal(t, "follower1", ack.SenderID)
+				handled = "ack"
+				return nil
+			},
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(raftApi)
+		body, err := common.Serialize(test.input.body)
+		assert.NoError(t, err)
+
+		// when
+		err = grpcCommandHandler.HandleGrpcCommand(command.ReceiveGrpc{Body: body, Protocol: test.input.protocol, ConnectionID: "sender"})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.handled, handled)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_MalformedBody(t *testing.T) {
+
+	// given
+	tests := map[string]struct {
+		input string
+	}{
+		"append entries":     {input: "AppendEntriesProtocol"},
+		"append entries ack": {input: "AppendEntriesAckProtocol"},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		handled := false
+		raftApi := mock.RaftApi{
+			HandleAppendEntriesFunc: func(senderID string, msg raft.AppendEntriesMsg) error {
+				handled = true
+				return nil
+			},
+			HandleAckFunc: func(ack raft.AppendEntriesAck) error {
+				handled = true
+				return nil
+			},
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(raftApi)
+
+		// when
+		err := grpcCommandHandler.HandleGrpcCommand(command.ReceiveGrpc{Body: []byte("{"), Protocol: test.input})
+
+		// then
+		assert.Error(t, err)
+		assert.False(t, handled)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/it-chain/engine/api_gateway"
+	"github.com/it-chain/engine/consensus/raft"
+)
+
+// p2p component에서 선출된 leader와 연결된 peer를 조회한다.
+type LeaderService struct {
+	pQuery *api_gateway.PeerQueryApi
+}
+
+func NewLeaderService(api *api_gateway.PeerQueryApi) *LeaderService {
+	return &LeaderService{
+		pQuery: api,
+	}
+}
+
+func (ls *LeaderService) RequestLeader() (raft.Leader, error) {
+	leader, err := ls.pQuery.GetLeader()
+
+	if err != nil {
+		return raft.Leader{}, err
+	}
+
+	return raft.Leader{ID: leader.GetID(), Term: leader.Term}, nil
+}
+
+func (ls *LeaderService) RequestPeerList() ([]string, error) {
+	peers, err := ls.pQuery.GetPeerList()
+
+	if err != nil {
+		return nil, err
+	}
+
+	peerIDs := make([]string, 0)
+	for _, peer := range peers {
+		peerIDs = append(peerIDs, peer.PeerId.Id)
+	}
+
+	return peerIDs, nil
+}
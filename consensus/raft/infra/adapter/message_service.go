@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"errors"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/rs/xid"
+)
+
+var ErrEmptyRecipient = errors.New("Recipient is empty")
+
+type Publish func(topic string, data interface{}) (err error)
+
+type MessageService struct {
+	publish Publish
+}
+
+func NewMessageService(publish Publish) MessageService {
+	return MessageService{
+		publish: publish,
+	}
+}
+
+func (ms MessageService) SendAppendEntries(msg raft.AppendEntriesMsg, recipients []string) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	deliverCommand, err := createDeliverGrpcCommand("AppendEntriesProtocol", msg)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.RecipientList = append(deliverCommand.RecipientList, recipients...)
+
+	return ms.publish("message.deliver", deliverCommand)
+}
+
+func (ms MessageService) SendAck(ack raft.AppendEntriesAck, recipient string) error {
+	if recipient == "" {
+		return ErrEmptyRecipient
+	}
+
+	deliverCommand, err := createDeliverGrpcCommand("AppendEntriesAckProtocol", ack)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.RecipientList = append(deliverCommand.RecipientList, recipient)
+
+	return ms.publish("message.deliver", deliverCommand)
+}
+
+func createDeliverGrpcCommand(protocol string, body interface{}) (command.DeliverGrpc, error) {
+	data, err := common.Serialize(body)
+
+	if err != nil {
+		return command.DeliverGrpc{}, err
+	}
+
+	return command.DeliverGrpc{
+		MessageId:     xid.New().String(),
+		RecipientList: make([]string, 0),
+		Body:          data,
+		Protocol:      protocol,
+	}, nil
+}
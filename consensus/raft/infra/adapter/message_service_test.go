@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/it-chain/engine/consensus/raft/infra/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageService_SendAppendEntries(t *testing.T) {
+
+	// given
+	msg := raft.AppendEntriesMsg{Term: 1, LeaderID: "leader", CommitIndex: 1}
+	published := false
+
+	messageService := adapter.NewMessageService(func(topic string, data interface{}) error {
+		published = true
+
+		// then
+		assert.Equal(t, "message.deliver", topic)
+		deliverCommand, ok := data.(command.DeliverGrpc)
+		assert.True(t, ok)
+		assert.Equal(t, "AppendEntriesProtocol", deliverCommand.Protocol)
+		assert.Equal(t, []string{"follower1", "follower2"}, deliverCommand.RecipientList)
+
+		sentMsg := raft.AppendEntriesMsg{}
+		assert.NoError(t, common.Deserialize(deliverCommand.Body, &sentMsg))
+		assert.Equal(t, msg, sentMsg)
+
+		return nil
+	})
+
+	// when
+	err := messageService.SendAppendEntries(msg, []string{"follower1", "follower2"})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, published)
+
+	// 받을 follower가 없는 경우 보내지 않는다.
+	published = false
+	assert.NoError(t, messageService.SendAppendEntries(msg, []string{}))
+	assert.False(t, published)
+}
+
+func TestMessageService_SendAck(t *testing.T) {
+
+	// given
+	ack := raft.AppendEntriesAck{Term: 1, SenderID: "follower1", MatchIndex: 3, Success: true}
+
+	messageService := adapter.NewMessageService(func(topic string, data interface{}) error {
+
+		// then
+		assert.Equal(t, "message.deliver", topic)
+		deliverCommand, ok := data.(command.DeliverGrpc)
+		assert.True(t, ok)
+		assert.Equal(t, "AppendEntriesAckProtocol", deliverCommand.Protocol)
+		assert.Equal(t, []string{"leader"}, deliverCommand.RecipientList)
+
+		return nil
+	})
+
+	// when
+	err1 := messageService.SendAck(ack, "leader")
+	err2 := messageService.SendAck(ack, "")
+
+	// then
+	assert.NoError(t, err1)
+	assert.Equal(t, adapter.ErrEmptyRecipient, err2)
+}
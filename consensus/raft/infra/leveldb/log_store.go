@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb
+
+import (
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+var hardStateKey = []byte("raft")
+
+// replica의 term, log, commit index를 leveldb에 저장한다.
+// replica가 재시작 되어도 이미 저장했다고 응답한 entry를 잃지 않는다.
+type LogStore struct {
+	leveldb *leveldbwrapper.DB
+}
+
+func NewLogStore(path string) *LogStore {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+	return &LogStore{
+		leveldb: db,
+	}
+}
+
+func (s *LogStore) Save(state raft.HardState) error {
+	b, err := common.Serialize(state)
+	if err != nil {
+		return err
+	}
+
+	// ack를 보내기 전에 디스크에 남도록 sync write 한다.
+	return s.leveldb.Put(hardStateKey, b, true)
+}
+
+func (s *LogStore) Load() (raft.HardState, error) {
+	b, err := s.leveldb.Get(hardStateKey)
+	if err != nil {
+		return raft.HardState{}, err
+	}
+
+	if len(b) == 0 {
+		return raft.HardState{}, raft.ErrEmptyLog
+	}
+
+	state := raft.HardState{}
+	if err := common.Deserialize(b, &state); err != nil {
+		return raft.HardState{}, err
+	}
+
+	return state, nil
+}
+
+func (s *LogStore) Close() {
+	s.leveldb.Close()
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/it-chain/engine/consensus/raft/infra/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogStore(t *testing.T) {
+	dbPath := "./.db"
+	defer os.RemoveAll(dbPath)
+
+	store := leveldb.NewLogStore(dbPath)
+
+	// case 1 : empty store
+	_, err := store.Load()
+	assert.Equal(t, raft.ErrEmptyLog, err)
+
+	// case 2 : save and load
+	state := raft.HardState{
+		Term: 3,
+		Log: []raft.Entry{
+			{Index: 1, Term: 1, Block: consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}},
+			{Index: 2, Term: 3, Block: consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}},
+		},
+		CommitIndex:  2,
+		AppliedIndex: 1,
+	}
+
+	err = store.Save(state)
+	assert.NoError(t, err)
+
+	// case 3 : log is recovered after the store is reopened
+	store.Close()
+	store = leveldb.NewLogStore(dbPath)
+	defer store.Close()
+
+	loadedState, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, state, loadedState)
+}
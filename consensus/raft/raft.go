@@ -0,0 +1,106 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package raft
+
+import (
+	"errors"
+
+	"github.com/it-chain/engine/consensus"
+)
+
+var ErrNotLeader = errors.New("Only leader can append entry")
+var ErrEmptyBlock = errors.New("Proposed block is empty")
+var ErrLeaderNotReady = errors.New("Leader has not checked the logs of a majority yet")
+var ErrSoleMember = errors.New("Leader is the only member while other peers exist")
+var ErrEmptyLog = errors.New("Log store is empty")
+var ErrInvalidSender = errors.New("Sender is not the leader of the message")
+var ErrNotMember = errors.New("Leader is not a member")
+var ErrNotElectedLeader = errors.New("Leader is not elected for the term")
+
+// replica들이 같은 순서로 저장하는 log의 entry이다.
+type Entry struct {
+	Index uint64
+	Term  uint64
+	Block consensus.ProposedBlock
+}
+
+// leader가 follower에게 entry를 복제하기 위해 보내는 message이다.
+// Entries가 비어있는 경우 commit index만 전달한다.
+// message는 항상 leader log의 마지막 entry까지 담으므로, follower는 message로 leader의 log가 얼마나 최신인지 알 수 있다.
+// PrevSeal은 PrevIndex entry의 block seal이다. term 만으로는 같은 entry인지 알 수 없으므로 함께 비교한다.
+type AppendEntriesMsg struct {
+	Term        uint64
+	LeaderID    string
+	PrevIndex   uint64
+	PrevTerm    uint64
+	PrevSeal    []byte
+	Entries     []Entry
+	CommitIndex uint64
+}
+
+// leader log의 마지막 index와 term
+func (msg AppendEntriesMsg) last() (uint64, uint64) {
+	if len(msg.Entries) == 0 {
+		return msg.PrevIndex, msg.PrevTerm
+	}
+
+	last := msg.Entries[len(msg.Entries)-1]
+	return last.Index, last.Term
+}
+
+// AppendEntriesMsg에 대한 follower의 응답이다.
+// 실패한 경우 MatchIndex는 follower가 leader와 일치한다고 확인된 마지막 index이다.
+// follower의 log가 leader보다 최신인 경우 Entries에 leader가 commit 한 다음 entry부터 담는다.
+type AppendEntriesAck struct {
+	Term       uint64
+	SenderID   string
+	MatchIndex uint64
+	Success    bool
+	Entries    []Entry
+}
+
+// 재시작 후에도 잃으면 안 되는 replica의 상태이다.
+// replica는 이 상태를 저장한 뒤에 다른 replica에 message를 보낸다.
+type HardState struct {
+	Term         uint64
+	Log          []Entry
+	CommitIndex  uint64
+	AppliedIndex uint64
+}
+
+type LogStore interface {
+	Save(state HardState) error
+	Load() (HardState, error)
+}
+
+type MessageService interface {
+	SendAppendEntries(msg AppendEntriesMsg, recipients []string) error
+	SendAck(ack AppendEntriesAck, recipient string) error
+}
+
+// p2p component에서 과반의 투표로 선출된 leader와 그 term이다.
+type Leader struct {
+	ID   string
+	Term uint64
+}
+
+// raft는 block의 순서만 정하며 leader와 term은 p2p component에서 선출된 leader를 따른다.
+// leader는 member가 자기 자신뿐인데 연결된 peer가 있는 경우 block을 제안하지 않는다.
+type LeaderService interface {
+	RequestLeader() (Leader, error)
+	RequestPeerList() ([]string, error)
+}
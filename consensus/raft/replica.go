@@ -0,0 +1,423 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package raft
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/it-chain/engine/consensus"
+)
+
+// raft log를 복제하는 replica이다.
+// leader는 entry를 만들어 follower에게 복제하고, 과반수의 replica가 저장한 entry를 commit 한다.
+// crash fault만 견디므로 byzantine fault가 없는 permissioned 환경에서 사용한다.
+type Replica struct {
+	ID           string
+	Term         uint64
+	LeaderID     string
+	Members      map[string]struct{}
+	Log          []Entry
+	CommitIndex  uint64
+	AppliedIndex uint64
+	MatchIndex   map[string]uint64
+	// leader가 된 뒤 log를 확인한 member이다.
+	// 과반수의 log를 확인하고 그 중 가장 최신의 log를 가지기 전에는 entry를 추가하지 않는다.
+	Checked map[string]struct{}
+}
+
+func NewReplica(id string) *Replica {
+	return &Replica{
+		ID:         id,
+		Members:    map[string]struct{}{id: {}},
+		Log:        make([]Entry, 0),
+		MatchIndex: make(map[string]uint64),
+		Checked:    make(map[string]struct{}),
+	}
+}
+
+// 재시작 후에도 잃으면 안 되는 상태를 반환한다.
+func (r *Replica) HardState() HardState {
+	return HardState{
+		Term:         r.Term,
+		Log:          r.Log,
+		CommitIndex:  r.CommitIndex,
+		AppliedIndex: r.AppliedIndex,
+	}
+}
+
+func (r *Replica) Restore(state HardState) {
+	r.Term = state.Term
+	r.Log = state.Log
+	r.CommitIndex = state.CommitIndex
+	r.AppliedIndex = state.AppliedIndex
+
+	if r.Log == nil {
+		r.Log = make([]Entry, 0)
+	}
+}
+
+func (r *Replica) IsLeader() bool {
+	return r.LeaderID == r.ID
+}
+
+// p2p election에서 선출된 term의 leader가 되고, follower들의 복제 상태를 처음부터 다시 확인한다.
+// 이미 사용한 term에서는 다른 leader가 있었을 수 있으므로 leader가 되지 않는다.
+func (r *Replica) BecomeLeader(term uint64) bool {
+	if term <= r.Term {
+		return false
+	}
+
+	r.Term = term
+	r.LeaderID = r.ID
+	r.MatchIndex = map[string]uint64{r.ID: r.LastIndex()}
+	r.Checked = map[string]struct{}{r.ID: {}}
+
+	return true
+}
+
+// 과반수 member의 log를 확인한 leader만 entry를 추가할 수 있다.
+// 합의된 entry는 과반수의 log에 있으므로, 확인한 log 중 가장 최신의 log는 합의된 entry를 모두 가지고 있다.
+func (r *Replica) IsReady() bool {
+	if !r.IsLeader() {
+		return false
+	}
+
+	checked := 0
+	for memberID := range r.Members {
+		if _, ok := r.Checked[memberID]; ok {
+			checked++
+		}
+	}
+
+	return checked >= r.quorum()
+}
+
+// 아직 log를 확인하지 못한 follower
+func (r *Replica) UncheckedFollowers() []string {
+	followers := make([]string, 0)
+
+	for _, memberID := range r.Followers() {
+		if _, ok := r.Checked[memberID]; !ok {
+			followers = append(followers, memberID)
+		}
+	}
+
+	return followers
+}
+
+func (r *Replica) Follow(leaderID string) {
+	r.LeaderID = leaderID
+}
+
+func (r *Replica) AddMember(memberID string) {
+	r.Members[memberID] = struct{}{}
+}
+
+func (r *Replica) RemoveMember(memberID string) {
+	if memberID == r.ID {
+		return
+	}
+
+	delete(r.Members, memberID)
+	delete(r.MatchIndex, memberID)
+	delete(r.Checked, memberID)
+}
+
+// 자기 자신을 제외한 member
+func (r *Replica) Followers() []string {
+	followers := make([]string, 0)
+
+	for memberID := range r.Members {
+		if memberID != r.ID {
+			followers = append(followers, memberID)
+		}
+	}
+
+	sort.Strings(followers)
+
+	return followers
+}
+
+func (r *Replica) LastIndex() uint64 {
+	return uint64(len(r.Log))
+}
+
+func (r *Replica) LastTerm() uint64 {
+	return r.termOf(r.LastIndex())
+}
+
+// 마지막 entry의 term이 더 크거나, term이 같고 더 긴 log가 더 최신이다.
+func (r *Replica) isUpToDate(lastIndex uint64, lastTerm uint64) bool {
+	if lastTerm != r.LastTerm() {
+		return lastTerm > r.LastTerm()
+	}
+
+	return lastIndex >= r.LastIndex()
+}
+
+func (r *Replica) quorum() int {
+	return len(r.Members)/2 + 1
+}
+
+func (r *Replica) termOf(index uint64) uint64 {
+	if index == 0 || index > r.LastIndex() {
+		return 0
+	}
+
+	return r.Log[index-1].Term
+}
+
+func (r *Replica) sealOf(index uint64) []byte {
+	if index == 0 || index > r.LastIndex() {
+		return nil
+	}
+
+	return r.Log[index-1].Block.Seal
+}
+
+// index의 entry가 같은 term의 같은 block인지 확인한다.
+func (r *Replica) matches(index uint64, term uint64, seal []byte) bool {
+	return r.termOf(index) == term && bytes.Equal(r.sealOf(index), seal)
+}
+
+// leader가 block을 log에 추가하고 follower에게 보낼 message를 만든다.
+func (r *Replica) Append(block consensus.ProposedBlock) (AppendEntriesMsg, error) {
+	if !r.IsLeader() {
+		return AppendEntriesMsg{}, ErrNotLeader
+	}
+
+	if block.Seal == nil {
+		return AppendEntriesMsg{}, ErrEmptyBlock
+	}
+
+	if !r.IsReady() {
+		return AppendEntriesMsg{}, ErrLeaderNotReady
+	}
+
+	prevIndex := r.LastIndex()
+	entry := Entry{
+		Index: prevIndex + 1,
+		Term:  r.Term,
+		Block: block,
+	}
+
+	r.Log = append(r.Log, entry)
+	r.MatchIndex[r.ID] = entry.Index
+	r.advanceCommitIndex()
+
+	return AppendEntriesMsg{
+		Term:        r.Term,
+		LeaderID:    r.ID,
+		PrevIndex:   prevIndex,
+		PrevTerm:    r.termOf(prevIndex),
+		PrevSeal:    r.sealOf(prevIndex),
+		Entries:     []Entry{entry},
+		CommitIndex: r.CommitIndex,
+	}, nil
+}
+
+// follower에게 nextIndex 부터의 entry를 다시 보내기 위한 message를 만든다.
+func (r *Replica) AppendEntriesFrom(nextIndex uint64) AppendEntriesMsg {
+	if nextIndex == 0 {
+		nextIndex = 1
+	}
+
+	if nextIndex > r.LastIndex()+1 {
+		nextIndex = r.LastIndex() + 1
+	}
+
+	prevIndex := nextIndex - 1
+	entries := make([]Entry, 0)
+	entries = append(entries, r.Log[prevIndex:]...)
+
+	return AppendEntriesMsg{
+		Term:        r.Term,
+		LeaderID:    r.ID,
+		PrevIndex:   prevIndex,
+		PrevTerm:    r.termOf(prevIndex),
+		PrevSeal:    r.sealOf(prevIndex),
+		Entries:     entries,
+		CommitIndex: r.CommitIndex,
+	}
+}
+
+// follower가 leader의 entry를 저장한다.
+// 이전 term의 leader가 보낸 message나, 이전 entry의 term과 seal이 일치하지 않는 message는 거절한다.
+// message를 보낸 peer가 term의 leader인지는 호출하기 전에 확인되어야 한다.
+// leader의 log가 자신의 log보다 오래된 경우 거절하고, leader가 commit 한 다음 entry부터 자신의 entry를 보내준다.
+func (r *Replica) HandleAppendEntries(msg AppendEntriesMsg) AppendEntriesAck {
+	if msg.Term < r.Term {
+		return AppendEntriesAck{Term: r.Term, SenderID: r.ID, MatchIndex: r.CommitIndex, Success: false}
+	}
+
+	r.Term = msg.Term
+	r.LeaderID = msg.LeaderID
+
+	if lastIndex, lastTerm := msg.last(); !r.isUpToDate(lastIndex, lastTerm) {
+		entries := make([]Entry, 0)
+		if msg.CommitIndex < r.LastIndex() {
+			entries = append(entries, r.Log[msg.CommitIndex:]...)
+		}
+
+		return AppendEntriesAck{Term: r.Term, SenderID: r.ID, MatchIndex: r.CommitIndex, Success: false, Entries: entries}
+	}
+
+	if msg.PrevIndex > r.LastIndex() || !r.matches(msg.PrevIndex, msg.PrevTerm, msg.PrevSeal) {
+		matchIndex := r.CommitIndex
+		if msg.PrevIndex > r.LastIndex() && r.LastIndex() > matchIndex {
+			matchIndex = r.LastIndex()
+		}
+
+		return AppendEntriesAck{Term: r.Term, SenderID: r.ID, MatchIndex: matchIndex, Success: false}
+	}
+
+	for _, entry := range msg.Entries {
+		if entry.Index <= r.LastIndex() {
+			if r.matches(entry.Index, entry.Term, entry.Block.Seal) {
+				continue
+			}
+
+			// commit 된 entry는 덮어쓰지 않는다.
+			if entry.Index <= r.CommitIndex {
+				return AppendEntriesAck{Term: r.Term, SenderID: r.ID, MatchIndex: r.CommitIndex, Success: false}
+			}
+
+			// leader와 다른 entry는 leader의 것으로 덮어쓴다.
+			r.Log = r.Log[:entry.Index-1]
+		}
+
+		r.Log = append(r.Log, entry)
+	}
+
+	lastNewIndex := msg.PrevIndex + uint64(len(msg.Entries))
+	if commitIndex := minIndex(msg.CommitIndex, lastNewIndex); commitIndex > r.CommitIndex {
+		r.CommitIndex = commitIndex
+	}
+
+	return AppendEntriesAck{Term: r.Term, SenderID: r.ID, MatchIndex: lastNewIndex, Success: true}
+}
+
+// leader가 follower의 응답을 반영한다.
+// 더 높은 term을 가진 응답을 받은 경우 leader에서 물러난다.
+// follower의 log가 더 최신인 경우 follower가 보낸 entry를 받아 log를 따라잡는다.
+func (r *Replica) HandleAck(ack AppendEntriesAck) {
+	if ack.Term > r.Term {
+		r.Term = ack.Term
+		r.LeaderID = ""
+		return
+	}
+
+	if !r.IsLeader() || ack.Term < r.Term {
+		return
+	}
+
+	if _, ok := r.Members[ack.SenderID]; !ok {
+		return
+	}
+
+	r.Checked[ack.SenderID] = struct{}{}
+
+	if !ack.Success {
+		r.catchUp(ack)
+		return
+	}
+
+	if ack.MatchIndex > r.MatchIndex[ack.SenderID] {
+		r.MatchIndex[ack.SenderID] = ack.MatchIndex
+	}
+
+	r.advanceCommitIndex()
+}
+
+// follower가 보낸 더 최신의 entry로 leader의 commit 되지 않은 entry를 대신한다.
+func (r *Replica) catchUp(ack AppendEntriesAck) {
+	if len(ack.Entries) == 0 {
+		return
+	}
+
+	last := ack.Entries[len(ack.Entries)-1]
+	if last.Index == r.LastIndex() && r.matches(last.Index, last.Term, last.Block.Seal) || !r.isUpToDate(last.Index, last.Term) {
+		return
+	}
+
+	for _, entry := range ack.Entries {
+		if entry.Index > r.LastIndex()+1 {
+			return
+		}
+
+		if entry.Index <= r.LastIndex() {
+			if r.matches(entry.Index, entry.Term, entry.Block.Seal) {
+				continue
+			}
+
+			if entry.Index <= r.CommitIndex {
+				return
+			}
+
+			r.Log = r.Log[:entry.Index-1]
+		}
+
+		r.Log = append(r.Log, entry)
+	}
+
+	r.MatchIndex[r.ID] = r.LastIndex()
+	r.MatchIndex[ack.SenderID] = r.LastIndex()
+}
+
+// 과반수의 member가 저장한 entry 중 현재 term의 entry까지 commit 한다.
+func (r *Replica) advanceCommitIndex() {
+	quorum := r.quorum()
+
+	for index := r.LastIndex(); index > r.CommitIndex; index-- {
+		if r.termOf(index) != r.Term {
+			break
+		}
+
+		replicated := 0
+		for memberID := range r.Members {
+			if r.MatchIndex[memberID] >= index {
+				replicated++
+			}
+		}
+
+		if replicated >= quorum {
+			r.CommitIndex = index
+			return
+		}
+	}
+}
+
+// commit 되었지만 아직 전달되지 않은 entry를 순서대로 반환한다.
+func (r *Replica) TakeCommittedEntries() []Entry {
+	entries := make([]Entry, 0)
+
+	for r.AppliedIndex < r.CommitIndex {
+		r.AppliedIndex++
+		entries = append(entries, r.Log[r.AppliedIndex-1])
+	}
+
+	return entries
+}
+
+func minIndex(a uint64, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,344 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package raft_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/raft"
+	"github.com/stretchr/testify/assert"
+)
+
+var block1 = consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")}
+var block2 = consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")}
+
+func TestReplica_Append(t *testing.T) {
+
+	// given
+	follower := raft.NewReplica("follower")
+	leader := raft.NewReplica("leader")
+	assert.True(t, leader.BecomeLeader(1))
+
+	// when
+	_, errOfFollower := follower.Append(block1)
+	_, errOfEmptyBlock := leader.Append(consensus.ProposedBlock{})
+	msg1, err1 := leader.Append(block1)
+	msg2, err2 := leader.Append(block2)
+
+	// then
+	assert.Equal(t, raft.ErrNotLeader, errOfFollower)
+	assert.Equal(t, raft.ErrEmptyBlock, errOfEmptyBlock)
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+
+	assert.Equal(t, uint64(1), leader.Term)
+	assert.Equal(t, uint64(0), msg1.PrevIndex)
+	assert.Equal(t, uint64(1), msg2.PrevIndex)
+	assert.Equal(t, uint64(1), msg2.PrevTerm)
+	assert.Equal(t, uint64(2), msg2.Entries[0].Index)
+
+	// leader 혼자인 경우 바로 commit 된다.
+	assert.Equal(t, uint64(2), leader.CommitIndex)
+}
+
+func TestReplica_HandleAppendEntries(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			term      uint64
+			log       []raft.Entry
+			msg       raft.AppendEntriesMsg
+			commitIdx uint64
+		}
+		output struct {
+			success    bool
+			matchIndex uint64
+			lastIndex  uint64
+			commitIdx  uint64
+		}
+	}{
+		"Case 1 빈 log에 entry를 저장한다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 0,
+				log:  []raft.Entry{},
+				msg: raft.AppendEntriesMsg{Term: 1, LeaderID: "leader", PrevIndex: 0, PrevTerm: 0,
+					Entries: []raft.Entry{{Index: 1, Term: 1, Block: block1}}, CommitIndex: 1},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{true, 1, 1, 1},
+		},
+		"Case 2 이전 term의 leader가 보낸 message는 거절한다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 2,
+				log:  []raft.Entry{},
+				msg: raft.AppendEntriesMsg{Term: 1, LeaderID: "leader", PrevIndex: 0, PrevTerm: 0,
+					Entries: []raft.Entry{{Index: 1, Term: 1, Block: block1}}},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{false, 0, 0, 0},
+		},
+		"Case 3 이전 entry가 없는 경우 거절한다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 1,
+				log:  []raft.Entry{},
+				msg: raft.AppendEntriesMsg{Term: 1, LeaderID: "leader", PrevIndex: 1, PrevTerm: 1,
+					Entries: []raft.Entry{{Index: 2, Term: 1, Block: block2}}},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{false, 0, 0, 0},
+		},
+		"Case 4 commit 되지 않은 다른 entry는 leader의 entry로 덮어쓴다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 1,
+				log:  []raft.Entry{{Index: 1, Term: 1, Block: block1}, {Index: 2, Term: 1, Block: block1}},
+				msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "leader", PrevIndex: 1, PrevTerm: 1, PrevSeal: block1.Seal,
+					Entries: []raft.Entry{{Index: 2, Term: 2, Block: block2}}, CommitIndex: 2},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{true, 2, 2, 2},
+		},
+		"Case 5 commit 된 entry는 덮어쓰지 않는다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term:      1,
+				log:       []raft.Entry{{Index: 1, Term: 1, Block: block1}},
+				commitIdx: 1,
+				msg: raft.AppendEntriesMsg{Term: 2, LeaderID: "leader", PrevIndex: 0, PrevTerm: 0,
+					Entries: []raft.Entry{{Index: 1, Term: 2, Block: block2}}},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{false, 1, 1, 1},
+		},
+		"Case 6 leader의 log가 더 오래된 경우 거절한다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 1,
+				log:  []raft.Entry{{Index: 1, Term: 1, Block: block1}, {Index: 2, Term: 1, Block: block2}},
+				msg:  raft.AppendEntriesMsg{Term: 2, LeaderID: "leader", PrevIndex: 1, PrevTerm: 1, PrevSeal: block1.Seal},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{false, 0, 2, 0},
+		},
+		"Case 7 이전 entry의 term이 같아도 seal이 다르면 거절한다": {
+			input: struct {
+				term      uint64
+				log       []raft.Entry
+				msg       raft.AppendEntriesMsg
+				commitIdx uint64
+			}{
+				term: 1,
+				log:  []raft.Entry{{Index: 1, Term: 1, Block: block1}},
+				msg: raft.AppendEntriesMsg{Term: 1, LeaderID: "leader", PrevIndex: 1, PrevTerm: 1, PrevSeal: block2.Seal,
+					Entries: []raft.Entry{{Index: 2, Term: 1, Block: block2}}, CommitIndex: 2},
+			},
+			output: struct {
+				success    bool
+				matchIndex uint64
+				lastIndex  uint64
+				commitIdx  uint64
+			}{false, 0, 1, 0},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		replica := raft.NewReplica("follower")
+		replica.Term = test.input.term
+		replica.Log = test.input.log
+		replica.CommitIndex = test.input.commitIdx
+
+		// when
+		ack := replica.HandleAppendEntries(test.input.msg)
+
+		// then
+		assert.Equal(t, test.output.success, ack.Success)
+		assert.Equal(t, test.output.matchIndex, ack.MatchIndex)
+		assert.Equal(t, test.output.lastIndex, replica.LastIndex())
+		assert.Equal(t, test.output.commitIdx, replica.CommitIndex)
+	}
+}
+
+func TestReplica_HandleAck(t *testing.T) {
+
+	// given
+	leader := raft.NewReplica("leader")
+	leader.AddMember("follower1")
+	leader.AddMember("follower2")
+	assert.True(t, leader.BecomeLeader(1))
+
+	// then : 과반수의 log를 확인하기 전에는 entry를 추가하지 않는다.
+	_, err := leader.Append(block1)
+	assert.Equal(t, raft.ErrLeaderNotReady, err)
+
+	// when
+	leader.HandleAck(raft.AppendEntriesAck{Term: 1, SenderID: "follower1", MatchIndex: 0, Success: true})
+	_, err = leader.Append(block1)
+
+	// then : 과반수가 저장하기 전에는 commit 되지 않는다.
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), leader.CommitIndex)
+
+	// when
+	leader.HandleAck(raft.AppendEntriesAck{Term: 1, SenderID: "follower1", MatchIndex: 1, Success: true})
+
+	// then
+	assert.Equal(t, uint64(1), leader.CommitIndex)
+	entries := leader.TakeCommittedEntries()
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, block1.Seal, entries[0].Block.Seal)
+	assert.Equal(t, 0, len(leader.TakeCommittedEntries()))
+
+	// when : 더 높은 term을 가진 응답을 받으면 leader에서 물러난다.
+	leader.HandleAck(raft.AppendEntriesAck{Term: 3, SenderID: "follower2", Success: false})
+
+	// then
+	assert.False(t, leader.IsLeader())
+	assert.Equal(t, uint64(3), leader.Term)
+}
+
+func TestReplica_HandleAck_CatchUp(t *testing.T) {
+
+	// given : follower1은 이전 leader로부터 leader보다 더 많은 entry를 받았다.
+	leader := raft.NewReplica("leader")
+	leader.AddMember("follower1")
+	leader.AddMember("follower2")
+	leader.Term = 1
+	leader.Log = []raft.Entry{{Index: 1, Term: 1, Block: block1}}
+	assert.True(t, leader.BecomeLeader(2))
+
+	follower1 := raft.NewReplica("follower1")
+	follower1.Term = 1
+	follower1.Log = []raft.Entry{{Index: 1, Term: 1, Block: block1}, {Index: 2, Term: 1, Block: block2}}
+
+	// when
+	ack := follower1.HandleAppendEntries(leader.AppendEntriesFrom(leader.LastIndex() + 1))
+
+	// then : follower는 leader에게 없는 entry를 보내준다.
+	assert.False(t, ack.Success)
+	assert.Equal(t, 2, len(ack.Entries))
+
+	// when
+	leader.HandleAck(ack)
+
+	// then : leader는 follower의 entry를 받아 따라잡은 뒤에 entry를 추가할 수 있다.
+	assert.Equal(t, uint64(2), leader.LastIndex())
+	assert.Equal(t, block2.Seal, leader.Log[1].Block.Seal)
+	assert.True(t, leader.IsReady())
+
+	// when
+	msg, err := leader.Append(consensus.ProposedBlock{Seal: []byte("seal3")})
+	ack = follower1.HandleAppendEntries(msg)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, ack.Success)
+	assert.Equal(t, uint64(3), ack.MatchIndex)
+}
+
+func TestReplica_BecomeLeader(t *testing.T) {
+
+	// given
+	replica := raft.NewReplica("leader")
+	replica.Term = 3
+
+	// when : 이미 사용한 term
+	becameOfUsedTerm := replica.BecomeLeader(3)
+
+	// then
+	assert.False(t, becameOfUsedTerm)
+	assert.False(t, replica.IsLeader())
+
+	// when : p2p election에서 선출된 새 term
+	becameOfElectedTerm := replica.BecomeLeader(5)
+
+	// then : election의 term을 그대로 사용한다.
+	assert.True(t, becameOfElectedTerm)
+	assert.True(t, replica.IsLeader())
+	assert.Equal(t, uint64(5), replica.Term)
+}
+
+func TestReplica_HandleAppendEntries_SameTermOtherBlock(t *testing.T) {
+
+	// given : index와 term은 같지만 block이 다른 commit 되지 않은 entry
+	replica := raft.NewReplica("follower")
+	replica.Term = 1
+	replica.Log = []raft.Entry{{Index: 1, Term: 1, Block: block1}}
+
+	// when
+	ack := replica.HandleAppendEntries(raft.AppendEntriesMsg{Term: 1, LeaderID: "leader",
+		Entries: []raft.Entry{{Index: 1, Term: 1, Block: block2}}, CommitIndex: 1})
+
+	// then : leader의 entry로 덮어쓴다.
+	assert.True(t, ack.Success)
+	assert.Equal(t, uint64(1), replica.LastIndex())
+	assert.Equal(t, block2.Seal, replica.Log[0].Block.Seal)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"github.com/it-chain/engine/consensus/raft"
+)
+
+type MessageService struct {
+	SendAppendEntriesFunc func(msg raft.AppendEntriesMsg, recipients []string) error
+	SendAckFunc           func(ack raft.AppendEntriesAck, recipient string) error
+}
+
+func (m MessageService) SendAppendEntries(msg raft.AppendEntriesMsg, recipients []string) error {
+	return m.SendAppendEntriesFunc(msg, recipients)
+}
+
+func (m MessageService) SendAck(ack raft.AppendEntriesAck, recipient string) error {
+	return m.SendAckFunc(ack, recipient)
+}
+
+type LeaderService struct {
+	RequestLeaderFunc   func() (raft.Leader, error)
+	RequestPeerListFunc func() ([]string, error)
+}
+
+func (m LeaderService) RequestLeader() (raft.Leader, error) {
+	return m.RequestLeaderFunc()
+}
+
+func (m LeaderService) RequestPeerList() ([]string, error) {
+	return m.RequestPeerListFunc()
+}
+
+type LogStore struct {
+	SaveFunc func(state raft.HardState) error
+	LoadFunc func() (raft.HardState, error)
+}
+
+func (m LogStore) Save(state raft.HardState) error {
+	return m.SaveFunc(state)
+}
+
+func (m LogStore) Load() (raft.HardState, error) {
+	return m.LoadFunc()
+}
+
+type RaftApi struct {
+	HandleAppendEntriesFunc func(senderID string, msg raft.AppendEntriesMsg) error
+	HandleAckFunc           func(ack raft.AppendEntriesAck) error
+}
+
+func (m RaftApi) HandleAppendEntries(senderID string, msg raft.AppendEntriesMsg) error {
+	return m.HandleAppendEntriesFunc(senderID, msg)
+}
+
+func (m RaftApi) HandleAck(ack raft.AppendEntriesAck) error {
+	return m.HandleAckFunc(ack)
+}
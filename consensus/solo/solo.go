@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package solo
+
+import (
+	"sync"
+
+	"github.com/it-chain/engine/consensus"
+)
+
+// node 혼자 block의 순서를 정하는 합의 방식이다.
+// 제안된 block은 곧바로 합의된 것으로 보고 전달한다.
+type Consensus struct {
+	deliver consensus.DeliverHandler
+	mutex   sync.Mutex
+}
+
+func NewConsensus() *Consensus {
+	return &Consensus{
+		mutex: sync.Mutex{},
+	}
+}
+
+func (c *Consensus) Propose(block consensus.ProposedBlock) error {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.deliver == nil {
+		return nil
+	}
+
+	return c.deliver(block)
+}
+
+func (c *Consensus) OnDeliver(handler consensus.DeliverHandler) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.deliver = handler
+}
+
+func (c *Consensus) IsProposer() bool {
+	return true
+}
+
+// solo는 자기 자신만 member 이므로 membership 변경을 무시한다.
+func (c *Consensus) AddMember(memberID string) error {
+	return nil
+}
+
+func (c *Consensus) RemoveMember(memberID string) error {
+	return nil
+}
+
+// membership 변경을 승인할 member가 없으므로 membership transaction은 받지 않는다.
+func (c *Consensus) Members() []string {
+	return nil
+}
+
+func (c *Consensus) Epoch() uint64 {
+	return 0
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package solo_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/solo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensus_Propose(t *testing.T) {
+
+	// given
+	soloConsensus := solo.NewConsensus()
+	deliveredBlocks := make([]consensus.ProposedBlock, 0)
+	soloConsensus.OnDeliver(func(block consensus.ProposedBlock) error {
+		deliveredBlocks = append(deliveredBlocks, block)
+		return nil
+	})
+
+	// when
+	err1 := soloConsensus.Propose(consensus.ProposedBlock{Seal: []byte("seal1"), Body: []byte("body1")})
+	err2 := soloConsensus.Propose(consensus.ProposedBlock{Seal: []byte("seal2"), Body: []byte("body2")})
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.True(t, soloConsensus.IsProposer())
+	assert.Equal(t, 2, len(deliveredBlocks))
+	assert.Equal(t, []byte("seal1"), deliveredBlocks[0].Seal)
+	assert.Equal(t, []byte("seal2"), deliveredBlocks[1].Seal)
+}
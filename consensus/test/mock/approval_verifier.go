@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/it-chain/engine/consensus"
+)
+
+var ErrInvalidApproval = errors.New("Invalid approval")
+
+// PubKey를 서명한 node의 id로 보고, Signature가 digest와 같으면 올바른 서명으로 본다.
+type ApprovalVerifier struct{}
+
+func (ApprovalVerifier) Verify(digest []byte, approval consensus.Approval) (string, error) {
+	if !bytes.Equal(digest, approval.Signature) {
+		return "", ErrInvalidApproval
+	}
+
+	return string(approval.PubKey), nil
+}
+
+// signerIDs가 change를 승인한 transaction signature를 만든다.
+func ApprovalSignature(change consensus.MembershipChange, signerIDs ...string) []byte {
+	approvals := make([]consensus.Approval, 0)
+	for _, id := range signerIDs {
+		approvals = append(approvals, consensus.Approval{PubKey: []byte(id), Signature: change.Digest()})
+	}
+
+	signature, _ := consensus.EncodeApprovals(approvals)
+
+	return signature
+}
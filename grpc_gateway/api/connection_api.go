@@ -19,16 +19,20 @@ package api
 import (
 	"log"
 
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/event"
 	"github.com/it-chain/engine/grpc_gateway"
 )
 
 type ConnectionApi struct {
-	grpcService grpc_gateway.GrpcService
+	grpcService  grpc_gateway.GrpcService
+	eventService common.EventService
 }
 
-func NewConnectionApi(grpcService grpc_gateway.GrpcService) *ConnectionApi {
+func NewConnectionApi(grpcService grpc_gateway.GrpcService, eventService common.EventService) *ConnectionApi {
 	return &ConnectionApi{
-		grpcService: grpcService,
+		grpcService:  grpcService,
+		eventService: eventService,
 	}
 }
 
@@ -52,3 +56,32 @@ func (c ConnectionApi) CloseConnection(connectionID string) error {
 
 	return nil
 }
+
+func (c ConnectionApi) GetConnectionStates() []grpc_gateway.ConnectionStatus {
+
+	return c.grpcService.GetConnectionStates()
+}
+
+// connection이 생기면 다른 component(p2p 등)가 peer를 관리할 수 있도록 connection created event를 publish 한다.
+func (c ConnectionApi) OnConnection(connection grpc_gateway.Connection) {
+
+	err := c.eventService.Publish("connection.created", event.ConnectionCreated{
+		ConnectionID: connection.ConnectionId,
+		Address:      connection.Address,
+	})
+
+	if err != nil {
+		log.Printf("fail to publish connection created event [%s]", err)
+	}
+}
+
+func (c ConnectionApi) OnDisconnection(connection grpc_gateway.Connection) {
+
+	err := c.eventService.Publish("connection.closed", event.ConnectionClosed{
+		ConnectionId: connection.ConnectionId,
+	})
+
+	if err != nil {
+		log.Printf("fail to publish connection closed event [%s]", err)
+	}
+}
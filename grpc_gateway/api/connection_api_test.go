@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/it-chain/engine/grpc_gateway/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockEventService struct {
+	PublishFunc func(topic string, event interface{}) error
+}
+
+func (m MockEventService) Publish(topic string, event interface{}) error {
+	return m.PublishFunc(topic, event)
+}
+
+func TestConnectionApi_OnConnection(t *testing.T) {
+
+	// given
+	published := false
+
+	eventService := MockEventService{}
+	eventService.PublishFunc = func(topic string, e interface{}) error {
+		published = true
+		assert.Equal(t, "connection.created", topic)
+		assert.Equal(t, event.ConnectionCreated{ConnectionID: "peer1", Address: "127.0.0.1:7777"}, e)
+		return nil
+	}
+
+	connectionApi := api.NewConnectionApi(nil, eventService)
+
+	// when
+	connectionApi.OnConnection(grpc_gateway.Connection{ConnectionId: "peer1", Address: "127.0.0.1:7777"})
+
+	// then
+	assert.True(t, published)
+}
+
+func TestConnectionApi_OnDisconnection(t *testing.T) {
+
+	// given
+	published := false
+
+	eventService := MockEventService{}
+	eventService.PublishFunc = func(topic string, e interface{}) error {
+		published = true
+		assert.Equal(t, "connection.closed", topic)
+		assert.Equal(t, event.ConnectionClosed{ConnectionId: "peer1"}, e)
+		return nil
+	}
+
+	connectionApi := api.NewConnectionApi(nil, eventService)
+
+	// when
+	connectionApi.OnDisconnection(grpc_gateway.Connection{ConnectionId: "peer1", Address: "127.0.0.1:7777"})
+
+	// then
+	assert.True(t, published)
+}
@@ -30,8 +30,9 @@ func NewMessageApi(grpcService grpc_gateway.GrpcService) *MessageApi {
 	}
 }
 
-func (c MessageApi) DeliverMessage(body []byte, protocol string, ids ...string) {
+// ack이면 모든 connection의 상대 node가 message를 받았다고 알리기를 기다린다. 보내지 못했거나 ack을 받지 못한 connection은 DeliveryError로 알린다.
+func (c MessageApi) DeliverMessage(messageId string, body []byte, protocol string, ack bool, ids ...string) error {
 
 	//validation rule add
-	c.grpcService.SendMessages(body, protocol, ids...)
+	return c.grpcService.SendMessages(messageId, body, protocol, ack, ids...)
 }
@@ -16,7 +16,51 @@
 
 package grpc_gateway
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
 type Connection struct {
 	ConnectionId string
 	Address      string
 }
+
+// connection의 상태
+type ConnectionState string
+
+const (
+	Connecting ConnectionState = "connecting"
+	Up         ConnectionState = "up"
+	BackingOff ConnectionState = "backing off"
+)
+
+// Attempts는 끊어진 후 다시 dial 한 횟수이다. Direction, ConnectedAt은 연결된 connection만 채워진다.
+type ConnectionStatus struct {
+	ConnectionId string
+	Address      string
+	State        ConnectionState
+	Attempts     int
+	Direction    Direction
+	ConnectedAt  time.Time
+}
+
+// message를 보내지 못한 connection과 그 이유
+type DeliveryError struct {
+	Failures map[string]string
+}
+
+func (e DeliveryError) Error() string {
+
+	failures := make([]string, 0, len(e.Failures))
+
+	for connectionId, reason := range e.Failures {
+		failures = append(failures, fmt.Sprintf("%s: %s", connectionId, reason))
+	}
+
+	sort.Strings(failures)
+
+	return fmt.Sprintf("fail to deliver message to [%s]", strings.Join(failures, ", "))
+}
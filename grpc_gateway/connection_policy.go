@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc_gateway
+
+import "errors"
+
+var ErrPeerNotAllowed = errors.New("peer is not in allow list")
+var ErrPeerDenied = errors.New("peer is in deny list")
+var ErrTooManyInbound = errors.New("too many inbound connections")
+var ErrTooManyOutbound = errors.New("too many outbound connections")
+
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// 받아들일 connection의 수와 연결할 수 있는 peer를 정한다.
+// peer는 public key로 부터 만든 node id로 구분한다.
+type ConnectionPolicy struct {
+	MaxInbound  int      // 0이면 제한하지 않는다.
+	MaxOutbound int      // 0이면 제한하지 않는다.
+	AllowList   []string // 비어있지 않으면 목록에 있는 peer만 연결한다. (permissioned network)
+	DenyList    []string
+}
+
+func (p ConnectionPolicy) Permit(peerId string) error {
+
+	if contains(p.DenyList, peerId) {
+		return ErrPeerDenied
+	}
+
+	if len(p.AllowList) != 0 && !contains(p.AllowList, peerId) {
+		return ErrPeerNotAllowed
+	}
+
+	return nil
+}
+
+// direction 방향으로 count개의 connection이 있을 때 connection을 하나 더 맺을 수 있는지 확인한다.
+func (p ConnectionPolicy) CheckCapacity(direction Direction, count int) error {
+
+	if direction == Inbound && p.MaxInbound > 0 && count >= p.MaxInbound {
+		return ErrTooManyInbound
+	}
+
+	if direction == Outbound && p.MaxOutbound > 0 && count >= p.MaxOutbound {
+		return ErrTooManyOutbound
+	}
+
+	return nil
+}
+
+func contains(list []string, target string) bool {
+
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc_gateway_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionPolicy_Permit(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			policy grpc_gateway.ConnectionPolicy
+			peerId string
+		}
+		err error
+	}{
+		"no list": {
+			input: struct {
+				policy grpc_gateway.ConnectionPolicy
+				peerId string
+			}{policy: grpc_gateway.ConnectionPolicy{}, peerId: "1"},
+			err: nil,
+		},
+		"in allow list": {
+			input: struct {
+				policy grpc_gateway.ConnectionPolicy
+				peerId string
+			}{policy: grpc_gateway.ConnectionPolicy{AllowList: []string{"1", "2"}}, peerId: "1"},
+			err: nil,
+		},
+		"not in allow list": {
+			input: struct {
+				policy grpc_gateway.ConnectionPolicy
+				peerId string
+			}{policy: grpc_gateway.ConnectionPolicy{AllowList: []string{"2"}}, peerId: "1"},
+			err: grpc_gateway.ErrPeerNotAllowed,
+		},
+		"in deny list": {
+			input: struct {
+				policy grpc_gateway.ConnectionPolicy
+				peerId string
+			}{policy: grpc_gateway.ConnectionPolicy{DenyList: []string{"1"}}, peerId: "1"},
+			err: grpc_gateway.ErrPeerDenied,
+		},
+		"in both allow and deny list": {
+			input: struct {
+				policy grpc_gateway.ConnectionPolicy
+				peerId string
+			}{policy: grpc_gateway.ConnectionPolicy{AllowList: []string{"1"}, DenyList: []string{"1"}}, peerId: "1"},
+			err: grpc_gateway.ErrPeerDenied,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := test.input.policy.Permit(test.input.peerId)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestConnectionPolicy_CheckCapacity(t *testing.T) {
+
+	// given
+	policy := grpc_gateway.ConnectionPolicy{MaxInbound: 2, MaxOutbound: 1}
+
+	// when, then
+	assert.NoError(t, policy.CheckCapacity(grpc_gateway.Inbound, 1))
+	assert.Equal(t, grpc_gateway.ErrTooManyInbound, policy.CheckCapacity(grpc_gateway.Inbound, 2))
+	assert.NoError(t, policy.CheckCapacity(grpc_gateway.Outbound, 0))
+	assert.Equal(t, grpc_gateway.ErrTooManyOutbound, policy.CheckCapacity(grpc_gateway.Outbound, 1))
+
+	// 0 means unlimited
+	assert.NoError(t, grpc_gateway.ConnectionPolicy{}.CheckCapacity(grpc_gateway.Inbound, 1000))
+}
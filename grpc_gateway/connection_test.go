@@ -14,29 +14,26 @@
  * limitations under the License.
  */
 
-package adapter
+package grpc_gateway_test
 
 import (
-	"github.com/it-chain/engine/common/event"
-	"github.com/it-chain/engine/consensus/pbft"
-)
-
-type Publish func(topic string, data interface{}) (err error)
+	"testing"
 
-type EventService struct {
-	publish Publish
-}
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/stretchr/testify/assert"
+)
 
-func NewEventService(publish Publish) EventService {
-	return EventService{
-		publish: publish,
-	}
-}
+func TestDeliveryError_Error(t *testing.T) {
 
-func (es EventService) ConfirmBlock(block pbft.ProposedBlock) error {
+	// given
+	err := grpc_gateway.DeliveryError{Failures: map[string]string{
+		"peer2": "send queue is full",
+		"peer1": "connection not found",
+	}}
 
-	// todo : block을 consensus finished event로 변경하여 날려야함
-	e := event.ConsensusFinished{}
+	// when
+	message := err.Error()
 
-	return es.publish("block.confirm", e)
+	// then
+	assert.Equal(t, "fail to deliver message to [peer1: connection not found, peer2: send queue is full]", message)
 }
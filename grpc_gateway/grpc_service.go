@@ -19,5 +19,6 @@ package grpc_gateway
 type GrpcService interface {
 	Dial(address string) (Connection, error)
 	CloseConnection(connID string)
-	SendMessages(message []byte, protocol string, connIDs ...string)
+	SendMessages(messageId string, message []byte, protocol string, ack bool, connIDs ...string) error
+	GetConnectionStates() []ConnectionStatus
 }
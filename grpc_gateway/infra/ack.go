@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"sync"
+
+	"github.com/it-chain/bifrost"
+)
+
+// ack을 요청한 message를 받은 node는 이 protocol로 message id를 알린다. data는 message id 이다.
+// 받은 message를 component의 topic으로 publish 한 후에 알리므로, 보낸 node는 상대 node가 message를 받았는지 알 수 있다.
+const DeliveryAckProtocol = "DeliveryAckProtocol"
+
+// 상대 node의 ack을 기다리는 message를 connection과 message id로 저장한다.
+type ackStore struct {
+	sync.Mutex
+	pending map[string]map[string]chan struct{}
+}
+
+func newAckStore() *ackStore {
+	return &ackStore{
+		pending: make(map[string]map[string]chan struct{}),
+	}
+}
+
+// ack을 받으면 닫히는 channel을 반환한다.
+func (s *ackStore) register(connID string, messageId string) <-chan struct{} {
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.pending[connID]; !ok {
+		s.pending[connID] = make(map[string]chan struct{})
+	}
+
+	acked := make(chan struct{})
+	s.pending[connID][messageId] = acked
+
+	return acked
+}
+
+// 기다리지 않는 ack은 무시한다.
+func (s *ackStore) resolve(connID string, messageId string) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	acked, ok := s.pending[connID][messageId]
+
+	if !ok {
+		return
+	}
+
+	close(acked)
+	s.remove(connID, messageId)
+}
+
+func (s *ackStore) cancel(connID string, messageId string) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.remove(connID, messageId)
+}
+
+func (s *ackStore) remove(connID string, messageId string) {
+
+	delete(s.pending[connID], messageId)
+
+	if len(s.pending[connID]) == 0 {
+		delete(s.pending, connID)
+	}
+}
+
+// 상대 node가 보낸 ack을 처리하고, 나머지 message는 next handler로 넘긴다.
+type deliveryAckHandler struct {
+	acks *ackStore
+	next bifrost.Handler
+}
+
+func (h deliveryAckHandler) ServeRequest(msg bifrost.Message) {
+
+	if msg.Envelope == nil || msg.Envelope.Protocol != DeliveryAckProtocol {
+		h.next.ServeRequest(msg)
+		return
+	}
+
+	h.acks.resolve(msg.Conn.GetID(), string(msg.Data))
+}
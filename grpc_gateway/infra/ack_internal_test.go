@@ -0,0 +1,191 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/it-chain/bifrost"
+	"github.com/it-chain/bifrost/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+type idConn struct {
+	bifrost.Connection
+	id string
+}
+
+func (c idConn) GetID() bifrost.ConnID {
+	return c.id
+}
+
+func TestWaitAcks(t *testing.T) {
+
+	errSend := errors.New("send error")
+
+	tests := map[string]struct {
+		input struct {
+			sent  error
+			acked bool
+		}
+		output error
+	}{
+		"acked by peer": {
+			input: struct {
+				sent  error
+				acked bool
+			}{sent: nil, acked: true},
+			output: nil,
+		},
+		"sent but not acked by peer": {
+			input: struct {
+				sent  error
+				acked bool
+			}{sent: nil, acked: false},
+			output: ErrAckTimeout,
+		},
+		"fail to send": {
+			input: struct {
+				sent  error
+				acked bool
+			}{sent: errSend, acked: false},
+			output: errSend,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		acks := newAckStore()
+		acked := acks.register("peer", "message id")
+
+		result := make(chan error, 1)
+		result <- test.input.sent
+
+		if test.input.acked {
+			deliveryAckHandler{acks: acks}.ServeRequest(bifrost.Message{
+				Envelope: &pb.Envelope{Protocol: DeliveryAckProtocol},
+				Data:     []byte("message id"),
+				Conn:     idConn{id: "peer"},
+			})
+		}
+
+		// when
+		failures := waitAcks(map[string]<-chan error{"peer": result}, map[string]<-chan struct{}{"peer": acked}, 100*time.Millisecond)
+
+		// then
+		assert.Equal(t, test.output, failures["peer"])
+	}
+}
+
+func TestDeliveryAckHandler_ServeRequest(t *testing.T) {
+
+	// given
+	acks := newAckStore()
+	acked := acks.register("peer", "message id")
+
+	passed := make([]bifrost.Message, 0)
+	handler := deliveryAckHandler{acks: acks, next: handlerFunc(func(msg bifrost.Message) {
+		passed = append(passed, msg)
+	})}
+
+	// when: ack from other connection is ignored
+	handler.ServeRequest(bifrost.Message{Envelope: &pb.Envelope{Protocol: DeliveryAckProtocol}, Data: []byte("message id"), Conn: idConn{id: "other"}})
+
+	// then
+	select {
+	case <-acked:
+		assert.Fail(t, "ack from other connection is accepted")
+	default:
+	}
+
+	// when
+	handler.ServeRequest(bifrost.Message{Envelope: &pb.Envelope{Protocol: "PingProtocol"}, Conn: idConn{id: "peer"}})
+	handler.ServeRequest(bifrost.Message{Envelope: &pb.Envelope{Protocol: DeliveryAckProtocol}, Data: []byte("message id"), Conn: idConn{id: "peer"}})
+
+	// then
+	<-acked
+	assert.Equal(t, 1, len(passed))
+	assert.Equal(t, 0, len(acks.pending))
+}
+
+func TestMessageHandler_ServeRequest_Acknowledge(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			ack        bool
+			publishErr error
+		}
+		output []string
+	}{
+		"ack is requested": {
+			input: struct {
+				ack        bool
+				publishErr error
+			}{ack: true},
+			output: []string{"peer:message id"},
+		},
+		"ack is not requested": {
+			input: struct {
+				ack        bool
+				publishErr error
+			}{ack: false},
+			output: []string{},
+		},
+		"fail to publish": {
+			input: struct {
+				ack        bool
+				publishErr error
+			}{ack: true, publishErr: errors.New("publish error")},
+			output: []string{},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		publishErr := test.input.publishErr
+		acknowledged := make([]string, 0)
+		handler := MessageHandler{
+			publish: func(exchange string, topic string, data interface{}) error {
+				return publishErr
+			},
+			acknowledge: func(connID string, messageId string) {
+				acknowledged = append(acknowledged, connID+":"+messageId)
+			},
+		}
+
+		frame, err := EncodeFrame("message id", NoCompression, []byte("hello"), test.input.ack)
+		assert.NoError(t, err)
+
+		// when
+		handler.ServeRequest(bifrost.Message{Envelope: &pb.Envelope{Protocol: "PingProtocol"}, Data: frame, Conn: idConn{id: "peer"}})
+
+		// then
+		assert.Equal(t, test.output, acknowledged)
+	}
+}
+
+type handlerFunc func(msg bifrost.Message)
+
+func (f handlerFunc) ServeRequest(msg bifrost.Message) {
+	f(msg)
+}
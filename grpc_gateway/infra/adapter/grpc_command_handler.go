@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/grpc_gateway"
+)
+
+type ConnectionApi interface {
+	CreateConnection(address string) (grpc_gateway.Connection, error)
+	CloseConnection(connectionID string) error
+	GetConnectionStates() []grpc_gateway.ConnectionStatus
+}
+
+type MessageApi interface {
+	DeliverMessage(messageId string, body []byte, protocol string, ack bool, ids ...string) error
+}
+
+// 다른 component가 요청한 connection 생성, 종료와 message 전송을 처리한다.
+type GrpcCommandHandler struct {
+	connectionApi ConnectionApi
+	messageApi    MessageApi
+}
+
+func NewGrpcCommandHandler(connectionApi ConnectionApi, messageApi MessageApi) *GrpcCommandHandler {
+	return &GrpcCommandHandler{
+		connectionApi: connectionApi,
+		messageApi:    messageApi,
+	}
+}
+
+func (g *GrpcCommandHandler) HandleConnectionCreate(command command.CreateConnection) (grpc_gateway.Connection, rpc.Error) {
+
+	connection, err := g.connectionApi.CreateConnection(command.Address)
+
+	if err != nil {
+		return grpc_gateway.Connection{}, rpc.Error{Message: err.Error()}
+	}
+
+	return connection, rpc.Error{}
+}
+
+func (g *GrpcCommandHandler) HandleConnectionClose(command command.CloseConnection) (struct{}, rpc.Error) {
+
+	if err := g.connectionApi.CloseConnection(command.ConnectionID); err != nil {
+		return struct{}{}, rpc.Error{Message: err.Error()}
+	}
+
+	return struct{}{}, rpc.Error{}
+}
+
+func (g *GrpcCommandHandler) HandleListConnectionStates(_ command.ListConnectionStates) ([]grpc_gateway.ConnectionStatus, rpc.Error) {
+
+	return g.connectionApi.GetConnectionStates(), rpc.Error{}
+}
+
+// rpc로 요청한 message와 "message.deliver" topic으로 publish 된 message를 함께 처리한다.
+// 보내지 못한 recipient는 rpc caller에게 error로 알린다.
+func (g *GrpcCommandHandler) HandleMessageDeliver(command command.DeliverGrpc) (struct{}, rpc.Error) {
+
+	if err := g.messageApi.DeliverMessage(command.MessageId, command.Body, command.Protocol, command.Ack, command.RecipientList...); err != nil {
+		return struct{}{}, rpc.Error{Message: err.Error()}
+	}
+
+	return struct{}{}, rpc.Error{}
+}
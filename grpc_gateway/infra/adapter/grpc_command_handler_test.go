@@ -0,0 +1,175 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/it-chain/engine/grpc_gateway/infra/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockConnectionApi struct {
+	CreateConnectionFunc    func(address string) (grpc_gateway.Connection, error)
+	CloseConnectionFunc     func(connectionID string) error
+	GetConnectionStatesFunc func() []grpc_gateway.ConnectionStatus
+}
+
+func (m MockConnectionApi) CreateConnection(address string) (grpc_gateway.Connection, error) {
+	return m.CreateConnectionFunc(address)
+}
+
+func (m MockConnectionApi) CloseConnection(connectionID string) error {
+	return m.CloseConnectionFunc(connectionID)
+}
+
+func (m MockConnectionApi) GetConnectionStates() []grpc_gateway.ConnectionStatus {
+	return m.GetConnectionStatesFunc()
+}
+
+type MockMessageApi struct {
+	DeliverMessageFunc func(messageId string, body []byte, protocol string, ack bool, ids ...string) error
+}
+
+func (m MockMessageApi) DeliverMessage(messageId string, body []byte, protocol string, ack bool, ids ...string) error {
+	return m.DeliverMessageFunc(messageId, body, protocol, ack, ids...)
+}
+
+func TestGrpcCommandHandler_HandleConnectionCreate(t *testing.T) {
+
+	tests := map[string]struct {
+		input  command.CreateConnection
+		output grpc_gateway.Connection
+		err    string
+	}{
+		"success": {
+			input:  command.CreateConnection{Address: "127.0.0.1:7777"},
+			output: grpc_gateway.Connection{ConnectionId: "peer1", Address: "127.0.0.1:7777"},
+			err:    "",
+		},
+		"dial fail": {
+			input:  command.CreateConnection{Address: "127.0.0.1:8888"},
+			output: grpc_gateway.Connection{},
+			err:    "dial fail",
+		},
+	}
+
+	// given
+	connectionApi := MockConnectionApi{}
+	connectionApi.CreateConnectionFunc = func(address string) (grpc_gateway.Connection, error) {
+		if address == "127.0.0.1:8888" {
+			return grpc_gateway.Connection{}, errors.New("dial fail")
+		}
+
+		return grpc_gateway.Connection{ConnectionId: "peer1", Address: address}, nil
+	}
+
+	handler := adapter.NewGrpcCommandHandler(connectionApi, MockMessageApi{})
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		connection, err := handler.HandleConnectionCreate(test.input)
+
+		// then
+		assert.Equal(t, test.output, connection)
+		assert.Equal(t, test.err, err.Message)
+	}
+}
+
+func TestGrpcCommandHandler_HandleConnectionClose(t *testing.T) {
+
+	// given
+	closed := ""
+
+	connectionApi := MockConnectionApi{}
+	connectionApi.CloseConnectionFunc = func(connectionID string) error {
+		closed = connectionID
+		return nil
+	}
+
+	handler := adapter.NewGrpcCommandHandler(connectionApi, MockMessageApi{})
+
+	// when
+	_, err := handler.HandleConnectionClose(command.CloseConnection{ConnectionID: "peer1"})
+
+	// then
+	assert.True(t, err.IsNil())
+	assert.Equal(t, "peer1", closed)
+}
+
+func TestGrpcCommandHandler_HandleMessageDeliver(t *testing.T) {
+
+	tests := map[string]struct {
+		input command.DeliverGrpc
+		err   string
+	}{
+		"success": {
+			input: command.DeliverGrpc{
+				MessageId:     "message1",
+				RecipientList: []string{"peer1", "peer2"},
+				Body:          []byte("body"),
+				Protocol:      "PingProtocol",
+				Ack:           true,
+			},
+			err: "",
+		},
+		"delivery fail": {
+			input: command.DeliverGrpc{
+				MessageId:     "message1",
+				RecipientList: []string{"peer1", "peer3"},
+				Body:          []byte("body"),
+				Protocol:      "PingProtocol",
+				Ack:           true,
+			},
+			err: "fail to deliver message to [peer3: send queue is full]",
+		},
+	}
+
+	// given
+	messageApi := MockMessageApi{}
+	messageApi.DeliverMessageFunc = func(messageId string, body []byte, protocol string, ack bool, ids ...string) error {
+		assert.Equal(t, "message1", messageId)
+		assert.Equal(t, []byte("body"), body)
+		assert.Equal(t, "PingProtocol", protocol)
+		assert.True(t, ack)
+
+		for _, id := range ids {
+			if id == "peer3" {
+				return grpc_gateway.DeliveryError{Failures: map[string]string{"peer3": "send queue is full"}}
+			}
+		}
+
+		return nil
+	}
+
+	handler := adapter.NewGrpcCommandHandler(MockConnectionApi{}, messageApi)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		_, err := handler.HandleMessageDeliver(test.input)
+
+		// then
+		assert.Equal(t, test.err, err.Message)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/it-chain/bifrost"
+)
+
+var ErrUnknownCodec = errors.New("unknown compression codec")
+var ErrMessageTooLarge = errors.New("message is too large")
+
+// frame의 body를 압축한 방법
+type Codec byte
+
+const (
+	NoCompression Codec = 0
+	Gzip          Codec = 1
+	Snappy        Codec = 2
+)
+
+// 이 node가 풀 수 있는 codec, connection이 생기면 상대 node에게 알린다.
+var supportedCodecs = []Codec{NoCompression, Gzip, Snappy}
+
+func CodecFromName(name string) (Codec, error) {
+
+	switch name {
+	case "", "none":
+		return NoCompression, nil
+	case "gzip":
+		return Gzip, nil
+	case "snappy":
+		return Snappy, nil
+	default:
+		return NoCompression, ErrUnknownCodec
+	}
+}
+
+// MaxMessageSize는 압축하기 전 body의 최대 크기이다. 0이면 제한하지 않는다.
+// Compression은 protocol 별 codec이며, 없는 protocol은 DefaultCompression을 사용한다.
+type MessageConfig struct {
+	MaxMessageSize     int
+	Compression        map[string]Codec
+	DefaultCompression Codec
+}
+
+// 설정 파일에서 읽은 key는 소문자로 바뀌므로 소문자 protocol 이름도 찾는다.
+func (c MessageConfig) codecOf(protocol string) Codec {
+
+	if codec, ok := c.Compression[protocol]; ok {
+		return codec
+	}
+
+	if codec, ok := c.Compression[strings.ToLower(protocol)]; ok {
+		return codec
+	}
+
+	return c.DefaultCompression
+}
+
+func compress(codec Codec, body []byte) ([]byte, error) {
+
+	switch codec {
+	case NoCompression:
+		return body, nil
+
+	case Gzip:
+		buf := &bytes.Buffer{}
+		writer := gzip.NewWriter(buf)
+
+		if _, err := writer.Write(body); err != nil {
+			return nil, err
+		}
+
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+
+	case Snappy:
+		return snappy.Encode(nil, body), nil
+
+	default:
+		return nil, ErrUnknownCodec
+	}
+}
+
+// 압축을 푼 body가 maxSize 보다 크면 ErrMessageTooLarge를 반환한다. maxSize가 0이면 제한하지 않는다.
+func decompress(codec Codec, data []byte, maxSize int) ([]byte, error) {
+
+	var body []byte
+
+	switch codec {
+	case NoCompression:
+		body = data
+
+	case Gzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer reader.Close()
+
+		// 작은 frame이 아주 큰 body로 풀리지 않도록 maxSize 까지만 읽는다.
+		var src io.Reader = reader
+		if maxSize > 0 {
+			src = io.LimitReader(reader, int64(maxSize)+1)
+		}
+
+		body, err = ioutil.ReadAll(src)
+
+		if err != nil {
+			return nil, err
+		}
+
+	case Snappy:
+		decodedLen, err := snappy.DecodedLen(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if maxSize > 0 && decodedLen > maxSize {
+			return nil, ErrMessageTooLarge
+		}
+
+		body, err = snappy.Decode(nil, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, ErrUnknownCodec
+	}
+
+	if maxSize > 0 && len(body) > maxSize {
+		return nil, ErrMessageTooLarge
+	}
+
+	return body, nil
+}
+
+// connection 마다 상대 node가 알린, 풀 수 있는 codec을 저장한다.
+// 상대 node가 알리기 전에는 압축하지 않고 보낸다.
+type peerCodecStore struct {
+	sync.RWMutex
+	codecs map[string]peerCodecEntry
+}
+
+type peerCodecEntry struct {
+	conn      bifrost.Connection
+	supported map[Codec]bool
+}
+
+func newPeerCodecStore() *peerCodecStore {
+	return &peerCodecStore{
+		codecs: make(map[string]peerCodecEntry),
+	}
+}
+
+func (s *peerCodecStore) set(conn bifrost.Connection, codecs []Codec) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	supported := make(map[Codec]bool)
+
+	for _, codec := range codecs {
+		supported[codec] = true
+	}
+
+	s.codecs[conn.GetID()] = peerCodecEntry{conn: conn, supported: supported}
+}
+
+// 같은 peer와 새로 연결된 connection의 codec을 지우지 않도록 conn이 알린 codec일 때만 지운다.
+func (s *peerCodecStore) compareAndDelete(conn bifrost.Connection) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	if entry, ok := s.codecs[conn.GetID()]; ok && entry.conn == conn {
+		delete(s.codecs, conn.GetID())
+	}
+}
+
+// 상대 node가 codec을 풀 수 없으면 압축하지 않는다.
+func (s *peerCodecStore) negotiate(connID string, codec Codec) Codec {
+
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.codecs[connID].supported[codec] {
+		return codec
+	}
+
+	return NoCompression
+}
+
+// connection이 생기면 이 protocol로 풀 수 있는 codec을 상대 node에게 알린다. data는 codec의 목록이다.
+const CodecNegotiationProtocol = "CodecNegotiationProtocol"
+
+func encodeCodecs(codecs []Codec) []byte {
+
+	data := make([]byte, 0, len(codecs))
+
+	for _, codec := range codecs {
+		data = append(data, byte(codec))
+	}
+
+	return data
+}
+
+// 상대 node가 알린 codec을 저장하고, 나머지 message는 next handler로 넘긴다.
+type codecNegotiationHandler struct {
+	peerCodecs *peerCodecStore
+	next       bifrost.Handler
+}
+
+func (h codecNegotiationHandler) ServeRequest(msg bifrost.Message) {
+
+	if msg.Envelope == nil || msg.Envelope.Protocol != CodecNegotiationProtocol {
+		h.next.ServeRequest(msg)
+		return
+	}
+
+	codecs := make([]Codec, 0, len(msg.Data))
+
+	for _, b := range msg.Data {
+		codecs = append(codecs, Codec(b))
+	}
+
+	h.peerCodecs.set(msg.Conn, codecs)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerCodecStore_CompareAndDelete(t *testing.T) {
+
+	tests := map[string]struct {
+		input  *idConn
+		output Codec
+	}{
+		"connection which sent codecs": {
+			input:  nil,
+			output: NoCompression,
+		},
+		"old connection of same peer": {
+			input:  &idConn{id: "peer"},
+			output: Gzip,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		// given
+		conn := &idConn{id: "peer"}
+		store := newPeerCodecStore()
+		store.set(conn, []Codec{Gzip})
+
+		deleting := test.input
+		if deleting == nil {
+			deleting = conn
+		}
+
+		// when
+		store.compareAndDelete(deleting)
+
+		// then
+		assert.Equal(t, test.output, store.negotiate("peer", Gzip))
+	}
+}
+
+// go test -race로 실행하여 이전 connection의 codec을 지우는 동안 새 connection이 알린 codec이 지워지지 않는지 확인한다.
+func TestPeerCodecStore_CompareAndDelete_Concurrent(t *testing.T) {
+
+	// given
+	store := newPeerCodecStore()
+	old := &idConn{id: "peer"}
+	renewed := &idConn{id: "peer"}
+	store.set(old, []Codec{Snappy})
+
+	wg := sync.WaitGroup{}
+
+	// when
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			store.compareAndDelete(old)
+		}()
+
+		go func() {
+			defer wg.Done()
+			store.set(renewed, []Codec{Gzip})
+			store.negotiate("peer", Gzip)
+		}()
+	}
+
+	wg.Wait()
+	store.compareAndDelete(old)
+
+	// then
+	assert.Equal(t, Gzip, store.negotiate("peer", Gzip))
+}
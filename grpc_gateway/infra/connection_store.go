@@ -0,0 +1,163 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"sync"
+	"time"
+
+	"github.com/it-chain/bifrost"
+	"github.com/it-chain/engine/grpc_gateway"
+)
+
+type ConnectionStore interface {
+	Exist(connID bifrost.ConnID) bool
+	Add(conn bifrost.Connection) error
+	Delete(connID bifrost.ConnID)
+	CompareAndDelete(conn bifrost.Connection) bool
+	Find(connID bifrost.ConnID) bifrost.Connection
+	FindAll() []bifrost.Connection
+	List() []ConnectionInfo
+}
+
+// 저장된 connection의 정보, Direction은 PeerConnection일 때만 채워진다.
+type ConnectionInfo struct {
+	ConnectionId string
+	Address      string
+	Direction    grpc_gateway.Direction
+	ConnectedAt  time.Time
+}
+
+type connectionEntry struct {
+	conn bifrost.Connection
+	info ConnectionInfo
+}
+
+// dial, accept, close가 서로 다른 goroutine에서 호출되므로 모든 접근은 lock을 잡는다.
+type MemConnectionStore struct {
+	mux     sync.RWMutex
+	connMap map[bifrost.ConnID]connectionEntry
+}
+
+func NewMemConnectionStore() *MemConnectionStore {
+	return &MemConnectionStore{
+		connMap: make(map[bifrost.ConnID]connectionEntry),
+	}
+}
+
+func (connStore *MemConnectionStore) Exist(connID bifrost.ConnID) bool {
+
+	connStore.mux.RLock()
+	defer connStore.mux.RUnlock()
+
+	_, ok := connStore.connMap[connID]
+
+	return ok
+}
+
+func (connStore *MemConnectionStore) Add(conn bifrost.Connection) error {
+
+	connStore.mux.Lock()
+	defer connStore.mux.Unlock()
+
+	connID := conn.GetID()
+
+	if _, ok := connStore.connMap[connID]; ok {
+		return ErrConnAlreadyExist
+	}
+
+	info := ConnectionInfo{
+		ConnectionId: connID,
+		Address:      conn.GetIP(),
+		ConnectedAt:  time.Now(),
+	}
+
+	if peerConn, ok := conn.(PeerConnection); ok {
+		info.Direction = peerConn.GetDirection()
+	}
+
+	connStore.connMap[connID] = connectionEntry{conn: conn, info: info}
+
+	return nil
+}
+
+func (connStore *MemConnectionStore) Delete(connID bifrost.ConnID) {
+
+	connStore.mux.Lock()
+	defer connStore.mux.Unlock()
+
+	delete(connStore.connMap, connID)
+}
+
+// 같은 peer와 새로 연결된 connection을 지우지 않도록 저장된 connection이 conn일 때만 지운다.
+func (connStore *MemConnectionStore) CompareAndDelete(conn bifrost.Connection) bool {
+
+	connStore.mux.Lock()
+	defer connStore.mux.Unlock()
+
+	entry, ok := connStore.connMap[conn.GetID()]
+
+	if !ok || entry.conn != conn {
+		return false
+	}
+
+	delete(connStore.connMap, conn.GetID())
+
+	return true
+}
+
+func (connStore *MemConnectionStore) Find(connID bifrost.ConnID) bifrost.Connection {
+
+	connStore.mux.RLock()
+	defer connStore.mux.RUnlock()
+
+	entry, ok := connStore.connMap[connID]
+
+	if !ok {
+		return nil
+	}
+
+	return entry.conn
+}
+
+func (connStore *MemConnectionStore) FindAll() []bifrost.Connection {
+
+	connStore.mux.RLock()
+	defer connStore.mux.RUnlock()
+
+	conns := make([]bifrost.Connection, 0, len(connStore.connMap))
+
+	for _, entry := range connStore.connMap {
+		conns = append(conns, entry.conn)
+	}
+
+	return conns
+}
+
+func (connStore *MemConnectionStore) List() []ConnectionInfo {
+
+	connStore.mux.RLock()
+	defer connStore.mux.RUnlock()
+
+	infos := make([]ConnectionInfo, 0, len(connStore.connMap))
+
+	for _, entry := range connStore.connMap {
+		infos = append(infos, entry.info)
+	}
+
+	return infos
+}
@@ -0,0 +1,344 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/it-chain/bifrost"
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemConnectionStore_Add(t *testing.T) {
+
+	//given
+	tests := map[string]struct {
+		input  bifrost.Connection
+		output bifrost.Connection
+		err    error
+	}{
+		"add success": {
+			input:  MockConn{ID: "123"},
+			output: MockConn{ID: "123"},
+			err:    nil,
+		},
+	}
+
+	connectionStore := infra.NewMemConnectionStore()
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		err := connectionStore.Add(test.input)
+
+		//then
+		assert.Equal(t, connectionStore.Find(test.input.GetID()), test.output)
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestMemConnectionStore_Find(t *testing.T) {
+
+	//given
+	tests := map[string]struct {
+		input  string
+		output bifrost.Connection
+		err    error
+	}{
+		"find success": {
+			input:  "123",
+			output: MockConn{ID: "123"},
+			err:    nil,
+		},
+		"find nil": {
+			input:  "124",
+			output: nil,
+			err:    nil,
+		},
+	}
+
+	connectionStore := infra.NewMemConnectionStore()
+	connectionStore.Add(MockConn{ID: "123"})
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		conn := connectionStore.Find(test.input)
+
+		//then
+		assert.Equal(t, conn, test.output)
+	}
+}
+
+func TestMemConnectionStore_Delete(t *testing.T) {
+
+	//given
+	tests := map[string]struct {
+		input  string
+		output bifrost.Connection
+		err    error
+	}{
+		"delete success": {
+			input:  "123",
+			output: nil,
+			err:    nil,
+		},
+		"delete fail": {
+			input:  "124",
+			output: nil,
+			err:    nil,
+		},
+	}
+
+	connectionStore := infra.NewMemConnectionStore()
+	connectionStore.Add(MockConn{ID: "123"})
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		connectionStore.Delete(test.input)
+
+		//then
+		assert.Equal(t, connectionStore.Find(test.input), test.output)
+	}
+}
+
+func TestMemConnectionStore_CompareAndDelete(t *testing.T) {
+
+	//given
+	stored := &MockConn{ID: "123"}
+
+	tests := map[string]struct {
+		input  bifrost.Connection
+		output bool
+	}{
+		"stored connection": {
+			input:  stored,
+			output: true,
+		},
+		"old connection of same peer": {
+			input:  &MockConn{ID: "123"},
+			output: false,
+		},
+		"not stored": {
+			input:  &MockConn{ID: "124"},
+			output: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		connectionStore := infra.NewMemConnectionStore()
+		connectionStore.Add(stored)
+
+		//when
+		deleted := connectionStore.CompareAndDelete(test.input)
+
+		//then
+		assert.Equal(t, test.output, deleted)
+		assert.Equal(t, !test.output, connectionStore.Exist("123"))
+	}
+}
+
+func TestMemConnectionStore_FindAll(t *testing.T) {
+
+	//given
+	connectionStore := infra.NewMemConnectionStore()
+	connectionStore.Add(MockConn{ID: "123"})
+	connectionStore.Add(infra.NewPeerConnection("456", grpc_gateway.Outbound, MockConn{ID: "random conn id"}))
+
+	//when
+	conns := connectionStore.FindAll()
+
+	//then
+	assert.Equal(t, 2, len(conns))
+	assert.Contains(t, conns, MockConn{ID: "123"})
+	assert.Contains(t, conns, infra.NewPeerConnection("456", grpc_gateway.Outbound, MockConn{ID: "random conn id"}))
+}
+
+func TestMemConnectionStore_List(t *testing.T) {
+
+	//given
+	connectionStore := infra.NewMemConnectionStore()
+	connectionStore.Add(MockConn{ID: "123"})
+	connectionStore.Add(infra.NewPeerConnection("456", grpc_gateway.Inbound, MockConn{ID: "random conn id"}))
+
+	//when
+	infos := connectionStore.List()
+
+	//then
+	assert.Equal(t, 2, len(infos))
+
+	for _, info := range infos {
+		assert.Equal(t, "1", info.Address)
+		assert.False(t, info.ConnectedAt.IsZero())
+
+		switch info.ConnectionId {
+		case "123":
+			assert.Equal(t, grpc_gateway.Direction(""), info.Direction)
+		case "456":
+			assert.Equal(t, grpc_gateway.Inbound, info.Direction)
+		default:
+			assert.Fail(t, "unexpected connection", info.ConnectionId)
+		}
+	}
+}
+
+// go test -race로 실행하여 dial, accept, close가 동시에 일어날 때 data race가 없는지 확인한다.
+func TestMemConnectionStore_Concurrent(t *testing.T) {
+
+	//given
+	connectionStore := infra.NewMemConnectionStore()
+	wg := sync.WaitGroup{}
+
+	//when
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("peer%d", i)
+		closing := i%2 == 0
+
+		wg.Add(4)
+
+		// dial
+		go func() {
+			defer wg.Done()
+			connectionStore.Add(infra.NewPeerConnection(id, grpc_gateway.Outbound, MockConn{ID: id}))
+		}()
+
+		// accept, 같은 peer와의 connection은 하나만 저장된다
+		go func() {
+			defer wg.Done()
+			connectionStore.Add(infra.NewPeerConnection(id, grpc_gateway.Inbound, MockConn{ID: id}))
+		}()
+
+		// 조회
+		go func() {
+			defer wg.Done()
+			connectionStore.Exist(id)
+			connectionStore.Find(id)
+			connectionStore.FindAll()
+			connectionStore.List()
+		}()
+
+		// 짝수 번째 peer는 close
+		go func() {
+			defer wg.Done()
+			if closing {
+				connectionStore.Delete(id)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	//then
+	for i := 1; i < 50; i += 2 {
+		assert.True(t, connectionStore.Exist(fmt.Sprintf("peer%d", i)))
+	}
+
+	assert.True(t, len(connectionStore.List()) >= 25)
+}
+
+func TestMemConnectionStore_Add_Concurrent(t *testing.T) {
+
+	//given
+	connectionStore := infra.NewMemConnectionStore()
+	wg := sync.WaitGroup{}
+
+	errs := make(chan error, 100)
+
+	//when: 같은 peer에 동시에 dial 하고 accept 한다
+	for i := 0; i < 100; i++ {
+		direction := grpc_gateway.Outbound
+		if i%2 == 0 {
+			direction = grpc_gateway.Inbound
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- connectionStore.Add(infra.NewPeerConnection("peer", direction, MockConn{ID: "peer"}))
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	//then
+	added := 0
+	for err := range errs {
+		if err == nil {
+			added++
+			continue
+		}
+		assert.Equal(t, infra.ErrConnAlreadyExist, err)
+	}
+
+	assert.Equal(t, 1, added)
+	assert.Len(t, connectionStore.FindAll(), 1)
+}
+
+// go test -race로 실행하여 끊긴 connection을 지우는 동안 같은 peer와 다시 연결되어도 새 connection이 지워지지 않는지 확인한다.
+func TestMemConnectionStore_CompareAndDelete_Concurrent(t *testing.T) {
+
+	//given
+	connectionStore := infra.NewMemConnectionStore()
+	wg := sync.WaitGroup{}
+
+	olds := make([]*MockConn, 50)
+	news := make([]*MockConn, 50)
+
+	for i := range olds {
+		id := fmt.Sprintf("peer%d", i)
+		olds[i] = &MockConn{ID: id}
+		news[i] = &MockConn{ID: id}
+		connectionStore.Add(olds[i])
+	}
+
+	//when: 이전 connection이 지워진 후 다시 연결되고, 이전 connection의 goroutine이 한번 더 지운다
+	for i := range olds {
+		old, renewed := olds[i], news[i]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connectionStore.CompareAndDelete(old)
+			connectionStore.Add(renewed)
+			connectionStore.CompareAndDelete(old)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connectionStore.CompareAndDelete(old)
+		}()
+	}
+
+	wg.Wait()
+
+	//then
+	for i, renewed := range news {
+		assert.Equal(t, renewed, connectionStore.Find(fmt.Sprintf("peer%d", i)))
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidFrame = errors.New("invalid frame")
+
+// codec byte의 최상위 bit가 켜져 있으면 받은 node가 DeliveryAckProtocol로 message id를 알려야 한다.
+const ackFlag byte = 0x80
+
+// bifrost로 보내는 data에 message id와 body의 codec을 함께 담는다. protocol은 bifrost envelope에 담긴다.
+// | len(message id) (uvarint) | message id | ack flag + codec (1 byte) | body (codec으로 압축) |
+func EncodeFrame(messageId string, codec Codec, body []byte, ack bool) ([]byte, error) {
+
+	compressed, err := compress(codec, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, uint64(len(messageId)))
+
+	frame := make([]byte, 0, n+len(messageId)+1+len(compressed))
+	frame = append(frame, header[:n]...)
+	frame = append(frame, messageId...)
+	flags := byte(codec)
+	if ack {
+		flags |= ackFlag
+	}
+
+	frame = append(frame, flags)
+	frame = append(frame, compressed...)
+
+	return frame, nil
+}
+
+// message id, body와 보낸 node가 ack을 요청했는지를 반환한다.
+// 압축을 푼 body가 maxSize 보다 크면 ErrMessageTooLarge를 반환한다. maxSize가 0이면 제한하지 않는다.
+func DecodeFrame(frame []byte, maxSize int) (string, []byte, bool, error) {
+
+	idLen, n := binary.Uvarint(frame)
+
+	if n <= 0 || idLen >= uint64(len(frame)-n) {
+		return "", nil, false, ErrInvalidFrame
+	}
+
+	messageId := string(frame[n : n+int(idLen)])
+	flags := frame[n+int(idLen)]
+	codec := Codec(flags &^ ackFlag)
+
+	body, err := decompress(codec, frame[n+int(idLen)+1:], maxSize)
+
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return messageId, body, flags&ackFlag != 0, nil
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeFrame(t *testing.T, messageId string, codec infra.Codec, body []byte) []byte {
+
+	frame, err := infra.EncodeFrame(messageId, codec, body, false)
+	assert.NoError(t, err)
+
+	return frame
+}
+
+func TestEncodeFrame(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			messageId string
+			codec     infra.Codec
+			body      []byte
+		}
+	}{
+		"message with id": {
+			input: struct {
+				messageId string
+				codec     infra.Codec
+				body      []byte
+			}{messageId: "message id", codec: infra.NoCompression, body: []byte("hello world")},
+		},
+		"message without id": {
+			input: struct {
+				messageId string
+				codec     infra.Codec
+				body      []byte
+			}{messageId: "", codec: infra.NoCompression, body: []byte("hello world")},
+		},
+		"empty body": {
+			input: struct {
+				messageId string
+				codec     infra.Codec
+				body      []byte
+			}{messageId: "message id", codec: infra.NoCompression, body: []byte{}},
+		},
+		"gzip": {
+			input: struct {
+				messageId string
+				codec     infra.Codec
+				body      []byte
+			}{messageId: "message id", codec: infra.Gzip, body: []byte("hello world hello world")},
+		},
+		"snappy": {
+			input: struct {
+				messageId string
+				codec     infra.Codec
+				body      []byte
+			}{messageId: "message id", codec: infra.Snappy, body: []byte("hello world hello world")},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		messageId, body, _, err := infra.DecodeFrame(encodeFrame(t, test.input.messageId, test.input.codec, test.input.body), 0)
+
+		//then
+		assert.NoError(t, err)
+		assert.Equal(t, test.input.messageId, messageId)
+		assert.Equal(t, test.input.body, body)
+	}
+}
+
+func TestEncodeFrame_Ack(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			codec infra.Codec
+			ack   bool
+		}
+	}{
+		"ack is requested": {
+			input: struct {
+				codec infra.Codec
+				ack   bool
+			}{codec: infra.Snappy, ack: true},
+		},
+		"ack is not requested": {
+			input: struct {
+				codec infra.Codec
+				ack   bool
+			}{codec: infra.Snappy, ack: false},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		// given
+		frame, err := infra.EncodeFrame("message id", test.input.codec, []byte("hello world"), test.input.ack)
+		assert.NoError(t, err)
+
+		//when
+		messageId, body, ack, err := infra.DecodeFrame(frame, 0)
+
+		//then
+		assert.NoError(t, err)
+		assert.Equal(t, "message id", messageId)
+		assert.Equal(t, []byte("hello world"), body)
+		assert.Equal(t, test.input.ack, ack)
+	}
+}
+
+func TestDecodeFrame_InvalidFrame(t *testing.T) {
+
+	//when
+	_, _, _, err1 := infra.DecodeFrame([]byte{}, 0)
+	_, _, _, err2 := infra.DecodeFrame([]byte{10, 'a'}, 0)
+	_, _, _, err3 := infra.DecodeFrame([]byte{1, 'a'}, 0)
+	_, _, _, err4 := infra.DecodeFrame([]byte{1, 'a', 9, 'b'}, 0)
+	_, _, _, err5 := infra.DecodeFrame([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 'a'}, 0)
+
+	//then
+	assert.Equal(t, infra.ErrInvalidFrame, err1)
+	assert.Equal(t, infra.ErrInvalidFrame, err2)
+	assert.Equal(t, infra.ErrInvalidFrame, err3)
+	assert.Equal(t, infra.ErrUnknownCodec, err4)
+	assert.Equal(t, infra.ErrInvalidFrame, err5)
+}
+
+func TestDecodeFrame_TooLarge(t *testing.T) {
+
+	tests := map[string]struct {
+		input infra.Codec
+	}{
+		"no compression": {input: infra.NoCompression},
+		"gzip":           {input: infra.Gzip},
+		"snappy":         {input: infra.Snappy},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		// given
+		frame := encodeFrame(t, "message id", test.input, make([]byte, 101))
+
+		//when
+		_, _, _, err := infra.DecodeFrame(frame, 100)
+
+		//then
+		assert.Equal(t, infra.ErrMessageTooLarge, err)
+	}
+}
+
+func TestCodecFromName(t *testing.T) {
+
+	tests := map[string]struct {
+		input  string
+		output infra.Codec
+		err    error
+	}{
+		"none":    {input: "none", output: infra.NoCompression, err: nil},
+		"empty":   {input: "", output: infra.NoCompression, err: nil},
+		"gzip":    {input: "gzip", output: infra.Gzip, err: nil},
+		"snappy":  {input: "snappy", output: infra.Snappy, err: nil},
+		"unknown": {input: "lz4", output: infra.NoCompression, err: infra.ErrUnknownCodec},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		codec, err := infra.CodecFromName(test.input)
+
+		//then
+		assert.Equal(t, test.output, codec)
+		assert.Equal(t, test.err, err)
+	}
+}
@@ -17,20 +17,30 @@
 package infra
 
 import (
+	"crypto/tls"
 	"log"
+	"net"
 	"sync"
+	"time"
 
 	"errors"
 
 	"github.com/it-chain/bifrost"
 	"github.com/it-chain/bifrost/client"
+	"github.com/it-chain/bifrost/pb"
 	"github.com/it-chain/bifrost/server"
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/grpc_gateway"
 	"github.com/it-chain/heimdall/key"
+	"github.com/rs/xid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 var ErrConnAlreadyExist = errors.New("connection is already exist")
+var ErrEmptyPeerKey = errors.New("peer did not present public key")
+var ErrSelfConnection = errors.New("connection to itself")
 
 type Publish func(exchange string, topic string, data interface{}) (err error)
 
@@ -45,19 +55,50 @@ type GrpcHostService struct {
 	publish           Publish
 	priKey            key.PriKey
 	pubKey            key.PubKey
+	nodeId            string
 	connectionHandler ConnectionHandler
+	policy            grpc_gateway.ConnectionPolicy
+	admitMux          sync.Mutex
+	tlsConfig         *tls.Config
+	grpcServer        *grpc.Server
+	tlsIdentities     *tlsIdentityStore
+	reconnector       *Reconnector
+	sendQueueConfig   SendQueueConfig
+	sendQueues        map[bifrost.ConnID]*SendQueue
+	sendQueueMux      sync.RWMutex
+	messageConfig     MessageConfig
+	peerCodecs        *peerCodecStore
+	acks              *ackStore
 }
 
-func NewGrpcHostService(priKey key.PriKey, pubKey key.PubKey, publish Publish) *GrpcHostService {
+// tlsConfig가 nil이면 TLS 없이 연결한다.
+func NewGrpcHostService(priKey key.PriKey, pubKey key.PubKey, publish Publish, policy grpc_gateway.ConnectionPolicy, tlsConfig *tls.Config, sendQueueConfig SendQueueConfig) *GrpcHostService {
 
 	s := server.New(bifrost.KeyOpts{PriKey: priKey, PubKey: pubKey})
 
 	grpcHostService := &GrpcHostService{
-		connStore:     NewMemConnectionStore(),
-		bifrostServer: s,
-		publish:       publish,
-		priKey:        priKey,
-		pubKey:        pubKey,
+		connStore:       NewMemConnectionStore(),
+		bifrostServer:   s,
+		publish:         publish,
+		priKey:          priKey,
+		pubKey:          pubKey,
+		nodeId:          NodeIdFromPubKey(pubKey),
+		policy:          policy,
+		tlsConfig:       tlsConfig,
+		tlsIdentities:   newTLSIdentityStore(),
+		sendQueueConfig: sendQueueConfig,
+		sendQueues:      make(map[bifrost.ConnID]*SendQueue),
+		peerCodecs:      newPeerCodecStore(),
+		acks:            newAckStore(),
+	}
+
+	// bifrost server는 TLS 설정을 받지 않으므로 TLS를 사용하면 bifrost stream service를 직접 grpc server에 등록한다.
+	if tlsConfig != nil {
+		grpcHostService.grpcServer = grpc.NewServer(
+			grpc.Creds(credentials.NewTLS(tlsConfig)),
+			grpc.StreamInterceptor(grpcHostService.recordTLSIdentity),
+		)
+		pb.RegisterStreamServiceServer(grpcHostService.grpcServer, s)
 	}
 
 	s.OnConnection(grpcHostService.onConnection)
@@ -70,20 +111,50 @@ func (g *GrpcHostService) SetHandler(connectionHandler ConnectionHandler) {
 	g.connectionHandler = connectionHandler
 }
 
+// message의 최대 크기와 protocol 별 압축 방법을 설정한다. 설정하지 않으면 제한 없이 압축하지 않고 보낸다.
+func (g *GrpcHostService) SetMessageConfig(messageConfig MessageConfig) {
+	g.messageConfig = messageConfig
+}
+
+// reconnector를 설정하면 dial 한 connection이 끊길 때 다시 dial 한다.
+func (g *GrpcHostService) SetReconnector(reconnector *Reconnector) {
+	g.reconnector = reconnector
+}
+
 func (g *GrpcHostService) Dial(address string) (grpc_gateway.Connection, error) {
 
-	connection, err := client.Dial(g.buildDialOption(address))
+	// outbound connection이 가득 차 있으면 dial 하지 않는다.
+	if err := g.policy.CheckCapacity(grpc_gateway.Outbound, g.countConnections(grpc_gateway.Outbound)); err != nil {
+		return grpc_gateway.Connection{}, err
+	}
+
+	tlsPeerId := ""
+	conn, err := client.Dial(g.buildDialOption(address, func(nodeId string) {
+		tlsPeerId = nodeId
+	}))
 
 	if err != nil {
 		return grpc_gateway.Connection{}, err
 	}
 
-	if g.connStore.Exist(connection.GetID()) {
+	connection, err := g.verifyPeer(conn, grpc_gateway.Outbound, tlsPeerId)
+
+	if err != nil {
+		conn.Close()
+		return grpc_gateway.Connection{}, err
+	}
+
+	if err := g.admit(connection); err != nil {
 		connection.Close()
-		return grpc_gateway.Connection{}, ErrConnAlreadyExist
+		return grpc_gateway.Connection{}, err
+	}
+
+	if g.reconnector != nil {
+		g.reconnector.Remember(address, connection.GetID())
 	}
 
-	g.connStore.Add(connection)
+	// dial 한 connection도 다른 node가 dial 한 connection과 같이 handler에 알린다.
+	g.notifyConnection(connection)
 
 	go g.startConnectionUntilClose(connection)
 
@@ -97,29 +168,176 @@ func toGatewayConnectionModel(connection bifrost.Connection) grpc_gateway.Connec
 	}
 }
 
-// connection이 형성되는 경우 실행하는 코드이다.
-func (g *GrpcHostService) onConnection(connection bifrost.Connection) {
+// handshake에서 교환한 public key로 peer를 확인하고, connection을 peer의 node id로 식별한다.
+// allow list, deny list에 따라 연결할 수 없는 peer이면 거절한다.
+// TLS로 확인한 peer의 인증서가 있어야 하는 경우, 인증서의 key가 handshake의 key와 다르면 거절한다.
+func (g *GrpcHostService) verifyPeer(connection bifrost.Connection, direction grpc_gateway.Direction, tlsPeerId string) (PeerConnection, error) {
+
+	peerId := NodeIdFromPubKey(connection.GetPeerKey())
+
+	if peerId == "" {
+		return PeerConnection{}, ErrEmptyPeerKey
+	}
+
+	if peerId == g.nodeId {
+		return PeerConnection{}, ErrSelfConnection
+	}
+
+	if g.requireTLSIdentity(direction) && tlsPeerId != peerId {
+		return PeerConnection{}, ErrTLSIdentityMismatch
+	}
+
+	if err := g.policy.Permit(peerId); err != nil {
+		return PeerConnection{}, err
+	}
+
+	return NewPeerConnection(peerId, direction, connection), nil
+}
+
+// 같은 peer와의 connection이 없고 방향별 최대 connection 수를 넘지 않을 때만 connection을 저장한다.
+func (g *GrpcHostService) admit(connection PeerConnection) error {
+
+	g.admitMux.Lock()
+	defer g.admitMux.Unlock()
 
 	if g.connStore.Exist(connection.GetID()) {
+		return ErrConnAlreadyExist
+	}
+
+	if err := g.policy.CheckCapacity(connection.GetDirection(), g.countConnections(connection.GetDirection())); err != nil {
+		return err
+	}
+
+	if err := g.connStore.Add(connection); err != nil {
+		return err
+	}
+
+	queue := NewSendQueue(connection, g.sendQueueConfig)
+	queue.Start()
+
+	g.sendQueueMux.Lock()
+	g.sendQueues[connection.GetID()] = queue
+	g.sendQueueMux.Unlock()
+
+	return nil
+}
+
+func (g *GrpcHostService) findSendQueue(connID bifrost.ConnID) *SendQueue {
+
+	g.sendQueueMux.RLock()
+	defer g.sendQueueMux.RUnlock()
+
+	return g.sendQueues[connID]
+}
+
+// 같은 peer와 새로 연결된 connection의 queue를 지우지 않도록 queue가 같을 때만 지운다.
+func (g *GrpcHostService) removeSendQueue(connID bifrost.ConnID, queue *SendQueue) {
+
+	if queue == nil {
+		return
+	}
+
+	queue.Stop()
+
+	g.sendQueueMux.Lock()
+	defer g.sendQueueMux.Unlock()
+
+	if g.sendQueues[connID] == queue {
+		delete(g.sendQueues, connID)
+	}
+}
+
+func (g *GrpcHostService) countConnections(direction grpc_gateway.Direction) int {
+
+	count := 0
+
+	for _, info := range g.connStore.List() {
+		if info.Direction == direction {
+			count++
+		}
+	}
+
+	return count
+}
+
+// connection이 형성되는 경우 실행하는 코드이다.
+func (g *GrpcHostService) onConnection(conn bifrost.Connection) {
+
+	tlsPeerId, _ := g.tlsIdentities.find(conn.GetIP())
+	connection, err := g.verifyPeer(conn, grpc_gateway.Inbound, tlsPeerId)
+
+	if err != nil {
+		log.Printf("reject connection from [%s]: %s", conn.GetIP(), err.Error())
+		conn.Close()
+		return
+	}
+
+	if err := g.admit(connection); err != nil {
+		log.Printf("reject connection from [%s]: %s", conn.GetIP(), err.Error())
 		connection.Close()
 		return
 	}
 
-	g.connStore.Add(connection)
-	g.connectionHandler.OnConnection(toGatewayConnectionModel(connection))
+	g.notifyConnection(connection)
 
 	g.startConnectionUntilClose(connection)
 }
 
+// connection이 끊길 때까지 message를 받고, 끊기면 connection을 지우고 handler에 알린다.
 func (g *GrpcHostService) startConnectionUntilClose(connection bifrost.Connection) {
 
-	connection.Handle(NewMessageHandler(g.publish))
+	queue := g.findSendQueue(connection.GetID())
+
+	connection.Handle(codecNegotiationHandler{
+		peerCodecs: g.peerCodecs,
+		next: deliveryAckHandler{
+			acks: g.acks,
+			next: MessageHandler{
+				publish:        g.publish,
+				maxMessageSize: g.messageConfig.MaxMessageSize,
+				acknowledge:    g.acknowledge,
+			},
+		},
+	})
+
+	// 상대 node가 압축하여 보낼 수 있도록 풀 수 있는 codec을 알린다.
+	if queue != nil {
+		if _, err := queue.Enqueue(encodeCodecs(supportedCodecs), CodecNegotiationProtocol); err != nil {
+			log.Printf("fail to send codecs to [%s]: %s", connection.GetID(), err.Error())
+		}
+	}
 
 	if err := connection.Start(); err != nil {
-		connection.Close()
-		g.connStore.Delete(connection.GetID())
-		g.connectionHandler.OnDisconnection(toGatewayConnectionModel(connection))
+		log.Printf("connection [%s] is closed: %s", connection.GetID(), err.Error())
+	}
+
+	connection.Close()
+	g.connStore.CompareAndDelete(connection)
+	g.removeSendQueue(connection.GetID(), queue)
+	g.peerCodecs.compareAndDelete(connection)
+	g.notifyDisconnection(connection)
+
+	if peerConn, ok := connection.(PeerConnection); ok && peerConn.GetDirection() == grpc_gateway.Outbound && g.reconnector != nil {
+		g.reconnector.Lost(connection.GetID(), time.Now())
+	}
+}
+
+func (g *GrpcHostService) notifyConnection(connection bifrost.Connection) {
+
+	if g.connectionHandler == nil {
+		return
+	}
+
+	g.connectionHandler.OnConnection(toGatewayConnectionModel(connection))
+}
+
+func (g *GrpcHostService) notifyDisconnection(connection bifrost.Connection) {
+
+	if g.connectionHandler == nil {
+		return
 	}
+
+	g.connectionHandler.OnDisconnection(toGatewayConnectionModel(connection))
 }
 
 func (g *GrpcHostService) CloseConnection(connID string) {
@@ -130,138 +348,368 @@ func (g *GrpcHostService) CloseConnection(connID string) {
 		return
 	}
 
+	// 직접 끊은 connection은 다시 연결하지 않는다.
+	if g.reconnector != nil {
+		g.reconnector.Forget(connID)
+	}
+
+	// connection이 저장되어 있는 동안에는 같은 peer의 새 connection이 저장되지 않으므로 지우기 전에 찾은 queue는 connection의 queue이다.
+	queue := g.findSendQueue(connID)
+
 	connection.Close()
-	g.connStore.Delete(connection.GetID())
+
+	if g.connStore.CompareAndDelete(connection) {
+		g.removeSendQueue(connID, queue)
+	}
 }
 
-func (g *GrpcHostService) SendMessages(message []byte, protocol string, connIDs ...string) {
+// 연결된 connection은 up, 다시 연결 중인 connection은 connecting 또는 backing off 상태이다.
+func (g *GrpcHostService) GetConnectionStates() []grpc_gateway.ConnectionStatus {
+
+	states := make([]grpc_gateway.ConnectionStatus, 0)
+	connected := make(map[string]bool)
+
+	for _, info := range g.connStore.List() {
+		connected[info.ConnectionId] = true
+		states = append(states, grpc_gateway.ConnectionStatus{
+			ConnectionId: info.ConnectionId,
+			Address:      info.Address,
+			State:        grpc_gateway.Up,
+			Direction:    info.Direction,
+			ConnectedAt:  info.ConnectedAt,
+		})
+	}
 
-	for _, connID := range connIDs {
-		connection := g.connStore.Find(connID)
+	if g.reconnector == nil {
+		return states
+	}
 
-		if connection != nil {
-			connection.Send(message, protocol, nil, nil)
+	for _, state := range g.reconnector.GetStates() {
+		if !connected[state.ConnectionId] {
+			states = append(states, state)
 		}
 	}
+
+	return states
 }
 
-func (g GrpcHostService) buildDialOption(address string) (string, client.ClientOpts, client.GrpcOpts) {
+// message id가 없으면 새로 만들어 보낸다.
+// message는 connection 별 send queue에 넣어 보내며, queue에 넣지 못한 connection은 DeliveryError로 알린다.
+// ack이면 AckTimeout 동안 모든 connection의 상대 node가 DeliveryAckProtocol로 message를 받았다고 알리기를 기다린다.
+// body는 protocol의 codec 중 상대 node가 풀 수 있는 codec으로 압축한다.
+func (g *GrpcHostService) SendMessages(messageId string, message []byte, protocol string, ack bool, connIDs ...string) error {
 
-	clientOpt := client.ClientOpts{
-		Ip:     address,
-		PriKey: g.priKey,
-		PubKey: g.pubKey,
+	if messageId == "" {
+		messageId = xid.New().String()
 	}
 
-	grpcOpt := client.GrpcOpts{
-		TlsEnabled: false,
-		Creds:      nil,
+	failures := make(map[string]string)
+	results := make(map[string]<-chan error)
+	acked := make(map[string]<-chan struct{})
+
+	if g.messageConfig.MaxMessageSize > 0 && len(message) > g.messageConfig.MaxMessageSize {
+		for _, connID := range connIDs {
+			failures[connID] = ErrMessageTooLarge.Error()
+		}
+
+		return grpc_gateway.DeliveryError{Failures: failures}
 	}
 
-	return address, clientOpt, grpcOpt
-}
+	// 같은 codec을 쓰는 connection에는 같은 frame을 보낸다.
+	frames := make(map[Codec][]byte)
 
-func (s *GrpcHostService) Listen(ip string) {
-	s.bifrostServer.Listen(ip)
-}
+	for _, connID := range connIDs {
+		queue := g.findSendQueue(connID)
 
-func (s *GrpcHostService) onError(err error) {
-	log.Fatalln(err.Error())
+		if queue == nil {
+			failures[connID] = ErrConnectionNotFound.Error()
+			continue
+		}
+
+		codec := g.peerCodecs.negotiate(connID, g.messageConfig.codecOf(protocol))
+		frame, ok := frames[codec]
+
+		if !ok {
+			var err error
+			frame, err = EncodeFrame(messageId, codec, message, ack)
+
+			if err != nil {
+				failures[connID] = err.Error()
+				continue
+			}
+
+			frames[codec] = frame
+		}
+
+		// 상대 node가 ack을 보내기 전에 기다리기 시작한다.
+		if ack {
+			acked[connID] = g.acks.register(connID, messageId)
+		}
+
+		result, err := queue.Enqueue(frame, protocol)
+
+		if err != nil {
+			g.acks.cancel(connID, messageId)
+			failures[connID] = err.Error()
+			continue
+		}
+
+		results[connID] = result
+	}
+
+	if ack {
+		for connID, err := range waitAcks(results, acked, g.sendQueueConfig.AckTimeout) {
+			failures[connID] = err.Error()
+		}
+
+		for connID := range acked {
+			g.acks.cancel(connID, messageId)
+		}
+	}
+
+	if len(failures) != 0 {
+		return grpc_gateway.DeliveryError{Failures: failures}
+	}
+
+	return nil
 }
 
-func (s *GrpcHostService) Stop() {
-	s.bifrostServer.Stop()
+// outbound connection은 항상 server 인증서를 확인하고, inbound connection은 mutual TLS일 때만 client 인증서를 확인한다.
+func (g *GrpcHostService) requireTLSIdentity(direction grpc_gateway.Direction) bool {
+
+	if g.tlsConfig == nil {
+		return false
+	}
+
+	if direction == grpc_gateway.Outbound {
+		return true
+	}
+
+	return g.tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert
 }
 
-type ConnectionStore interface {
-	Exist(connID bifrost.ConnID) bool
-	Add(conn bifrost.Connection) error
-	Delete(connID bifrost.ConnID)
-	Find(connID bifrost.ConnID) bifrost.Connection
+// inbound connection의 client 인증서로 확인한 node id를 connection이 끝날 때까지 remote address로 저장한다.
+// bifrost server는 inbound connection의 ip를 remote address로 설정한다.
+func (g *GrpcHostService) recordTLSIdentity(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	p, ok := peer.FromContext(stream.Context())
+
+	if !ok {
+		return handler(srv, stream)
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return handler(srv, stream)
+	}
+
+	nodeId, err := NodeIdFromCertificate(tlsInfo.State.PeerCertificates[0])
+
+	if err != nil {
+		return err
+	}
+
+	address := p.Addr.String()
+	g.tlsIdentities.add(address, nodeId)
+	defer g.tlsIdentities.delete(address)
+
+	return handler(srv, stream)
 }
 
-type MemConnectionStore struct {
-	sync.RWMutex
-	connMap map[bifrost.ConnID]bifrost.Connection
+// 보내지 못한 connection은 보낸 결과의 error로, timeout 동안 상대 node의 ack을 받지 못한 connection은 ErrAckTimeout으로 본다.
+func waitAcks(results map[string]<-chan error, acked map[string]<-chan struct{}, timeout time.Duration) map[string]error {
+
+	failures := make(map[string]error)
+
+	expired := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+
+	for connID, result := range results {
+		var err error
+
+		// timeout 전에 도착한 결과를 먼저 확인한다.
+		select {
+		case err = <-result:
+		default:
+			select {
+			case err = <-result:
+			case <-expired:
+				err = ErrAckTimeout
+			}
+		}
+
+		if err != nil {
+			failures[connID] = err
+			continue
+		}
+
+		select {
+		case <-acked[connID]:
+		default:
+			select {
+			case <-acked[connID]:
+			case <-expired:
+				failures[connID] = ErrAckTimeout
+			}
+		}
+	}
+
+	return failures
 }
 
-func NewMemConnectionStore() MemConnectionStore {
-	return MemConnectionStore{
-		connMap: make(map[bifrost.ConnID]bifrost.Connection),
+// 받은 message의 ack을 보낸 node의 send queue로 보낸다.
+func (g *GrpcHostService) acknowledge(connID string, messageId string) {
+
+	queue := g.findSendQueue(connID)
+
+	if queue == nil {
+		return
+	}
+
+	if _, err := queue.Enqueue([]byte(messageId), DeliveryAckProtocol); err != nil {
+		log.Printf("fail to send ack of [%s] to [%s]: %s", messageId, connID, err.Error())
 	}
 }
 
-func (connStore MemConnectionStore) Exist(connID bifrost.ConnID) bool {
+func (g *GrpcHostService) buildDialOption(address string, onTLSVerified func(nodeId string)) (string, client.ClientOpts, client.GrpcOpts) {
 
-	_, ok := connStore.connMap[connID]
+	clientOpt := client.ClientOpts{
+		Ip:     address,
+		PriKey: g.priKey,
+		PubKey: g.pubKey,
+	}
 
-	//exist
-	if ok {
-		return true
+	grpcOpt := client.GrpcOpts{
+		TlsEnabled: false,
+		Creds:      nil,
 	}
 
-	return false
+	if g.tlsConfig != nil {
+		grpcOpt.TlsEnabled = true
+		grpcOpt.Creds = credentials.NewTLS(clientTLSConfig(g.tlsConfig, onTLSVerified))
+	}
+
+	return address, clientOpt, grpcOpt
 }
 
-func (connStore MemConnectionStore) Add(conn bifrost.Connection) error {
+func (s *GrpcHostService) Listen(ip string) {
 
-	connStore.Lock()
-	defer connStore.Unlock()
+	if s.grpcServer == nil {
+		s.bifrostServer.Listen(ip)
+		return
+	}
 
-	connID := conn.GetID()
+	lis, err := net.Listen("tcp", ip)
 
-	if connStore.Exist(connID) {
-		return ErrConnAlreadyExist
+	if err != nil {
+		s.onError(err)
+		return
 	}
 
-	connStore.connMap[connID] = conn
+	if err := s.grpcServer.Serve(lis); err != nil {
+		s.onError(err)
+	}
+}
 
-	return nil
+func (s *GrpcHostService) onError(err error) {
+	log.Fatalln(err.Error())
 }
 
-func (connStore MemConnectionStore) Delete(connID bifrost.ConnID) {
+func (s *GrpcHostService) Stop() {
+
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+		return
+	}
+
+	s.bifrostServer.Stop()
+}
 
-	connStore.Lock()
-	defer connStore.Unlock()
+// bifrost connection을 peer의 node id로 식별하기 위한 wrapper 이다.
+type PeerConnection struct {
+	bifrost.Connection
+	peerId    string
+	direction grpc_gateway.Direction
+}
 
-	if connStore.Exist(connID) {
-		delete(connStore.connMap, connID)
+func NewPeerConnection(peerId string, direction grpc_gateway.Direction, connection bifrost.Connection) PeerConnection {
+	return PeerConnection{
+		Connection: connection,
+		peerId:     peerId,
+		direction:  direction,
 	}
 }
 
-func (connStore MemConnectionStore) Find(connID bifrost.ConnID) bifrost.Connection {
+func (c PeerConnection) GetID() bifrost.ConnID {
+	return c.peerId
+}
 
-	connStore.Lock()
-	conn, ok := connStore.connMap[connID]
+// 이 node가 dial 한 connection이면 Outbound, 상대 node가 dial 한 connection이면 Inbound 이다.
+func (c PeerConnection) GetDirection() grpc_gateway.Direction {
+	return c.direction
+}
 
-	connStore.Unlock()
+// 수신한 message의 connection도 peer의 node id로 식별되도록 handler를 감싼다.
+func (c PeerConnection) Handle(handler bifrost.Handler) {
+	c.Connection.Handle(peerMessageHandler{connection: c, handler: handler})
+}
 
-	//exist
-	if ok {
-		return conn
-	}
+type peerMessageHandler struct {
+	connection PeerConnection
+	handler    bifrost.Handler
+}
 
-	return nil
+func (h peerMessageHandler) ServeRequest(msg bifrost.Message) {
+	msg.Conn = h.connection
+	h.handler.ServeRequest(msg)
 }
 
 type MessageHandler struct {
-	publish Publish
+	publish        Publish
+	maxMessageSize int
+	acknowledge    func(connID string, messageId string) // nil이면 ack을 보내지 않는다.
 }
 
+// 받은 message를 protocol을 처리하는 component의 topic으로 publish 한다.
+// 보낸 node가 ack을 요청했으면 publish 한 후에 message id를 알린다.
 func (r MessageHandler) ServeRequest(msg bifrost.Message) {
 
-	err := r.publish("Command", "message.receive", command.ReceiveGrpc{
-		Body:         msg.Data,
+	messageId, body, ack, err := DecodeFrame(msg.Data, r.maxMessageSize)
+
+	if err != nil {
+		log.Printf("drop message from [%s]: %s", msg.Conn.GetID(), err.Error())
+		return
+	}
+
+	protocol := ""
+
+	if msg.Envelope != nil {
+		protocol = msg.Envelope.Protocol
+	}
+
+	err = r.publish("Command", command.ReceiveTopic(protocol), command.ReceiveGrpc{
+		MessageId:    messageId,
+		Body:         body,
 		ConnectionID: msg.Conn.GetID(),
+		Protocol:     protocol,
 	})
 
 	if err != nil {
 		log.Println(err.Error())
+		return
+	}
+
+	if ack && r.acknowledge != nil {
+		r.acknowledge(msg.Conn.GetID(), messageId)
 	}
 }
 
-func NewMessageHandler(publish Publish) MessageHandler {
+// maxMessageSize 보다 큰 message는 버린다. 0이면 제한하지 않는다.
+func NewMessageHandler(publish Publish, maxMessageSize int) MessageHandler {
 	return MessageHandler{
-		publish: publish,
+		publish:        publish,
+		maxMessageSize: maxMessageSize,
 	}
 }
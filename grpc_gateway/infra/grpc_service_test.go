@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/it-chain/bifrost"
+	"github.com/it-chain/bifrost/pb"
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/grpc_gateway"
 	"github.com/it-chain/engine/grpc_gateway/infra"
@@ -71,140 +72,122 @@ func TestMessageHandler_ServeRequest(t *testing.T) {
 	//given
 	tests := map[string]struct {
 		input  bifrost.Message
-		output command.ReceiveGrpc
-		err    error
+		output struct {
+			topic   string
+			command *command.ReceiveGrpc
+		}
 	}{
 		"success": {
 			input: bifrost.Message{
-				Data: []byte("hello world"),
+				Envelope: &pb.Envelope{Protocol: "PingProtocol"},
+				Data:     encodeFrame(t, "message id", infra.NoCompression, []byte("hello world")),
 				Conn: MockConn{
 					ID: "123",
 				},
 			},
-			output: command.ReceiveGrpc{
-				Body:         []byte("hello world"),
-				ConnectionID: "123",
+			output: struct {
+				topic   string
+				command *command.ReceiveGrpc
+			}{
+				topic: "message.receive.p2p.PingProtocol",
+				command: &command.ReceiveGrpc{
+					MessageId:    "message id",
+					Body:         []byte("hello world"),
+					ConnectionID: "123",
+					Protocol:     "PingProtocol",
+				},
 			},
-			err: nil,
 		},
-	}
-
-	var publish = func(exchange string, topic string, data interface{}) (err error) {
-
-		//then
-		assert.Equal(t, exchange, "Command")
-		assert.Equal(t, topic, "message.receive")
-		assert.Equal(t, data, command.ReceiveGrpc{
-			Body:         []byte("hello world"),
-			ConnectionID: "123",
-		})
-		return nil
-	}
-
-	messageHandler := infra.NewMessageHandler(publish)
-
-	for testName, test := range tests {
-		t.Logf("Running test case %s", testName)
-
-		//when
-		messageHandler.ServeRequest(test.input)
-	}
-}
-
-func TestMemConnectionStore_Add(t *testing.T) {
-
-	//given
-	tests := map[string]struct {
-		input  bifrost.Connection
-		output bifrost.Connection
-		err    error
-	}{
-		"add success": {
-			input:  MockConn{ID: "123"},
-			output: MockConn{ID: "123"},
-			err:    nil,
+		"message without envelope": {
+			input: bifrost.Message{
+				Data: encodeFrame(t, "message id", infra.NoCompression, []byte("hello world")),
+				Conn: MockConn{
+					ID: "123",
+				},
+			},
+			output: struct {
+				topic   string
+				command *command.ReceiveGrpc
+			}{
+				topic: "message.receive.unknown.",
+				command: &command.ReceiveGrpc{
+					MessageId:    "message id",
+					Body:         []byte("hello world"),
+					ConnectionID: "123",
+				},
+			},
 		},
-	}
-
-	connectionStore := infra.NewMemConnectionStore()
-
-	for testName, test := range tests {
-		t.Logf("Running test case %s", testName)
-
-		//when
-		err := connectionStore.Add(test.input)
-
-		//then
-		assert.Equal(t, connectionStore.Find(test.input.GetID()), test.output)
-		assert.Equal(t, err, test.err)
-	}
-}
-
-func TestMemConnectionStore_Find(t *testing.T) {
-
-	//given
-	tests := map[string]struct {
-		input  string
-		output bifrost.Connection
-		err    error
-	}{
-		"find success": {
-			input:  "123",
-			output: MockConn{ID: "123"},
-			err:    nil,
+		"compressed message": {
+			input: bifrost.Message{
+				Envelope: &pb.Envelope{Protocol: "PingProtocol"},
+				Data:     encodeFrame(t, "message id", infra.Snappy, []byte("hello world")),
+				Conn: MockConn{
+					ID: "123",
+				},
+			},
+			output: struct {
+				topic   string
+				command *command.ReceiveGrpc
+			}{
+				topic: "message.receive.p2p.PingProtocol",
+				command: &command.ReceiveGrpc{
+					MessageId:    "message id",
+					Body:         []byte("hello world"),
+					ConnectionID: "123",
+					Protocol:     "PingProtocol",
+				},
+			},
 		},
-		"find nil": {
-			input:  "124",
-			output: nil,
-			err:    nil,
+		"too large message": {
+			input: bifrost.Message{
+				Envelope: &pb.Envelope{Protocol: "PingProtocol"},
+				Data:     encodeFrame(t, "message id", infra.Gzip, make([]byte, 1025)),
+				Conn: MockConn{
+					ID: "123",
+				},
+			},
+			output: struct {
+				topic   string
+				command *command.ReceiveGrpc
+			}{topic: "", command: nil},
+		},
+		"invalid frame": {
+			input: bifrost.Message{
+				Envelope: &pb.Envelope{Protocol: "PingProtocol"},
+				Data:     []byte{},
+				Conn: MockConn{
+					ID: "123",
+				},
+			},
+			output: struct {
+				topic   string
+				command *command.ReceiveGrpc
+			}{topic: "", command: nil},
 		},
 	}
 
-	connectionStore := infra.NewMemConnectionStore()
-	connectionStore.Add(MockConn{ID: "123"})
-
 	for testName, test := range tests {
 		t.Logf("Running test case %s", testName)
 
-		//when
-		conn := connectionStore.Find(test.input)
+		var publishedTopic string
+		var published *command.ReceiveGrpc
 
-		//then
-		assert.Equal(t, conn, test.output)
-	}
-}
-
-func TestMemConnectionStore_Delete(t *testing.T) {
-
-	//given
-	tests := map[string]struct {
-		input  string
-		output bifrost.Connection
-		err    error
-	}{
-		"delete success": {
-			input:  "123",
-			output: nil,
-			err:    nil,
-		},
-		"delete fail": {
-			input:  "124",
-			output: nil,
-			err:    nil,
-		},
-	}
-
-	connectionStore := infra.NewMemConnectionStore()
-	connectionStore.Add(MockConn{ID: "123"})
+		var publish = func(exchange string, topic string, data interface{}) (err error) {
+			assert.Equal(t, exchange, "Command")
+			publishedTopic = topic
+			receiveGrpc := data.(command.ReceiveGrpc)
+			published = &receiveGrpc
+			return nil
+		}
 
-	for testName, test := range tests {
-		t.Logf("Running test case %s", testName)
+		messageHandler := infra.NewMessageHandler(publish, 1024)
 
 		//when
-		connectionStore.Delete(test.input)
+		messageHandler.ServeRequest(test.input)
 
 		//then
-		assert.Equal(t, connectionStore.Find(test.input), test.output)
+		assert.Equal(t, test.output.topic, publishedTopic)
+		assert.Equal(t, test.output.command, published)
 	}
 }
 
@@ -225,7 +208,12 @@ var setupGrpcHostService = func(t *testing.T, ip string, keyPath string, publish
 
 	pri, pub := infra.LoadKeyPair(keyPath, "ECDSA256")
 
-	hostService := infra.NewGrpcHostService(pri, pub, publish)
+	hostService := infra.NewGrpcHostService(pri, pub, publish, grpc_gateway.ConnectionPolicy{}, nil, infra.SendQueueConfig{
+		Size:         16,
+		Policy:       infra.Block,
+		BlockTimeout: time.Second,
+		AckTimeout:   time.Second,
+	})
 
 	go hostService.Listen(ip)
 
@@ -374,10 +362,12 @@ func TestGrpcHostService_SendMessages(t *testing.T) {
 	var publish = func(exchange string, topic string, data interface{}) (err error) {
 
 		assert.Equal(t, exchange, "Command")
-		assert.Equal(t, topic, "message.receive")
+		assert.Equal(t, topic, "message.receive.unknown.testProtocol")
 		assert.Equal(t, data, command.ReceiveGrpc{
+			MessageId:    "message id",
 			Body:         publishedData,
 			ConnectionID: connID,
+			Protocol:     "testProtocol",
 		})
 		return nil
 	}
@@ -411,7 +401,8 @@ func TestGrpcHostService_SendMessages(t *testing.T) {
 
 		publishedData = test.input.Message
 
-		clientHostService.SendMessages(test.input.Message, test.input.Protocol, conn.ConnectionId)
+		err = clientHostService.SendMessages("message id", test.input.Message, test.input.Protocol, true, conn.ConnectionId)
+		assert.Equal(t, test.err, err)
 	}
 }
 
@@ -473,3 +464,76 @@ func TestGrpcHostService_Close(t *testing.T) {
 		clientHostService.CloseConnection(conn.ConnectionId)
 	}
 }
+
+type MockPubKey struct {
+	ski []byte
+}
+
+func (k MockPubKey) SKI() []byte             { return k.ski }
+func (MockPubKey) Algorithm() key.KeyGenOpts { return key.ECDSA256 }
+func (MockPubKey) ToPEM() ([]byte, error)    { return nil, nil }
+func (MockPubKey) Type() key.KeyType         { return "" }
+
+func TestNodeIdFromPubKey(t *testing.T) {
+
+	//given
+	tests := map[string]struct {
+		input  key.PubKey
+		output bool
+	}{
+		"from pub key": {
+			input:  MockPubKey{ski: []byte("ski")},
+			output: true,
+		},
+		"nil pub key": {
+			input:  nil,
+			output: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("Running test case %s", testName)
+
+		//when
+		nodeId := infra.NodeIdFromPubKey(test.input)
+
+		//then
+		assert.Equal(t, test.output, nodeId != "")
+		assert.Equal(t, nodeId, infra.NodeIdFromPubKey(test.input))
+	}
+}
+
+type MockHandledConn struct {
+	MockConn
+	handler bifrost.Handler
+}
+
+func (m *MockHandledConn) Handle(handler bifrost.Handler) {
+	m.handler = handler
+}
+
+func TestPeerConnection_Handle(t *testing.T) {
+
+	//given
+	conn := &MockHandledConn{MockConn: MockConn{ID: "random conn id"}}
+	peerConnection := infra.NewPeerConnection("peer id", grpc_gateway.Inbound, conn)
+
+	var publish = func(exchange string, topic string, data interface{}) (err error) {
+
+		//then
+		assert.Equal(t, command.ReceiveGrpc{
+			MessageId:    "message id",
+			Body:         []byte("hello world"),
+			ConnectionID: "peer id",
+			Protocol:     "PingProtocol",
+		}, data)
+		return nil
+	}
+
+	//when
+	peerConnection.Handle(infra.NewMessageHandler(publish, 0))
+	conn.handler.ServeRequest(bifrost.Message{Envelope: &pb.Envelope{Protocol: "PingProtocol"}, Data: encodeFrame(t, "message id", infra.NoCompression, []byte("hello world")), Conn: conn})
+
+	//then
+	assert.Equal(t, "peer id", peerConnection.GetID())
+}
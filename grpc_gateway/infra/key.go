@@ -20,6 +20,7 @@ import (
 	"log"
 
 	"github.com/it-chain/heimdall/key"
+	"github.com/jbenet/go-base58"
 )
 
 func LoadKeyPair(keyPath string, keyType string) (key.PriKey, key.PubKey) {
@@ -62,3 +63,14 @@ func ConvertToKeyGenOpts(keyType string) key.KeyGenOpts {
 		return key.RSA1024
 	}
 }
+
+// node의 식별자는 heimdall public key의 SKI를 base58로 인코딩한 값이다.
+// PeerId, transaction의 PeerID, block creator, consensus의 SenderID가 모두 이 값을 사용한다.
+func NodeIdFromPubKey(pubKey key.PubKey) string {
+
+	if pubKey == nil {
+		return ""
+	}
+
+	return base58.Encode(pubKey.SKI())
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/grpc_gateway"
+)
+
+type ReconnectConfig struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64 // backoff에 임의로 더할 최대 비율 (0.2면 backoff의 0 ~ 20%)
+}
+
+type reconnectTarget struct {
+	connectionId string
+	address      string
+	state        grpc_gateway.ConnectionState
+	attempts     int
+	delay        time.Duration
+	nextAttempt  time.Time
+}
+
+// dial 했던 address를 기억하고, connection이 끊기면 jitter를 더한 exponential backoff로 다시 dial 한다.
+// CloseConnection으로 끊은 connection은 잊는다.
+type Reconnector struct {
+	mux     sync.Mutex
+	dial    func(address string) (grpc_gateway.Connection, error)
+	publish Publish
+	config  ReconnectConfig
+	targets map[string]*reconnectTarget
+	random  func() float64
+	quit    chan struct{}
+}
+
+func NewReconnector(dial func(address string) (grpc_gateway.Connection, error), publish Publish, config ReconnectConfig) *Reconnector {
+	return &Reconnector{
+		mux:     sync.Mutex{},
+		dial:    dial,
+		publish: publish,
+		config:  config,
+		targets: make(map[string]*reconnectTarget),
+		random:  rand.Float64,
+		quit:    make(chan struct{}),
+	}
+}
+
+// dial에 성공한 address를 기억한다.
+func (r *Reconnector) Remember(address string, connectionId string) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.targets[address] = &reconnectTarget{
+		connectionId: connectionId,
+		address:      address,
+		state:        grpc_gateway.Up,
+		delay:        r.config.InitialBackoff,
+	}
+}
+
+func (r *Reconnector) Forget(connectionId string) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for address, target := range r.targets {
+		if target.connectionId == connectionId {
+			delete(r.targets, address)
+		}
+	}
+}
+
+// 기억하고 있는 connection이 끊기면 backoff 후에 다시 dial 한다.
+func (r *Reconnector) Lost(connectionId string, now time.Time) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, target := range r.targets {
+		if target.connectionId == connectionId && target.state == grpc_gateway.Up {
+			target.state = grpc_gateway.BackingOff
+			target.attempts = 0
+			target.delay = r.config.InitialBackoff
+			target.nextAttempt = now.Add(r.jitter(target.delay))
+		}
+	}
+}
+
+// backoff가 지난 address에 다시 dial 한다.
+func (r *Reconnector) Reconnect(now time.Time) {
+
+	for _, target := range r.dueTargets(now) {
+
+		r.publishEvent("connection.reconnecting", event.ConnectionReconnecting{
+			ConnectionID: target.connectionId,
+			Address:      target.address,
+			Attempt:      target.attempts,
+		})
+
+		_, err := r.dial(target.address)
+
+		// 상대 node가 먼저 다시 연결한 경우에도 연결된 것으로 본다.
+		if err == nil || err == ErrConnAlreadyExist {
+			r.markUp(target.address)
+			continue
+		}
+
+		log.Printf("fail to reconnect [%s]: %s", target.address, err.Error())
+
+		if delay, ok := r.backOff(target.address, now); ok {
+			r.publishEvent("connection.backoff", event.ConnectionBackingOff{
+				ConnectionID: target.connectionId,
+				Address:      target.address,
+				Attempt:      target.attempts,
+				Delay:        delay,
+			})
+		}
+	}
+}
+
+// dial 하는 동안 lock을 잡지 않도록 시도할 target을 복사하여 반환한다.
+func (r *Reconnector) dueTargets(now time.Time) []reconnectTarget {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	targets := make([]reconnectTarget, 0)
+
+	for _, target := range r.targets {
+		if target.state != grpc_gateway.BackingOff || now.Before(target.nextAttempt) {
+			continue
+		}
+
+		target.state = grpc_gateway.Connecting
+		target.attempts++
+		targets = append(targets, *target)
+	}
+
+	return targets
+}
+
+func (r *Reconnector) markUp(address string) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if target, ok := r.targets[address]; ok {
+		target.state = grpc_gateway.Up
+		target.attempts = 0
+		target.delay = r.config.InitialBackoff
+	}
+}
+
+func (r *Reconnector) backOff(address string, now time.Time) (time.Duration, bool) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	target, ok := r.targets[address]
+
+	// 시도하는 동안 Forget 된 경우
+	if !ok || target.state != grpc_gateway.Connecting {
+		return 0, false
+	}
+
+	target.delay = target.delay * 2
+
+	if target.delay > r.config.MaxBackoff {
+		target.delay = r.config.MaxBackoff
+	}
+
+	delay := r.jitter(target.delay)
+	target.state = grpc_gateway.BackingOff
+	target.nextAttempt = now.Add(delay)
+
+	return delay, true
+}
+
+// 여러 node가 같은 시각에 다시 dial 하지 않도록 backoff에 임의의 시간을 더한다.
+func (r *Reconnector) jitter(delay time.Duration) time.Duration {
+	return delay + time.Duration(float64(delay)*r.config.Jitter*r.random())
+}
+
+func (r *Reconnector) publishEvent(topic string, e interface{}) {
+
+	if err := r.publish("Event", topic, e); err != nil {
+		log.Printf("fail to publish [%s]: %s", topic, err.Error())
+	}
+}
+
+// 다시 연결 중인 connection의 상태
+func (r *Reconnector) GetStates() []grpc_gateway.ConnectionStatus {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	states := make([]grpc_gateway.ConnectionStatus, 0)
+
+	for _, target := range r.targets {
+		states = append(states, grpc_gateway.ConnectionStatus{
+			ConnectionId: target.connectionId,
+			Address:      target.address,
+			State:        target.state,
+			Attempts:     target.attempts,
+		})
+	}
+
+	return states
+}
+
+// interval 마다 Reconnect 한다. Stop을 호출할 때 까지 계속된다.
+func (r *Reconnector) Start(interval time.Duration) {
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.quit:
+				return
+			case now := <-ticker.C:
+				r.Reconnect(now)
+			}
+		}
+	}()
+}
+
+func (r *Reconnector) Stop() {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	select {
+	case <-r.quit:
+	default:
+		close(r.quit)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/grpc_gateway"
+	"github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnector_Reconnect(t *testing.T) {
+
+	// given
+	dialed := make([]string, 0)
+	dialErr := errors.New("connection refused")
+
+	var reconnector *infra.Reconnector
+
+	dial := func(address string) (grpc_gateway.Connection, error) {
+		dialed = append(dialed, address)
+
+		if dialErr != nil {
+			return grpc_gateway.Connection{}, dialErr
+		}
+
+		reconnector.Remember(address, "peer1")
+
+		return grpc_gateway.Connection{ConnectionId: "peer1", Address: address}, nil
+	}
+
+	events := make([]interface{}, 0)
+	publish := func(exchange string, topic string, data interface{}) error {
+		assert.Equal(t, "Event", exchange)
+		events = append(events, data)
+		return nil
+	}
+
+	reconnector = infra.NewReconnector(dial, publish, infra.ReconnectConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Jitter:         0,
+	})
+
+	now := time.Now()
+	reconnector.Remember("127.0.0.1:7777", "peer1")
+	assert.Equal(t, grpc_gateway.Up, reconnector.GetStates()[0].State)
+
+	// when
+	reconnector.Lost("peer1", now)
+
+	// then
+	assert.Equal(t, grpc_gateway.BackingOff, reconnector.GetStates()[0].State)
+
+	// when: backoff이 지나지 않았으면 dial 하지 않는다
+	reconnector.Reconnect(now.Add(500 * time.Millisecond))
+
+	// then
+	assert.Len(t, dialed, 0)
+
+	// when: dial에 실패하면 backoff를 두 배로 늘린다
+	reconnector.Reconnect(now.Add(time.Second))
+
+	// then
+	assert.Len(t, dialed, 1)
+	assert.Equal(t, []interface{}{
+		event.ConnectionReconnecting{ConnectionID: "peer1", Address: "127.0.0.1:7777", Attempt: 1},
+		event.ConnectionBackingOff{ConnectionID: "peer1", Address: "127.0.0.1:7777", Attempt: 1, Delay: 2 * time.Second},
+	}, events)
+	assert.Equal(t, grpc_gateway.BackingOff, reconnector.GetStates()[0].State)
+	assert.Equal(t, 1, reconnector.GetStates()[0].Attempts)
+
+	// when
+	reconnector.Reconnect(now.Add(2500 * time.Millisecond))
+
+	// then
+	assert.Len(t, dialed, 1)
+
+	// when: dial에 성공하면 up 상태가 된다
+	dialErr = nil
+	reconnector.Reconnect(now.Add(3 * time.Second))
+
+	// then
+	assert.Len(t, dialed, 2)
+	assert.Equal(t, grpc_gateway.Up, reconnector.GetStates()[0].State)
+	assert.Equal(t, 0, reconnector.GetStates()[0].Attempts)
+}
+
+func TestReconnector_Forget(t *testing.T) {
+
+	// given
+	dial := func(address string) (grpc_gateway.Connection, error) {
+		assert.Fail(t, "forgotten address should not be dialed")
+		return grpc_gateway.Connection{}, nil
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		return nil
+	}
+
+	reconnector := infra.NewReconnector(dial, publish, infra.ReconnectConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	})
+
+	now := time.Now()
+	reconnector.Remember("127.0.0.1:7777", "peer1")
+
+	// when
+	reconnector.Forget("peer1")
+	reconnector.Lost("peer1", now)
+	reconnector.Reconnect(now.Add(time.Minute))
+
+	// then
+	assert.Len(t, reconnector.GetStates(), 0)
+}
+
+func TestReconnector_Jitter(t *testing.T) {
+
+	// given
+	dial := func(address string) (grpc_gateway.Connection, error) {
+		return grpc_gateway.Connection{}, errors.New("connection refused")
+	}
+
+	var delay time.Duration
+	publish := func(exchange string, topic string, data interface{}) error {
+		if backingOff, ok := data.(event.ConnectionBackingOff); ok {
+			delay = backingOff.Delay
+		}
+		return nil
+	}
+
+	reconnector := infra.NewReconnector(dial, publish, infra.ReconnectConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Jitter:         0.5,
+	})
+
+	now := time.Now()
+	reconnector.Remember("127.0.0.1:7777", "peer1")
+	reconnector.Lost("peer1", now)
+
+	// when
+	reconnector.Reconnect(now.Add(2 * time.Second))
+
+	// then
+	assert.True(t, delay >= 2*time.Second)
+	assert.True(t, delay <= 3*time.Second)
+}
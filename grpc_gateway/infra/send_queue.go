@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/it-chain/bifrost"
+)
+
+var ErrQueueFull = errors.New("send queue is full")
+var ErrMessageDropped = errors.New("message is dropped from send queue")
+var ErrConnectionClosed = errors.New("connection is closed")
+var ErrConnectionNotFound = errors.New("connection not found")
+var ErrAckTimeout = errors.New("delivery ack timeout")
+
+// queue가 가득 찼을 때 새 message를 처리하는 방법
+type QueuePolicy string
+
+const (
+	Block      QueuePolicy = "block"       // BlockTimeout 동안 자리가 날 때까지 기다린다.
+	DropNewest QueuePolicy = "drop-newest" // 새 message를 버린다.
+	DropOldest QueuePolicy = "drop-oldest" // 가장 오래된 message를 버리고 새 message를 넣는다.
+)
+
+type SendQueueConfig struct {
+	Size         int
+	Policy       QueuePolicy
+	BlockTimeout time.Duration
+	AckTimeout   time.Duration
+}
+
+type sendRequest struct {
+	data     []byte
+	protocol string
+	result   chan error
+}
+
+// connection 마다 보낼 message를 쌓아두고 하나의 goroutine이 순서대로 보낸다.
+// 느린 peer에게 보내는 동안 다른 peer에게 보내는 caller가 막히지 않는다.
+type SendQueue struct {
+	mux        sync.Mutex
+	connection bifrost.Connection
+	config     SendQueueConfig
+	queue      chan *sendRequest
+	quit       chan struct{}
+}
+
+func NewSendQueue(connection bifrost.Connection, config SendQueueConfig) *SendQueue {
+
+	if config.Size < 1 {
+		config.Size = 1
+	}
+
+	return &SendQueue{
+		mux:        sync.Mutex{},
+		connection: connection,
+		config:     config,
+		queue:      make(chan *sendRequest, config.Size),
+		quit:       make(chan struct{}),
+	}
+}
+
+// message를 queue에 넣고, 보낸 결과를 받을 channel을 반환한다.
+func (q *SendQueue) Enqueue(data []byte, protocol string) (<-chan error, error) {
+
+	request := &sendRequest{
+		data:     data,
+		protocol: protocol,
+		result:   make(chan error, 1),
+	}
+
+	select {
+	case <-q.quit:
+		return nil, ErrConnectionClosed
+	default:
+	}
+
+	switch q.config.Policy {
+	case DropNewest:
+		select {
+		case q.queue <- request:
+			return request.result, nil
+		default:
+			return nil, ErrQueueFull
+		}
+
+	case DropOldest:
+		q.mux.Lock()
+		defer q.mux.Unlock()
+
+		for {
+			select {
+			case q.queue <- request:
+				return request.result, nil
+			default:
+			}
+
+			select {
+			case dropped := <-q.queue:
+				dropped.result <- ErrMessageDropped
+			default:
+			}
+		}
+
+	default:
+		timer := time.NewTimer(q.config.BlockTimeout)
+		defer timer.Stop()
+
+		select {
+		case q.queue <- request:
+			return request.result, nil
+		case <-timer.C:
+			return nil, ErrQueueFull
+		case <-q.quit:
+			return nil, ErrConnectionClosed
+		}
+	}
+}
+
+func (q *SendQueue) Start() {
+
+	go func() {
+		for {
+			select {
+			case <-q.quit:
+				q.drain()
+				return
+			case request := <-q.queue:
+				// 닫힌 connection으로는 보내지 않는다.
+				select {
+				case <-q.quit:
+					request.result <- ErrConnectionClosed
+					q.drain()
+					return
+				default:
+				}
+
+				q.send(request)
+			}
+		}
+	}()
+}
+
+func (q *SendQueue) send(request *sendRequest) {
+
+	q.connection.Send(request.data, request.protocol, func(_ interface{}) {
+		request.result <- nil
+	}, func(err error) {
+		request.result <- err
+	})
+}
+
+// 보내지 못한 message는 connection이 닫혔다고 알린다.
+func (q *SendQueue) drain() {
+
+	for {
+		select {
+		case request := <-q.queue:
+			request.result <- ErrConnectionClosed
+		default:
+			return
+		}
+	}
+}
+
+func (q *SendQueue) Stop() {
+
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	select {
+	case <-q.quit:
+	default:
+		close(q.quit)
+	}
+}
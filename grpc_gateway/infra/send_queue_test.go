@@ -0,0 +1,189 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/it-chain/bifrost"
+	"github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/it-chain/heimdall/key"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockSendConn struct {
+	SendFunc func(data []byte, protocol string, successCallBack func(interface{}), errCallBack func(error))
+}
+
+func (MockSendConn) Close()                         {}
+func (MockSendConn) GetID() bifrost.ConnID          { return "peer1" }
+func (MockSendConn) GetIP() string                  { return "127.0.0.1:7777" }
+func (MockSendConn) GetPeerKey() key.PubKey         { return nil }
+func (MockSendConn) Handle(handler bifrost.Handler) {}
+func (MockSendConn) Start() error                   { return nil }
+
+func (m MockSendConn) Send(data []byte, protocol string, successCallBack func(interface{}), errCallBack func(error)) {
+	m.SendFunc(data, protocol, successCallBack, errCallBack)
+}
+
+func TestSendQueue_Enqueue(t *testing.T) {
+
+	sendErr := errors.New("stream closed")
+
+	tests := map[string]struct {
+		input []byte
+		err   error
+	}{
+		"send success": {
+			input: []byte("ok"),
+			err:   nil,
+		},
+		"send fail": {
+			input: []byte("fail"),
+			err:   sendErr,
+		},
+	}
+
+	// given
+	conn := MockSendConn{}
+	conn.SendFunc = func(data []byte, protocol string, successCallBack func(interface{}), errCallBack func(error)) {
+		assert.Equal(t, "testProtocol", protocol)
+
+		if string(data) == "fail" {
+			errCallBack(sendErr)
+			return
+		}
+
+		successCallBack(nil)
+	}
+
+	queue := infra.NewSendQueue(conn, infra.SendQueueConfig{Size: 4, Policy: infra.Block, BlockTimeout: time.Second})
+	queue.Start()
+	defer queue.Stop()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		result, err := queue.Enqueue(test.input, "testProtocol")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.err, <-result)
+	}
+}
+
+// 첫 message를 보내는 동안 막히는 connection으로 size 1의 queue를 가득 채운다.
+func setupFullSendQueue(t *testing.T, policy infra.QueuePolicy) (*infra.SendQueue, <-chan error, <-chan error, chan struct{}) {
+
+	sending := make(chan struct{})
+	release := make(chan struct{})
+
+	conn := MockSendConn{}
+	conn.SendFunc = func(data []byte, protocol string, successCallBack func(interface{}), errCallBack func(error)) {
+		if string(data) == "first" {
+			close(sending)
+			<-release
+		}
+		successCallBack(nil)
+	}
+
+	queue := infra.NewSendQueue(conn, infra.SendQueueConfig{Size: 1, Policy: policy, BlockTimeout: 50 * time.Millisecond})
+	queue.Start()
+
+	first, err := queue.Enqueue([]byte("first"), "testProtocol")
+	assert.NoError(t, err)
+	<-sending
+
+	second, err := queue.Enqueue([]byte("second"), "testProtocol")
+	assert.NoError(t, err)
+
+	return queue, first, second, release
+}
+
+func TestSendQueue_Enqueue_DropNewest(t *testing.T) {
+
+	// given
+	queue, first, second, release := setupFullSendQueue(t, infra.DropNewest)
+	defer queue.Stop()
+
+	// when
+	_, err := queue.Enqueue([]byte("third"), "testProtocol")
+
+	// then
+	assert.Equal(t, infra.ErrQueueFull, err)
+
+	close(release)
+	assert.NoError(t, <-first)
+	assert.NoError(t, <-second)
+}
+
+func TestSendQueue_Enqueue_DropOldest(t *testing.T) {
+
+	// given
+	queue, first, second, release := setupFullSendQueue(t, infra.DropOldest)
+	defer queue.Stop()
+
+	// when
+	third, err := queue.Enqueue([]byte("third"), "testProtocol")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, infra.ErrMessageDropped, <-second)
+
+	close(release)
+	assert.NoError(t, <-first)
+	assert.NoError(t, <-third)
+}
+
+func TestSendQueue_Enqueue_Block(t *testing.T) {
+
+	// given
+	queue, first, second, release := setupFullSendQueue(t, infra.Block)
+	defer queue.Stop()
+
+	// when
+	start := time.Now()
+	_, err := queue.Enqueue([]byte("third"), "testProtocol")
+
+	// then
+	assert.Equal(t, infra.ErrQueueFull, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	close(release)
+	assert.NoError(t, <-first)
+	assert.NoError(t, <-second)
+}
+
+func TestSendQueue_Stop(t *testing.T) {
+
+	// given
+	queue, first, second, release := setupFullSendQueue(t, infra.Block)
+
+	// when
+	queue.Stop()
+	close(release)
+
+	// then
+	assert.NoError(t, <-first)
+	assert.Equal(t, infra.ErrConnectionClosed, <-second)
+
+	_, err := queue.Enqueue([]byte("third"), "testProtocol")
+	assert.Equal(t, infra.ErrConnectionClosed, err)
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"sync"
+
+	"github.com/jbenet/go-base58"
+)
+
+var ErrInvalidCaBundle = errors.New("no certificate found in ca bundle")
+var ErrNoPeerCertificate = errors.New("peer did not present tls certificate")
+var ErrNotECDSACertificate = errors.New("tls certificate key is not ecdsa")
+var ErrTLSIdentityMismatch = errors.New("tls certificate does not match peer node key")
+
+type TLSConfig struct {
+	Enabled  bool
+	CertPath string
+	KeyPath  string
+	CaPath   string
+	Mutual   bool
+}
+
+// node 인증서는 node의 heimdall key로 발급하고 CA bundle의 CA가 서명한다.
+// Mutual이면 inbound connection도 client 인증서를 요구한다. TLS를 사용하지 않으면 nil을 반환한다.
+func LoadTLSConfig(config TLSConfig) (*tls.Config, error) {
+
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.CertPath, config.KeyPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := ioutil.ReadFile(config.CaPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	caPool := x509.NewCertPool()
+
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, ErrInvalidCaBundle
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		ClientCAs:    caPool,
+		ClientAuth:   tls.NoClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if config.Mutual {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// dial 하는 address가 아니라 node key로 peer를 확인하므로 hostname 대신 CA 서명만 검증하고,
+// 검증한 인증서의 node id를 onVerified로 넘긴다.
+func clientTLSConfig(base *tls.Config, onVerified func(nodeId string)) *tls.Config {
+
+	config := base.Clone()
+	config.InsecureSkipVerify = true
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+
+		if len(rawCerts) == 0 {
+			return ErrNoPeerCertificate
+		}
+
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+
+			if err != nil {
+				return err
+			}
+
+			certs = append(certs, cert)
+		}
+
+		intermediates := x509.NewCertPool()
+
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         base.RootCAs,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		nodeId, err := NodeIdFromCertificate(certs[0])
+
+		if err != nil {
+			return err
+		}
+
+		onVerified(nodeId)
+
+		return nil
+	}
+
+	return config
+}
+
+// 인증서의 public key로 heimdall key와 같은 방법(SKI의 base58)으로 node id를 만든다.
+func NodeIdFromCertificate(cert *x509.Certificate) (string, error) {
+
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+
+	if !ok {
+		return "", ErrNotECDSACertificate
+	}
+
+	ski := sha256.Sum256(elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+
+	return base58.Encode(ski[:]), nil
+}
+
+// inbound connection의 tls 인증서로 확인한 node id를 remote address 별로 저장한다.
+type tlsIdentityStore struct {
+	sync.RWMutex
+	identities map[string]string
+}
+
+func newTLSIdentityStore() *tlsIdentityStore {
+	return &tlsIdentityStore{
+		identities: make(map[string]string),
+	}
+}
+
+func (s *tlsIdentityStore) add(address string, nodeId string) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.identities[address] = nodeId
+}
+
+func (s *tlsIdentityStore) delete(address string) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.identities, address)
+}
+
+func (s *tlsIdentityStore) find(address string) (string, bool) {
+
+	s.RLock()
+	defer s.RUnlock()
+
+	nodeId, ok := s.identities[address]
+
+	return nodeId, ok
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package infra_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/jbenet/go-base58"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+
+	priKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	if parent == nil {
+		parent = template
+		parentKey = priKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &priKey.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert, priKey
+}
+
+func writeTLSFiles(t *testing.T, dir string) infra.TLSConfig {
+
+	caCert, caKey := createCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+
+	nodeCert, nodeKey := createCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, caCert, caKey)
+
+	keyDER, err := x509.MarshalECPrivateKey(nodeKey)
+	assert.NoError(t, err)
+
+	config := infra.TLSConfig{
+		Enabled:  true,
+		CertPath: filepath.Join(dir, "node.crt"),
+		KeyPath:  filepath.Join(dir, "node.key"),
+		CaPath:   filepath.Join(dir, "ca.crt"),
+	}
+
+	assert.NoError(t, ioutil.WriteFile(config.CertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: nodeCert.Raw}), 0600))
+	assert.NoError(t, ioutil.WriteFile(config.KeyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	assert.NoError(t, ioutil.WriteFile(config.CaPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}), 0600))
+
+	return config
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+
+	// given
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := writeTLSFiles(t, dir)
+
+	invalidCaPath := filepath.Join(dir, "invalid.crt")
+	assert.NoError(t, ioutil.WriteFile(invalidCaPath, []byte("invalid"), 0600))
+
+	tests := map[string]struct {
+		input      infra.TLSConfig
+		clientAuth tls.ClientAuthType
+		isNil      bool
+		err        error
+	}{
+		"disabled": {
+			input: infra.TLSConfig{Enabled: false},
+			isNil: true,
+			err:   nil,
+		},
+		"server only": {
+			input:      config,
+			clientAuth: tls.NoClientCert,
+			err:        nil,
+		},
+		"mutual": {
+			input:      infra.TLSConfig{Enabled: true, CertPath: config.CertPath, KeyPath: config.KeyPath, CaPath: config.CaPath, Mutual: true},
+			clientAuth: tls.RequireAndVerifyClientCert,
+			err:        nil,
+		},
+		"invalid ca bundle": {
+			input: infra.TLSConfig{Enabled: true, CertPath: config.CertPath, KeyPath: config.KeyPath, CaPath: invalidCaPath},
+			isNil: true,
+			err:   infra.ErrInvalidCaBundle,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		tlsConfig, err := infra.LoadTLSConfig(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.isNil, tlsConfig == nil)
+
+		if tlsConfig != nil {
+			assert.Equal(t, test.clientAuth, tlsConfig.ClientAuth)
+			assert.Len(t, tlsConfig.Certificates, 1)
+		}
+	}
+}
+
+func TestNodeIdFromCertificate(t *testing.T) {
+
+	// given
+	cert, priKey := createCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, nil, nil)
+
+	ski := sha256.Sum256(elliptic.Marshal(priKey.Curve, priKey.X, priKey.Y))
+
+	// when
+	nodeId, err := infra.NodeIdFromCertificate(cert)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, base58.Encode(ski[:]), nodeId)
+}
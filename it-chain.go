@@ -27,23 +27,46 @@ import (
 
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/it-chain/engine/api_gateway"
+	"github.com/it-chain/engine/blockchain"
 	blockchainApi "github.com/it-chain/engine/blockchain/api"
 	blockchainAdapter "github.com/it-chain/engine/blockchain/infra/adapter"
 	blockchainMem "github.com/it-chain/engine/blockchain/infra/mem"
 	"github.com/it-chain/engine/cmd/ivm"
+	"github.com/it-chain/engine/cmd/membership"
+	"github.com/it-chain/engine/cmd/peer"
 	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/common/logger"
 	"github.com/it-chain/engine/common/rabbitmq/pubsub"
 	"github.com/it-chain/engine/common/rabbitmq/rpc"
 	"github.com/it-chain/engine/conf"
+	"github.com/it-chain/engine/consensus"
+	consensusAdapter "github.com/it-chain/engine/consensus/infra/adapter"
+	"github.com/it-chain/engine/consensus/pbft"
+	pbftAdapter "github.com/it-chain/engine/consensus/pbft/infra/adapter"
+	pbftLeveldb "github.com/it-chain/engine/consensus/pbft/infra/leveldb"
+	pbftMetric "github.com/it-chain/engine/consensus/pbft/infra/metric"
+	raftApi "github.com/it-chain/engine/consensus/raft/api"
+	raftAdapter "github.com/it-chain/engine/consensus/raft/infra/adapter"
+	raftLeveldb "github.com/it-chain/engine/consensus/raft/infra/leveldb"
+	"github.com/it-chain/engine/consensus/solo"
+	"github.com/it-chain/engine/grpc_gateway"
+	grpcGatewayApi "github.com/it-chain/engine/grpc_gateway/api"
+	grpcGatewayInfra "github.com/it-chain/engine/grpc_gateway/infra"
+	grpcGatewayAdapter "github.com/it-chain/engine/grpc_gateway/infra/adapter"
 	icodeApi "github.com/it-chain/engine/ivm/api"
 	icodeAdapter "github.com/it-chain/engine/ivm/infra/adapter"
 	icodeInfra "github.com/it-chain/engine/ivm/infra/git"
 	"github.com/it-chain/engine/ivm/infra/tesseract"
+	"github.com/it-chain/engine/p2p"
+	p2pApi "github.com/it-chain/engine/p2p/api"
+	p2pAdapter "github.com/it-chain/engine/p2p/infra/adapter"
+	p2pLeveldb "github.com/it-chain/engine/p2p/infra/leveldb"
 	txpoolApi "github.com/it-chain/engine/txpool/api"
 	txpoolAdapter "github.com/it-chain/engine/txpool/infra/adapter"
 	txpoolBatch "github.com/it-chain/engine/txpool/infra/batch"
 	txpoolMem "github.com/it-chain/engine/txpool/infra/mem"
+	"github.com/it-chain/heimdall/key"
 	"github.com/urfave/cli"
 )
 
@@ -59,11 +82,14 @@ func PrintLogo() {
 	`)
 }
 
+// handshake로 다른 node에게도 알린다.
+const version = "0.1.1"
+
 func main() {
 
 	app := cli.NewApp()
 	app.Name = "it-chain"
-	app.Version = "0.1.1"
+	app.Version = version
 	app.Compiled = time.Now()
 	app.Authors = []cli.Author{
 		cli.Author{
@@ -80,6 +106,8 @@ func main() {
 	}
 	app.Commands = []cli.Command{}
 	app.Commands = append(app.Commands, ivm.IcodeCmd())
+	app.Commands = append(app.Commands, peer.PeerCmd())
+	app.Commands = append(app.Commands, membership.MembershipCmd())
 	app.Action = func(c *cli.Context) error {
 		PrintLogo()
 		configName := c.String("config")
@@ -102,10 +130,31 @@ func run() error {
 
 	logger.EnableFileLogger(true, configuration.Engine.LogPath)
 
-	defer initApiGateway(configuration, errs)()
-	defer initTxPool(configuration, rpcServer, rpcClient)()
+	// node의 식별자는 heimdall public key로 부터 만들어 모든 component가 함께 사용한다.
+	priKey, pubKey := grpcGatewayInfra.LoadKeyPair(configuration.Engine.KeyPath, "ECDSA256")
+	nodeId := grpcGatewayInfra.NodeIdFromPubKey(pubKey)
+
+	// p2p component가 채우는 peer table을 consensus도 함께 사용한다.
+	peerRepository := p2pLeveldb.NewPeerRepository(configuration.Peer.DBPath)
+	defer peerRepository.Close()
+
+	cons, tearDownConsensus := initConsensus(configuration, nodeId, peerRepository)
+	defer tearDownConsensus()
+
+	// raft는 p2p election에서 선출된 term을 쓰므로, 재시작한 node는 저장된 raft term 부터 election을 시작한다.
+	electionTerm := uint64(0)
+	if raftConsensus, ok := cons.(*raftApi.RaftApi); ok {
+		electionTerm = raftConsensus.Term()
+	}
+
+	defer initApiGateway(configuration, errs, cons)()
+	defer initTxPool(configuration, nodeId, rpcServer, rpcClient, cons)()
 	defer initICode(configuration, rpcServer)()
-	defer initBlockchain(configuration, rpcServer)()
+	blockRepo, tearDownBlockchain := initBlockchain(configuration, nodeId, rpcServer, rpcClient, cons)
+	defer tearDownBlockchain()
+	// p2p가 bootstrap node에 dial 하기 전에 grpc gateway가 connection.create 요청을 받을 수 있어야 한다.
+	defer initGrpcGateway(configuration, priKey, pubKey, rpcServer)()
+	defer initP2P(configuration, nodeId, priKey, rpcServer, rpcClient, peerRepository, blockRepo, electionTerm)()
 
 	go func() {
 		c := make(chan os.Signal, 1)
@@ -128,7 +177,7 @@ func initCommon() (rpc.Server, rpc.Client, *conf.Configuration, func()) {
 	}
 }
 
-func initApiGateway(config *conf.Configuration, errs chan error) func() {
+func initApiGateway(config *conf.Configuration, errs chan error, cons consensus.Consensus) func() {
 
 	ipAddress := config.ApiGateway.Address + ":" + config.ApiGateway.Port
 
@@ -168,6 +217,12 @@ func initApiGateway(config *conf.Configuration, errs chan error) func() {
 
 	mux.Handle("/blocks", api_gateway.BlockchainApiHandler(blockQueryApi, httpLogger))
 	mux.Handle("/icodes", api_gateway.ICodeApiHandler(icodeQueryApi, httpLogger))
+
+	// pbft metric은 expvar로 publish 되어 "/debug/vars" 에서 조회할 수 있다.
+	if pbftConsensus, ok := cons.(*pbftAdapter.Consensus); ok {
+		consensusQueryApi := api_gateway.NewConsensusQueryApi(pbftConsensus.StateRepository())
+		mux.Handle("/consensus/state", api_gateway.ConsensusApiHandler(consensusQueryApi, httpLogger))
+	}
 	http.Handle("/", mux)
 
 	go func() {
@@ -181,6 +236,122 @@ func initApiGateway(config *conf.Configuration, errs chan error) func() {
 	}
 }
 
+// grpc gateway는 다른 node와의 connection을 관리하고, connection이 생기거나 끊기면 Event exchange로 알린다.
+func initGrpcGateway(config *conf.Configuration, priKey key.PriKey, pubKey key.PubKey, server rpc.Server) func() {
+
+	ipAddress := config.GrpcGateway.Address + ":" + config.GrpcGateway.Port
+
+	logger.Infof(nil, "[Main] Grpc-gateway is staring on [%s]", ipAddress)
+
+	// 받은 message는 Command exchange로, reconnect event는 Event exchange로 publish 된다.
+	commandPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Command")
+	eventPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Event")
+	publish := func(exchange string, topic string, data interface{}) error {
+		if exchange == "Event" {
+			return eventPublisher.Publish(topic, data)
+		}
+		return commandPublisher.Publish(topic, data)
+	}
+
+	policy := grpc_gateway.ConnectionPolicy{
+		MaxInbound:  config.GrpcGateway.MaxInbound,
+		MaxOutbound: config.GrpcGateway.MaxOutbound,
+		AllowList:   config.GrpcGateway.AllowList,
+		DenyList:    config.GrpcGateway.DenyList,
+	}
+
+	// TLS를 사용하면 다른 노드의 인증서가 CA로 서명되었고 node key로 발급되었는지 확인한다.
+	tlsConfig, err := grpcGatewayInfra.LoadTLSConfig(grpcGatewayInfra.TLSConfig{
+		Enabled:  config.GrpcGateway.Tls.Enabled,
+		CertPath: config.GrpcGateway.Tls.CertPath,
+		KeyPath:  config.GrpcGateway.Tls.KeyPath,
+		CaPath:   config.GrpcGateway.Tls.CaPath,
+		Mutual:   config.GrpcGateway.Tls.Mutual,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// 느린 peer가 다른 peer로의 전송을 막지 않도록 connection 마다 send queue를 둔다.
+	sendQueueConfig := grpcGatewayInfra.SendQueueConfig{
+		Size:         config.GrpcGateway.SendQueueSize,
+		Policy:       grpcGatewayInfra.QueuePolicy(config.GrpcGateway.SendQueuePolicy),
+		BlockTimeout: time.Duration(config.GrpcGateway.SendBlockTimeoutMs) * time.Millisecond,
+		AckTimeout:   time.Duration(config.GrpcGateway.AckTimeoutMs) * time.Millisecond,
+	}
+
+	grpcHostService := grpcGatewayInfra.NewGrpcHostService(priKey, pubKey, publish, policy, tlsConfig, sendQueueConfig)
+	grpcHostService.SetMessageConfig(buildMessageConfig(config))
+
+	eventService := common.NewEventService(config.Engine.Amqp, "Event")
+	connectionApi := grpcGatewayApi.NewConnectionApi(grpcHostService, eventService)
+	messageApi := grpcGatewayApi.NewMessageApi(grpcHostService)
+	grpcHostService.SetHandler(connectionApi)
+
+	reconnectBackoff := time.Duration(config.GrpcGateway.ReconnectInitialBackoffMs) * time.Millisecond
+	reconnector := grpcGatewayInfra.NewReconnector(grpcHostService.Dial, publish, grpcGatewayInfra.ReconnectConfig{
+		InitialBackoff: reconnectBackoff,
+		MaxBackoff:     time.Duration(config.GrpcGateway.ReconnectMaxBackoffMs) * time.Millisecond,
+		Jitter:         config.GrpcGateway.ReconnectJitter,
+	})
+	grpcHostService.SetReconnector(reconnector)
+
+	grpcCommandHandler := grpcGatewayAdapter.NewGrpcCommandHandler(connectionApi, messageApi)
+	if err := server.Register("connection.create", grpcCommandHandler.HandleConnectionCreate); err != nil {
+		panic(err)
+	}
+	if err := server.Register("connection.close", grpcCommandHandler.HandleConnectionClose); err != nil {
+		panic(err)
+	}
+	if err := server.Register("message.deliver", grpcCommandHandler.HandleMessageDeliver); err != nil {
+		panic(err)
+	}
+	if err := server.Register("connection.states", grpcCommandHandler.HandleListConnectionStates); err != nil {
+		panic(err)
+	}
+
+	// consensus, txpool은 rpc 대신 "message.deliver" topic으로 publish 하여 message를 보낸다.
+	deliverSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := deliverSubscriber.SubscribeTopic("message.deliver", grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	go grpcHostService.Listen(ipAddress)
+	reconnector.Start(reconnectBackoff)
+
+	return func() {
+		reconnector.Stop()
+		grpcHostService.Stop()
+		deliverSubscriber.Close()
+		commandPublisher.Close()
+		eventPublisher.Close()
+	}
+}
+
+// 설정의 codec 이름을 확인하여 gateway의 message 설정을 만든다.
+func buildMessageConfig(config *conf.Configuration) grpcGatewayInfra.MessageConfig {
+
+	defaultCodec, err := grpcGatewayInfra.CodecFromName(config.GrpcGateway.DefaultCompression)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default compression [%s]", config.GrpcGateway.DefaultCompression))
+	}
+
+	compression := make(map[string]grpcGatewayInfra.Codec)
+	for protocol, name := range config.GrpcGateway.Compression {
+		codec, err := grpcGatewayInfra.CodecFromName(name)
+		if err != nil {
+			panic(fmt.Sprintf("invalid compression [%s] for protocol [%s]", name, protocol))
+		}
+		compression[protocol] = codec
+	}
+
+	return grpcGatewayInfra.MessageConfig{
+		MaxMessageSize:     config.GrpcGateway.MaxMessageSize,
+		Compression:        compression,
+		DefaultCompression: defaultCodec,
+	}
+}
+
 func initICode(config *conf.Configuration, server rpc.Server) func() {
 
 	logger.Infof(nil, "[Main] Ivm is staring")
@@ -214,15 +385,17 @@ func initICode(config *conf.Configuration, server rpc.Server) func() {
 	}
 }
 
-func initTxPool(config *conf.Configuration, server rpc.Server, client rpc.Client) func() {
+func initTxPool(config *conf.Configuration, nodeId string, server rpc.Server, client rpc.Client, cons consensus.Consensus) func() {
 
 	logger.Infof(nil, "[Main] Txpool is staring")
 
-	//todo get id from pubkey
-	tmpPeerID := "tmp peer 1"
 	transactionRepo := txpoolMem.NewTransactionRepository()
-	blockProposalService := txpoolAdapter.NewBlockProposalService(client, transactionRepo, config.Engine.Mode)
-	txApi := txpoolApi.NewTransactionApi(tmpPeerID, transactionRepo)
+
+	// leader가 아닌 node는 transaction을 gossip으로 leader에게 보낸다.
+	commandPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Command")
+	transferService := txpoolAdapter.NewTransferService(commandPublisher.Publish)
+	blockProposalService := txpoolAdapter.NewBlockProposalService(client, transactionRepo, cons, transferService)
+	txApi := txpoolApi.NewTransactionApi(nodeId, transactionRepo, txpoolAdapter.NewMembershipService(cons, consensusAdapter.NewECDSAApprovalVerifier()))
 	txCommandHandler := txpoolAdapter.NewTxCommandHandler(txApi)
 	txpoolBatch.GetTimeOutBatcherInstance().Run(blockProposalService.ProposeBlock, (time.Duration(config.Txpool.TimeoutMs) * time.Millisecond))
 
@@ -232,14 +405,34 @@ func initTxPool(config *conf.Configuration, server rpc.Server, client rpc.Client
 		panic(err)
 	}
 
-	return func() {}
+	grpcCommandHandler := txpoolAdapter.NewGrpcCommandHandler(txApi, cons)
+	subscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := subscriber.SubscribeTopic(command.ReceiveTopicOf(command.TxpoolComponent), &grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// leader에게 보낸 transaction은 block이 확정되면 지우고, leader가 바뀌면 다시 보낸다.
+	eventHandler := txpoolAdapter.NewEventHandler(blockProposalService)
+	eventSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Event")
+	if err := eventSubscriber.SubscribeTopic("block.*", eventHandler); err != nil {
+		panic(err)
+	}
+	if err := eventSubscriber.SubscribeTopic("leader.*", eventHandler); err != nil {
+		panic(err)
+	}
+
+	return func() {
+		subscriber.Close()
+		eventSubscriber.Close()
+		commandPublisher.Close()
+	}
 }
 
-func initBlockchain(config *conf.Configuration, server rpc.Server) func() {
+// p2p component가 handshake에 chain 정보를 쓸 수 있도록 block repository를 함께 반환한다.
+func initBlockchain(config *conf.Configuration, nodeId string, server rpc.Server, client rpc.Client, cons consensus.Consensus) (blockchain.BlockRepository, func()) {
 
 	logger.Infof(nil, "[Main] Blockchain is staring")
 
-	publisherId := "publisher.1"
 	blockRepo, err := blockchainMem.NewBlockRepository("./db")
 
 	if err != nil {
@@ -247,7 +440,7 @@ func initBlockchain(config *conf.Configuration, server rpc.Server) func() {
 	}
 
 	eventService := common.NewEventService(config.Engine.Amqp, "Event")
-	blockApi, err := blockchainApi.NewBlockApi(publisherId, blockRepo, eventService)
+	blockApi, err := blockchainApi.NewBlockApi(nodeId, blockRepo, eventService)
 	if err != nil {
 		panic(err)
 	}
@@ -257,10 +450,235 @@ func initBlockchain(config *conf.Configuration, server rpc.Server) func() {
 		panic(err)
 	}
 
-	blockProposeHandler := blockchainAdapter.NewBlockProposeCommandHandler(blockApi, config.Engine.Mode)
+	if err := blockchainAdapter.RestoreMembership(blockRepo, cons, consensusAdapter.NewECDSAApprovalVerifier()); err != nil {
+		panic(err)
+	}
+
+	// 합의가 끝난 block은 합의된 순서대로 deliver hook을 통해 commit 된다.
+	confirmedBlockHandler := blockchainAdapter.NewConfirmedBlockHandler(blockApi)
+	cons.OnDeliver(confirmedBlockHandler.HandleConfirmedBlock)
+
+	blockProposeHandler := blockchainAdapter.NewBlockProposeCommandHandler(blockApi, blockchainAdapter.NewConsensusService(cons))
 	server.Register("block.propose", blockProposeHandler.HandleProposeBlockCommand)
 
-	return func() {
+	return blockRepo, func() {
 		os.RemoveAll("./db")
 	}
 }
+
+// engine mode에 따라 blockchain, txpool component가 사용할 합의 방식을 만든다.
+func initConsensus(config *conf.Configuration, nodeID string, peerRepository p2p.PeerRepository) (consensus.Consensus, func()) {
+
+	logger.Infof(nil, "[Main] Consensus is staring - mode: [%s], node: [%s]", config.Engine.Mode, nodeID)
+
+	switch config.Engine.Mode {
+	case consensus.Solo:
+		return solo.NewConsensus(), func() {}
+
+	case consensus.Pbft:
+		return initPbft(config, nodeID, peerRepository)
+
+	case consensus.Raft:
+		return initRaft(config, nodeID, peerRepository)
+
+	default:
+		panic(consensus.ErrUnknownMode)
+	}
+}
+
+func initPbft(config *conf.Configuration, nodeID string, peerRepository p2p.PeerRepository) (consensus.Consensus, func()) {
+
+	commandPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Command")
+
+	stateStore := pbftLeveldb.NewStateStore(config.Consensus.StateDBPath)
+	stateRepository, err := pbft.NewPersistentStateRepository(stateStore)
+	if err != nil {
+		panic(err)
+	}
+
+	propagateService := pbftAdapter.NewPropagateService(commandPublisher.Publish)
+	// validator는 연결된 peer가 아니라 설정과 chain에 기록된 membership 으로부터 정해진다.
+	validatorSet := pbft.NewValidatorSet(toMemberIDs(config.Consensus.Validators))
+	parliamentService := pbftAdapter.NewParliamentService(api_gateway.NewPeerQueryApi(peerRepository), validatorSet)
+
+	pbftConsensus := pbftAdapter.NewConsensus(nodeID, propagateService, parliamentService, &stateRepository, validatorSet, consensusAdapter.NewECDSAApprovalVerifier(), pbftMetric.NewExpvarMetrics("pbft"))
+
+	grpcCommandHandler := pbftAdapter.NewGrpcCommandHandler(pbftConsensus.StateApi())
+	subscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := subscriber.SubscribeTopic(command.ReceiveTopicOf(command.ConsensusComponent), &grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	if err := pbftConsensus.StateApi().RecoverState(); err != nil {
+		logger.Error(nil, fmt.Sprintf("[Main] Fail to recover consensus state - err: [%s]", err.Error()))
+	}
+
+	return pbftConsensus, func() {
+		stateStore.Close()
+		commandPublisher.Close()
+	}
+}
+
+func toMemberIDs(ids []string) []pbft.MemberID {
+	memberIDs := make([]pbft.MemberID, 0)
+	for _, id := range ids {
+		memberIDs = append(memberIDs, pbft.MemberID(id))
+	}
+
+	return memberIDs
+}
+
+func initRaft(config *conf.Configuration, nodeID string, peerRepository p2p.PeerRepository) (consensus.Consensus, func()) {
+
+	commandPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Command")
+
+	peerQueryApi := api_gateway.NewPeerQueryApi(peerRepository)
+
+	messageService := raftAdapter.NewMessageService(commandPublisher.Publish)
+	leaderService := raftAdapter.NewLeaderService(&peerQueryApi)
+
+	logStore := raftLeveldb.NewLogStore(config.Consensus.StateDBPath)
+	raftConsensus, err := raftApi.NewPersistentRaftApi(nodeID, messageService, leaderService, consensusAdapter.NewECDSAApprovalVerifier(), logStore)
+	if err != nil {
+		panic(err)
+	}
+
+	// genesis block에 없는 validator는 설정으로 등록한다. 나머지는 chain에 기록된 membership으로 복구된다.
+	for _, validator := range config.Consensus.Validators {
+		raftConsensus.AddMember(validator)
+	}
+
+	grpcCommandHandler := raftAdapter.NewGrpcCommandHandler(raftConsensus)
+	subscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := subscriber.SubscribeTopic(command.ReceiveTopicOf(command.ConsensusComponent), &grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	return raftConsensus, func() {
+		logStore.Close()
+		commandPublisher.Close()
+	}
+}
+
+// p2p component는 bootstrap node에 연결하여 PLTable을 교환하고, connection event로 peer table을 유지한다.
+func initP2P(config *conf.Configuration, nodeId string, priKey key.PriKey, server rpc.Server, client rpc.Client, peerRepository *p2pLeveldb.PeerRepository, blockRepo blockchain.BlockRepository, electionTerm uint64) func() {
+
+	logger.Infof(nil, "[Main] P2P is staring - bootstrap: [%s]", config.Engine.BootstrapNodeAddress)
+
+	myAddress := config.GrpcGateway.Address + ":" + config.GrpcGateway.Port
+
+	eventService := common.NewEventService(config.Engine.Amqp, "Event")
+	peerQueryApi := api_gateway.NewPeerQueryApi(peerRepository)
+
+	communicationService := p2p.NewCommunicationService(client)
+	communicationApi := p2pApi.NewCommunicationApi(&peerQueryApi, communicationService, config.Peer.TargetPeerCount)
+	peerApi := p2pApi.NewPeerApi(peerRepository, eventService)
+
+	// leader는 node의 key로 서명하여 알리고, 다른 node의 leader는 서명을 검증한 후에 받아들인다.
+	leaderSigner, err := p2pAdapter.NewECDSALeaderSigner(priKey)
+	if err != nil {
+		panic(err)
+	}
+	leaderApi := p2pApi.NewLeaderApi(peerRepository, eventService, nodeId, leaderSigner, p2pAdapter.NewECDSALeaderVerifier())
+
+	election := p2p.NewElection(nodeId, 30, p2p.Ticking, 0)
+	election.UpdateTerm(electionTerm)
+	electionService := p2p.NewElectionService(&election, &peerQueryApi, client, &leaderApi, config.Peer.ElectionMinPeers)
+
+	// 규칙을 어겨 평판이 떨어진 peer는 연결을 끊고 일정 시간 동안 ban 한다.
+	reputationApi := p2pApi.NewReputationApi(p2p.NewReputation(), peerRepository, peerRepository, peerApi, client, p2pApi.ReputationConfig{
+		BanThreshold: config.Peer.BanThreshold,
+		BanDuration:  time.Duration(config.Peer.BanDurationSec) * time.Second,
+	})
+	reputationCommandHandler := p2pAdapter.NewReputationCommandHandler(reputationApi)
+	reputationSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := reputationSubscriber.SubscribeTopic("peer.report", &reputationCommandHandler); err != nil {
+		panic(err)
+	}
+	banCommandHandler := p2pAdapter.NewBanCommandHandler(reputationApi)
+	server.Register("peer.ban.list", banCommandHandler.HandleListBans)
+	server.Register("peer.ban.clear", banCommandHandler.HandleClearBan)
+
+	// 다른 peer에게 leader와 peer list를 물을 수 있다.
+	peerService := p2pAdapter.NewPeerService(client, &peerQueryApi, time.Duration(config.Peer.RequestTimeoutMs)*time.Millisecond)
+
+	grpcCommandHandler := p2pAdapter.NewGrpcCommandHandler(&leaderApi, &electionService, &communicationApi, p2p.PLTableService{}, peerService, reputationApi)
+	commandSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := commandSubscriber.SubscribeTopic(command.ReceiveTopicOf(command.P2PComponent), &grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// txpool, consensus 등은 "message.gossip" command로 네트워크 전체에 메세지를 전달할 수 있다. (ex. txpool의 SendLeaderTransactionsProtocol)
+	commandPublisher := pubsub.NewTopicPublisher(config.Engine.Amqp, "Command")
+	gossipCache := p2p.NewGossipCache(10000, 10*time.Minute)
+	// gossip은 여러 peer를 거쳐 전달되므로 처음 보낸 node가 서명하고, 받은 node는 서명으로 origin을 확인한다.
+	gossipSigner, err := p2pAdapter.NewECDSAGossipSigner(priKey)
+	if err != nil {
+		panic(err)
+	}
+	gossipService := p2p.NewGossipService(nodeId, gossipCache, &peerQueryApi, client, commandPublisher.Publish, gossipSigner, p2pAdapter.NewECDSAGossipVerifier(), config.Peer.GossipFanout, config.Peer.GossipTTL)
+	gossipCommandHandler := p2pAdapter.NewGossipCommandHandler(gossipService, reputationApi)
+	// subscriber 마다 handler의 parameter 타입이 한 번씩만 등록될 수 있으므로 topic 별로 subscriber를 만든다.
+	gossipReceiveSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := gossipReceiveSubscriber.SubscribeTopic(command.ReceiveTopicOf(command.P2PComponent), &gossipCommandHandler); err != nil {
+		panic(err)
+	}
+	gossipSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := gossipSubscriber.SubscribeTopic("message.gossip", &gossipCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// ping에 MaxMissedPings 번 연속으로 응답하지 않는 peer는 peer table에서 지운다.
+	livenessApi := p2pApi.NewLivenessApi(nodeId, p2p.NewLiveness(config.Peer.MaxMissedPings), peerRepository, peerApi, client)
+	livenessCommandHandler := p2pAdapter.NewLivenessCommandHandler(livenessApi, reputationApi)
+	livenessSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := livenessSubscriber.SubscribeTopic(command.ReceiveTopicOf(command.P2PComponent), &livenessCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// 연결된 node와 version, network id, genesis seal, height를 교환하고 다른 network의 node는 연결을 끊는다.
+	handshakeApi := p2pApi.NewHandshakeApi(version, config.Peer.NetworkId, p2pAdapter.NewChainQueryService(blockRepo), peerRepository, peerApi, client)
+	handshakeCommandHandler := p2pAdapter.NewHandshakeCommandHandler(handshakeApi, &communicationApi, reputationApi)
+	handshakeSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Command")
+	if err := handshakeSubscriber.SubscribeTopic(command.ReceiveTopicOf(command.P2PComponent), &handshakeCommandHandler); err != nil {
+		panic(err)
+	}
+
+	eventHandler := p2pAdapter.NewEventHandler(peerApi, reputationApi, handshakeApi)
+	eventSubscriber := pubsub.NewTopicSubscriber(config.Engine.Amqp, "Event")
+	if err := eventSubscriber.SubscribeTopic("connection.*", &eventHandler); err != nil {
+		panic(err)
+	}
+
+	if err := communicationApi.Bootstrap(config.Engine.BootstrapNodeAddress, myAddress); err != nil {
+		logger.Error(nil, fmt.Sprintf("[Main] Fail to dial bootstrap node - address: [%s], err: [%s]", config.Engine.BootstrapNodeAddress, err.Error()))
+	}
+
+	// 재시작한 node는 저장된 peer에 다시 연결하고, 연결된 peer가 TargetPeerCount 보다 적으면 저장된 peer로 채운다.
+	redialApi := p2pApi.NewRedialApi(peerRepository, &peerQueryApi, communicationService, p2pApi.RedialConfig{
+		InitialBackoff:     time.Duration(config.Peer.RedialInitialBackoffMs) * time.Millisecond,
+		MaxBackoff:         time.Duration(config.Peer.RedialMaxBackoffMs) * time.Millisecond,
+		UnreachableTimeout: time.Duration(config.Peer.UnreachableTimeoutSec) * time.Second,
+		TargetPeerCount:    config.Peer.TargetPeerCount,
+	})
+	redialApi.Start(time.Duration(config.Peer.RedialInitialBackoffMs) * time.Millisecond)
+	livenessApi.Start(time.Duration(config.Peer.PingIntervalMs) * time.Millisecond)
+
+	// bootstrap 이후에 시작하고, peer.electionminpeers 만큼 연결될 때 까지는 election을 시작하지 않는다.
+	if config.Peer.LeaderElection == "RAFT" {
+		electionService.ElectLeaderWithRaft()
+	}
+
+	return func() {
+		redialApi.Stop()
+		livenessApi.Stop()
+		electionService.Stop()
+		commandSubscriber.Close()
+		gossipReceiveSubscriber.Close()
+		gossipSubscriber.Close()
+		livenessSubscriber.Close()
+		reputationSubscriber.Close()
+		commandPublisher.Close()
+		eventSubscriber.Close()
+	}
+}
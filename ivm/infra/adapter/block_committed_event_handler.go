@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/consensus"
 	"github.com/it-chain/engine/ivm"
 	"github.com/it-chain/engine/ivm/api"
 )
@@ -49,6 +50,11 @@ func createRequestList(transactionList []event.Tx) []ivm.Request {
 	requestList := make([]ivm.Request, 0)
 
 	for _, transaction := range transactionList {
+		// membership transaction은 icode가 아니라 consensus가 처리한다.
+		if transaction.ICodeID == consensus.MembershipICodeID {
+			continue
+		}
+
 		requestList = append(requestList, ivm.Request{
 			Function: transaction.Function,
 			Args:     transaction.Args,
@@ -26,33 +26,61 @@ type CommunicationService interface {
 type CommunicationApi struct {
 	peerQueryService     p2p.PeerQueryService
 	communicationService CommunicationService // CommunicationService
+	targetPeerCount      int                  // 0이면 제한하지 않는다.
 }
 
-func NewCommunicationApi(peerQueryService p2p.PeerQueryService, communicationService CommunicationService) CommunicationApi {
+func NewCommunicationApi(peerQueryService p2p.PeerQueryService, communicationService CommunicationService, targetPeerCount int) CommunicationApi {
 	return CommunicationApi{
 		peerQueryService:     peerQueryService,
 		communicationService: communicationService,
+		targetPeerCount:      targetPeerCount,
 	}
 }
 
+// 받은 peer table을 자신의 peer table과 합쳐서 연결되지 않은 peer에게 dial 한다.
+// 같은 peer의 정보가 다르면 Version이 큰 쪽의 주소로 dial 한다.
+// 연결된 peer가 targetPeerCount에 도달하면 더 이상 dial 하지 않는다.
 func (ca *CommunicationApi) DialToUnConnectedNode(peerTable map[string]p2p.Peer) error {
 
-	//1. find unconnected peer
-	//2. dial to unconnected peer
-	for _, peer := range peerTable {
+	myPLTable, err := ca.peerQueryService.GetPLTable()
 
-		//err is nil if there is matching peer
-		peer, err := ca.peerQueryService.FindPeerById(peer.PeerId)
+	if err != nil {
+		return err
+	}
+
+	merged := p2p.MergePeerTable(myPLTable.PeerTable, peerTable)
+	connectedCount := len(myPLTable.PeerTable)
+
+	for id, peer := range merged {
+
+		if ca.targetPeerCount > 0 && connectedCount >= ca.targetPeerCount {
+			return nil
+		}
+
+		if _, connected := myPLTable.PeerTable[id]; connected || peer.IpAddress == "" {
+			continue
+		}
 
-		//dial if no peer matching peer id
-		if err != nil {
-			ca.communicationService.Dial(peer.IpAddress)
+		if err := ca.communicationService.Dial(peer.IpAddress); err == nil {
+			connectedCount++
 		}
 	}
 
 	return nil
 }
 
+// bootstrap node에 연결한다. 연결이 생성되면 connection created event를 받아 서로의 PLTable을 교환하고,
+// 받은 PLTable의 peer들에게 다시 연결하면서 peer table을 채운다.
+// 자기 자신이 bootstrap node이면 다른 node의 연결을 기다린다.
+func (ca *CommunicationApi) Bootstrap(bootstrapAddress string, myAddress string) error {
+
+	if bootstrapAddress == "" || bootstrapAddress == myAddress {
+		return nil
+	}
+
+	return ca.communicationService.Dial(bootstrapAddress)
+}
+
 //Deliver Peer leader table that consists of peerList and leader
 func (ca *CommunicationApi) DeliverPLTable(connectionId string) error {
 
@@ -28,44 +28,61 @@ import (
 func TestCommunicationApi_DialToUnConnectedNode(t *testing.T) {
 	tests := map[string]struct {
 		input struct {
-			peerTable map[string]p2p.Peer
+			peerTable       map[string]p2p.Peer
+			targetPeerCount int
 		}
-		err error
+		output []string
+		err    error
 	}{
 		"success": {
-			input: struct{ peerTable map[string]p2p.Peer }{peerTable: map[string]p2p.Peer{
-				"1": {
-					PeerId: p2p.PeerId{
-						Id: "1",
-					},
-				},
-			}},
-			err: nil,
+			input: struct {
+				peerTable       map[string]p2p.Peer
+				targetPeerCount int
+			}{peerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}, IpAddress: "1.new", Version: 2},
+				"2": {PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr", Version: 1},
+				"3": {PeerId: p2p.PeerId{Id: "3"}},
+			}, targetPeerCount: 0},
+			output: []string{"2.ipAddr"},
+			err:    nil,
+		},
+		"target peer count reached": {
+			input: struct {
+				peerTable       map[string]p2p.Peer
+				targetPeerCount int
+			}{peerTable: map[string]p2p.Peer{
+				"2": {PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr", Version: 1},
+			}, targetPeerCount: 1},
+			output: []string{},
+			err:    nil,
 		},
 	}
 
 	mockPeerQueryService := &mock.MockPeerQueryService{}
-	mockPeerQueryService.FindPeerByIdFunc = func(peerId p2p.PeerId) (p2p.Peer, error) {
-
-		peerTable := mock.MakeFakePeerTable()
-
-		for _, peer := range peerTable {
-			if peer.PeerId == peerId {
-				return peer, nil
-			}
-		}
-
-		return p2p.Peer{}, nil
+	mockPeerQueryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}, IpAddress: "1.ipAddr", Version: 1},
+			},
+		}, nil
 	}
 
 	for testName, test := range tests {
 
 		t.Logf("running test case %s", testName)
 
-		communicationApi := api.NewCommunicationApi(mockPeerQueryService, &mock.MockCommunicationService{})
+		dialed := make([]string, 0)
+		communicationService := &mock.MockCommunicationService{}
+		communicationService.DialFunc = func(ipAddress string) error {
+			dialed = append(dialed, ipAddress)
+			return nil
+		}
+
+		communicationApi := api.NewCommunicationApi(mockPeerQueryService, communicationService, test.input.targetPeerCount)
 
+		// connected peer and peer without address are not dialed
 		assert.Equal(t, communicationApi.DialToUnConnectedNode(test.input.peerTable), test.err)
-
+		assert.Equal(t, dialed, test.output)
 	}
 }
 
@@ -141,7 +158,63 @@ func SetupCommunicationApi() api.CommunicationApi {
 		return nil
 	}
 
-	communicationApi := api.NewCommunicationApi(peerQueryService, communicationService)
+	communicationApi := api.NewCommunicationApi(peerQueryService, communicationService, 0)
 
 	return communicationApi
 }
+
+func TestCommunicationApi_Bootstrap(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			bootstrapAddress string
+			myAddress        string
+		}
+		dialed []string
+	}{
+		"dial to bootstrap node": {
+			input: struct {
+				bootstrapAddress string
+				myAddress        string
+			}{bootstrapAddress: "127.0.0.1:5555", myAddress: "127.0.0.1:13579"},
+			dialed: []string{"127.0.0.1:5555"},
+		},
+		"i am bootstrap node": {
+			input: struct {
+				bootstrapAddress string
+				myAddress        string
+			}{bootstrapAddress: "127.0.0.1:13579", myAddress: "127.0.0.1:13579"},
+			dialed: []string{},
+		},
+		"no bootstrap node": {
+			input: struct {
+				bootstrapAddress string
+				myAddress        string
+			}{bootstrapAddress: "", myAddress: "127.0.0.1:13579"},
+			dialed: []string{},
+		},
+	}
+
+	for testName, test := range tests {
+
+		t.Logf("running test case %s", testName)
+
+		// given
+		dialed := make([]string, 0)
+
+		communicationService := &mock.MockCommunicationService{}
+		communicationService.DialFunc = func(ipAddress string) error {
+			dialed = append(dialed, ipAddress)
+			return nil
+		}
+
+		communicationApi := api.NewCommunicationApi(&mock.MockPeerQueryService{}, communicationService, 0)
+
+		// when
+		err := communicationApi.Bootstrap(test.input.bootstrapAddress, test.input.myAddress)
+
+		// then
+		assert.Equal(t, err, nil)
+		assert.Equal(t, dialed, test.dialed)
+	}
+}
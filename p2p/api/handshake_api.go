@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/p2p"
+)
+
+// 연결된 node와 NodeMetadata를 교환하여 같은 network의 호환되는 node인지 확인한다.
+// 호환되지 않는 node는 peer table에서 지우고 연결을 끊는다.
+type HandshakeApi struct {
+	softwareVersion   string
+	networkId         string
+	chainQueryService p2p.ChainQueryService
+	peerRepository    p2p.PeerRepository
+	peerApi           PeerApi
+	client            p2p.Client
+}
+
+func NewHandshakeApi(softwareVersion string, networkId string, chainQueryService p2p.ChainQueryService, peerRepository p2p.PeerRepository, peerApi PeerApi, client p2p.Client) *HandshakeApi {
+
+	return &HandshakeApi{
+		softwareVersion:   softwareVersion,
+		networkId:         networkId,
+		chainQueryService: chainQueryService,
+		peerRepository:    peerRepository,
+		peerApi:           peerApi,
+		client:            client,
+	}
+}
+
+// 이 node의 현재 NodeMetadata. genesis block이 없으면 GenesisSeal은 비어있다.
+func (ha *HandshakeApi) GetMetadata() p2p.NodeMetadata {
+
+	genesisSeal, err := ha.chainQueryService.GetGenesisSeal()
+
+	if err != nil {
+		genesisSeal = nil
+	}
+
+	height, err := ha.chainQueryService.GetLastHeight()
+
+	if err != nil {
+		height = 0
+	}
+
+	return p2p.NodeMetadata{
+		SoftwareVersion: ha.softwareVersion,
+		ProtocolVersion: p2p.ProtocolVersion,
+		NetworkId:       ha.networkId,
+		GenesisSeal:     genesisSeal,
+		Height:          height,
+	}
+}
+
+func (ha *HandshakeApi) SendHandshake(connectionId string) error {
+
+	if connectionId == "" {
+		return ErrEmptyConnectionId
+	}
+
+	grpcDeliverCommand, err := p2p.CreateGrpcDeliverCommand("HandshakeProtocol", p2p.HandshakeMessage{Metadata: ha.GetMetadata()})
+
+	if err != nil {
+		return err
+	}
+
+	grpcDeliverCommand.RecipientList = []string{connectionId}
+
+	return ha.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
+}
+
+// 받은 NodeMetadata가 호환되면 peer에 저장하고, 호환되지 않으면 연결을 끊고 error를 반환한다.
+func (ha *HandshakeApi) HandleHandshake(connectionId string, message p2p.HandshakeMessage) error {
+
+	if connectionId == "" {
+		return ErrEmptyConnectionId
+	}
+
+	if err := ha.GetMetadata().CheckCompatible(message.Metadata); err != nil {
+		logger.Warn(nil, fmt.Sprintf("[P2P] Refuse incompatible peer - peer: [%s], network: [%s], protocol version: [%d], err: [%s]", connectionId, message.Metadata.NetworkId, message.Metadata.ProtocolVersion, err.Error()))
+
+		if err := ha.peerApi.Remove(p2p.PeerId{Id: connectionId}); err != nil {
+			logger.Error(nil, fmt.Sprintf("[P2P] Fail to remove incompatible peer - peer: [%s], err: [%s]", connectionId, err.Error()))
+		}
+
+		ha.client.Call("connection.close", command.CloseConnection{ConnectionID: connectionId}, func(_ struct{}, err rpc.Error) {})
+
+		return err
+	}
+
+	return ha.peerRepository.UpdateMetadata(connectionId, message.Metadata)
+}
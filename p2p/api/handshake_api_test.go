@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/api"
+	"github.com/it-chain/engine/p2p/infra/mem"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupChainQueryService(genesisSeal []byte, height uint64) *mock.MockChainQueryService {
+
+	chainQueryService := &mock.MockChainQueryService{}
+	chainQueryService.GetGenesisSealFunc = func() ([]byte, error) {
+		return genesisSeal, nil
+	}
+	chainQueryService.GetLastHeightFunc = func() (uint64, error) {
+		return height, nil
+	}
+
+	return chainQueryService
+}
+
+func TestHandshakeApi_SendHandshake(t *testing.T) {
+
+	// given
+	delivered := make([]command.DeliverGrpc, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		assert.Equal(t, "message.deliver", queue)
+		delivered = append(delivered, params.(command.DeliverGrpc))
+		return nil
+	}
+
+	peerRepository := mem.NewPeerReopository()
+	handshakeApi := api.NewHandshakeApi("0.1.1", "default", setupChainQueryService([]byte("genesis"), 7), &peerRepository, &mock.MockPeerApi{}, client)
+
+	// when
+	assert.NoError(t, handshakeApi.SendHandshake("peer"))
+
+	// then
+	assert.Equal(t, 1, len(delivered))
+	assert.Equal(t, "HandshakeProtocol", delivered[0].Protocol)
+	assert.Equal(t, []string{"peer"}, delivered[0].RecipientList)
+
+	message := p2p.HandshakeMessage{}
+	assert.NoError(t, json.Unmarshal(delivered[0].Body, &message))
+	assert.Equal(t, p2p.NodeMetadata{
+		SoftwareVersion: "0.1.1",
+		ProtocolVersion: p2p.ProtocolVersion,
+		NetworkId:       "default",
+		GenesisSeal:     []byte("genesis"),
+		Height:          7,
+	}, message.Metadata)
+
+	// when, then
+	assert.Equal(t, api.ErrEmptyConnectionId, handshakeApi.SendHandshake(""))
+}
+
+func TestHandshakeApi_GetMetadata_WithoutGenesisBlock(t *testing.T) {
+
+	// given
+	chainQueryService := &mock.MockChainQueryService{}
+	chainQueryService.GetGenesisSealFunc = func() ([]byte, error) {
+		return nil, errors.New("no block")
+	}
+	chainQueryService.GetLastHeightFunc = func() (uint64, error) {
+		return 0, errors.New("no block")
+	}
+
+	peerRepository := mem.NewPeerReopository()
+	handshakeApi := api.NewHandshakeApi("0.1.1", "default", chainQueryService, &peerRepository, &mock.MockPeerApi{}, mock.MockClient{})
+
+	// when
+	metadata := handshakeApi.GetMetadata()
+
+	// then
+	assert.Nil(t, metadata.GenesisSeal)
+	assert.Equal(t, uint64(0), metadata.Height)
+}
+
+func TestHandshakeApi_HandleHandshake(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			metadata p2p.NodeMetadata
+		}
+		output struct {
+			removed bool
+			closed  bool
+		}
+		err error
+	}{
+		"compatible peer": {
+			input: struct{ metadata p2p.NodeMetadata }{metadata: p2p.NodeMetadata{ProtocolVersion: p2p.ProtocolVersion, NetworkId: "default", GenesisSeal: []byte("genesis"), Height: 10}},
+			output: struct {
+				removed bool
+				closed  bool
+			}{removed: false, closed: false},
+			err: nil,
+		},
+		"peer of other network": {
+			input: struct{ metadata p2p.NodeMetadata }{metadata: p2p.NodeMetadata{ProtocolVersion: p2p.ProtocolVersion, NetworkId: "test", GenesisSeal: []byte("genesis"), Height: 10}},
+			output: struct {
+				removed bool
+				closed  bool
+			}{removed: true, closed: true},
+			err: p2p.ErrNetworkIdMismatch,
+		},
+		"peer of other genesis block": {
+			input: struct{ metadata p2p.NodeMetadata }{metadata: p2p.NodeMetadata{ProtocolVersion: p2p.ProtocolVersion, NetworkId: "default", GenesisSeal: []byte("other"), Height: 10}},
+			output: struct {
+				removed bool
+				closed  bool
+			}{removed: true, closed: true},
+			err: p2p.ErrGenesisSealMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		peerRepository := mem.NewPeerReopository()
+		peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "peer"}, IpAddress: "1.ipAddr"})
+
+		removed := false
+		peerApi := &mock.MockPeerApi{}
+		peerApi.RemoveFunc = func(peerId p2p.PeerId) error {
+			assert.Equal(t, "peer", peerId.Id)
+			removed = true
+			return peerRepository.Remove(peerId.Id)
+		}
+
+		closed := false
+		client := mock.MockClient{}
+		client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+			assert.Equal(t, "connection.close", queue)
+			assert.Equal(t, "peer", params.(command.CloseConnection).ConnectionID)
+			closed = true
+			return nil
+		}
+
+		handshakeApi := api.NewHandshakeApi("0.1.1", "default", setupChainQueryService([]byte("genesis"), 3), &peerRepository, peerApi, client)
+
+		// when
+		err := handshakeApi.HandleHandshake("peer", p2p.HandshakeMessage{Metadata: test.input.metadata})
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output.removed, removed)
+		assert.Equal(t, test.output.closed, closed)
+
+		if err == nil {
+			peer, _ := peerRepository.FindPeerById(p2p.PeerId{Id: "peer"})
+			assert.Equal(t, test.input.metadata, peer.Metadata)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/common/event"
@@ -31,19 +32,28 @@ var ErrNoMatchingPeerWithIpAddress = errors.New("no matching peer with ip addres
 
 type ILeaderApi interface {
 	UpdateLeaderWithAddress(ipAddress string) error
-	UpdateLeaderWithLargePeerTable(oppositePLTable p2p.PLTable) error
+	UpdateLeaderWithPLTable(oppositePLTable p2p.PLTable) error
+	UpdateLeaderWithTerm(leader p2p.Leader) error
+	VerifyLeader(leader p2p.Leader) error
+	VerifyVote(vote p2p.Vote) error
 }
 
 type LeaderApi struct {
 	PeerRepository p2p.PeerRepository
 	eventService   common.EventService
+	nodeId         string
+	signer         p2p.LeaderSigner
+	verifier       p2p.LeaderVerifier
 }
 
-func NewLeaderApi(peerRepository p2p.PeerRepository, eventService common.EventService) LeaderApi {
+func NewLeaderApi(peerRepository p2p.PeerRepository, eventService common.EventService, nodeId string, signer p2p.LeaderSigner, verifier p2p.LeaderVerifier) LeaderApi {
 
 	return LeaderApi{
 		PeerRepository: peerRepository,
 		eventService:   eventService,
+		nodeId:         nodeId,
+		signer:         signer,
+		verifier:       verifier,
 	}
 }
 
@@ -82,41 +92,95 @@ func (la *LeaderApi) UpdateLeaderWithAddress(ipAddress string) error {
 	return ErrNoMatchingPeerWithIpAddress
 }
 
-func (la *LeaderApi) UpdateLeaderWithLargePeerTable(oppositePLTable p2p.PLTable) error {
+// election으로 term의 leader가 정해지면 호출된다.
+func (la *LeaderApi) UpdateLeaderWithTerm(leader p2p.Leader) error {
 
-	myPLTable, _ := la.PeerRepository.GetPLTable()
+	if leader.LeaderId.Id == "" {
+		return ErrEmptyLeaderId
+	}
 
-	myLeader, _ := myPLTable.GetLeader()
+	err := la.PeerRepository.SetLeader(leader)
 
-	if len(myPLTable.PeerTable) < len(oppositePLTable.PeerTable) {
+	if err != nil {
+		return err
+	}
 
-		err := la.PeerRepository.SetLeader(oppositePLTable.Leader)
+	event := event.LeaderUpdated{
+		LeaderId: leader.LeaderId.Id,
+		Term:     leader.Term,
+	}
 
-		if err != nil {
-			return err
-		}
+	return la.eventService.Publish("leader.updated", event)
+}
 
-		event := event.LeaderUpdated{
-			LeaderId: oppositePLTable.Leader.LeaderId.Id,
-		}
+// 이 node가 term의 leader임을 서명한다. 선출의 증명으로 받은 vote를 함께 담는다.
+func (la *LeaderApi) SignLeader(term uint64, votes []p2p.Vote) (p2p.Leader, error) {
+
+	return la.signer.Sign(p2p.Leader{
+		LeaderId: p2p.LeaderId{Id: la.nodeId},
+		Term:     term,
+		Votes:    votes,
+	})
+}
 
-		return la.eventService.Publish("leader.updated", event)
+// 이 node가 term에서 leaderId에게 투표했음을 서명한다.
+func (la *LeaderApi) SignVote(leaderId string, term uint64) (p2p.Vote, error) {
 
-	} else {
+	return la.signer.SignVote(p2p.Vote{
+		VoterId:  la.nodeId,
+		LeaderId: leaderId,
+		Term:     term,
+	})
+}
 
-		err := la.PeerRepository.SetLeader(myLeader)
+// 서명이 맞고, 알려진 peer의 과반이 투표했고, term이 local term과 너무 멀지 않은 leader만 받아들인다.
+func (la *LeaderApi) VerifyLeader(leader p2p.Leader) error {
 
-		if err != nil {
-			return err
-		}
+	if err := la.verifier.Verify(leader); err != nil {
+		return err
+	}
 
-		event := event.LeaderUpdated{
-			LeaderId: myLeader.LeaderId.Id,
-		}
+	pLTable, err := la.PeerRepository.GetPLTable()
+
+	if err != nil {
+		return err
+	}
 
-		return la.eventService.Publish("leader.updated", event)
+	if !leader.IsVotedByMajority(pLTable, la.nodeId) {
+		return p2p.ErrNotElectedLeader
+	}
+
+	myLeader, _ := la.PeerRepository.GetLeader()
 
+	if !leader.IsTermInRange(myLeader.Term, myLeader.GetID() != "") {
+		return p2p.ErrLeaderTermTooHigh
 	}
 
 	return nil
 }
+
+func (la *LeaderApi) VerifyVote(vote p2p.Vote) error {
+
+	return la.verifier.VerifyVote(vote)
+}
+
+// 다른 peer의 PLTable에 있는 leader는 term이 더 크고 VerifyLeader를 통과할 때만 받아들인다.
+// peer table의 크기는 leader를 정하는데 쓰이지 않는다.
+func (la *LeaderApi) UpdateLeaderWithPLTable(oppositePLTable p2p.PLTable) error {
+
+	myLeader, _ := la.PeerRepository.GetLeader()
+	oppositeLeader := oppositePLTable.Leader
+
+	if oppositeLeader.LeaderId.Id == "" || oppositeLeader.Term <= myLeader.Term {
+		return nil
+	}
+
+	if err := la.VerifyLeader(oppositeLeader); err != nil {
+		logger.Info(nil, fmt.Sprintf("[P2P] Leader of peer table rejected - leader: [%s], term: [%d], err: [%s]", oppositeLeader.LeaderId.Id, oppositeLeader.Term, err))
+		return err
+	}
+
+	logger.Info(nil, fmt.Sprintf("[P2P] Leader updated with peer table - leader: [%s], term: [%d]", oppositeLeader.LeaderId.Id, oppositeLeader.Term))
+
+	return la.UpdateLeaderWithTerm(oppositeLeader)
+}
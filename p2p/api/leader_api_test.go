@@ -23,6 +23,7 @@ import (
 	"github.com/it-chain/engine/p2p"
 	"github.com/it-chain/engine/p2p/api"
 	"github.com/it-chain/engine/p2p/infra/mem"
+	p2pMock "github.com/it-chain/engine/p2p/test/mock"
 	"github.com/magiconair/properties/assert"
 )
 
@@ -99,139 +100,258 @@ func TestLeaderApi_UpdateLeaderWithAddress(t *testing.T) {
 	}
 }
 
-func TestLeaderApi_UpdateLeaderWithLargePeerTable(t *testing.T) {
+func TestLeaderApi_UpdateLeaderWithPLTable(t *testing.T) {
 
 	tests := map[string]struct {
 		input struct {
-			myPLTable       p2p.PLTable
 			oppositePLTable p2p.PLTable
 		}
 		output struct {
 			leader p2p.Leader
 		}
+		err error
 	}{
-		"success": {
-			input: struct {
-				myPLTable       p2p.PLTable
-				oppositePLTable p2p.PLTable
-			}{myPLTable: p2p.PLTable{
-				Leader: p2p.Leader{
-					LeaderId: p2p.LeaderId{
-						Id: "1",
-					},
-				},
-				PeerTable: map[string]p2p.Peer{
-					"1": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-				},
-			}, oppositePLTable: p2p.PLTable{
-				Leader: p2p.Leader{
-					LeaderId: p2p.LeaderId{
-						Id: "2",
-					},
-				},
-				PeerTable: map[string]p2p.Peer{
-					"1": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-					"2": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-					"3": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-					"4": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-				},
+		"leader of higher term": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("2"), Votes: signedVotes("2", 4, "1", "3")},
 			}},
-			output: struct{ leader p2p.Leader }{leader: p2p.Leader{
-				LeaderId: p2p.LeaderId{
-					Id: "2",
-				},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("2"), Votes: signedVotes("2", 4, "1", "3")}},
+			err:    nil,
+		},
+		"self signed leader of huge term": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 1 << 62, Signature: []byte("2")},
 			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrNotElectedLeader,
 		},
-		"not updated with longer peer list case": {
-			input: struct {
-				myPLTable       p2p.PLTable
-				oppositePLTable p2p.PLTable
-			}{myPLTable: p2p.PLTable{
-				Leader: p2p.Leader{
-					LeaderId: p2p.LeaderId{
-						Id: "1",
-					},
-				},
-				PeerTable: map[string]p2p.Peer{
-					"1": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-					"2": {
-						PeerId: p2p.PeerId{
-							Id: "2",
-						},
-					},
-					"3": {
-						PeerId: p2p.PeerId{
-							Id: "3",
-						},
-					},
-				},
-			}, oppositePLTable: p2p.PLTable{
-				Leader: p2p.Leader{
-					LeaderId: p2p.LeaderId{
-						Id: "2",
-					},
-				},
+		"leader voted by unknown peers": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("2"), Votes: signedVotes("2", 4, "x", "y")},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrNotElectedLeader,
+		},
+		"leader with vote of other term": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("2"), Votes: append(signedVotes("2", 4, "1"), signedVotes("2", 3, "3")...)},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrInvalidLeaderSignature,
+		},
+		"leader with forged vote": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("2"), Votes: append(signedVotes("2", 4, "1"), p2p.Vote{VoterId: "3", LeaderId: "2", Term: 4, Signature: []byte("forged")})},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrInvalidLeaderSignature,
+		},
+		"leader of too high term": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 3 + p2p.MaxLeaderTermGap + 1, Signature: []byte("2"), Votes: signedVotes("2", 3+p2p.MaxLeaderTermGap+1, "1", "3")},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrLeaderTermTooHigh,
+		},
+		"leader of same term with larger peer table": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 3, Signature: []byte("2")},
 				PeerTable: map[string]p2p.Peer{
-					"1": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
-					"2": {
-						PeerId: p2p.PeerId{
-							Id: "1",
-						},
-					},
+					"1": {PeerId: p2p.PeerId{Id: "1"}},
+					"2": {PeerId: p2p.PeerId{Id: "2"}},
+					"3": {PeerId: p2p.PeerId{Id: "3"}},
+					"4": {PeerId: p2p.PeerId{Id: "4"}},
 				},
 			}},
-			output: struct {
-				leader p2p.Leader
-			}{
-				leader: p2p.Leader{
-					LeaderId: p2p.LeaderId{
-						Id: "1",
-					},
-				},
-			},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    nil,
+		},
+		"leader of higher term with invalid signature": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 4, Signature: []byte("forged")},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    p2p.ErrInvalidLeaderSignature,
+		},
+		"empty leader": {
+			input: struct{ oppositePLTable p2p.PLTable }{oppositePLTable: p2p.PLTable{
+				Leader: p2p.Leader{Term: 5},
+			}},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3}},
+			err:    nil,
 		},
 	}
 
 	for testName, test := range tests {
 		t.Logf("running test case %s ", testName)
 
-		leaderApi := SetupLeaderApi(test.input.myPLTable)
+		// given
+		leaderApi := SetupLeaderApi(p2p.PLTable{
+			Leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3},
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}},
+				"2": {PeerId: p2p.PeerId{Id: "2"}},
+				"3": {PeerId: p2p.PeerId{Id: "3"}},
+			},
+		})
 
-		leaderApi.UpdateLeaderWithLargePeerTable(test.input.oppositePLTable)
+		// when
+		err := leaderApi.UpdateLeaderWithPLTable(test.input.oppositePLTable)
 
-		leader, _ := leaderApi.PeerRepository.GetLeader()
+		// then
+		assert.Equal(t, err, test.err)
 
+		leader, _ := leaderApi.PeerRepository.GetLeader()
 		assert.Equal(t, leader, test.output.leader)
 	}
+}
+
+func TestLeaderApi_SignLeader(t *testing.T) {
+
+	// given
+	leaderApi := SetupLeaderApi(p2p.PLTable{PeerTable: map[string]p2p.Peer{}})
 
+	// when
+	leader, err := leaderApi.SignLeader(7, nil)
+
+	// then
+	assert.Equal(t, err, nil)
+	assert.Equal(t, leader.GetID(), "me")
+	assert.Equal(t, leader.Term, uint64(7))
+	assert.Equal(t, leaderApi.VerifyLeader(leader), nil)
+}
+
+func TestLeaderApi_VerifyLeader(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			myLeader p2p.Leader
+			leader   p2p.Leader
+		}
+		err error
+	}{
+		"voted by majority": {
+			input: struct {
+				myLeader p2p.Leader
+				leader   p2p.Leader
+			}{
+				myLeader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3},
+				leader:   p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 4, Signature: []byte("1"), Votes: signedVotes("1", 4, "2")},
+			},
+			err: nil,
+		},
+		"voted by minority": {
+			input: struct {
+				myLeader p2p.Leader
+				leader   p2p.Leader
+			}{
+				myLeader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3},
+				leader:   p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 4, Signature: []byte("1")},
+			},
+			err: p2p.ErrNotElectedLeader,
+		},
+		"too high term": {
+			input: struct {
+				myLeader p2p.Leader
+				leader   p2p.Leader
+			}{
+				myLeader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3},
+				leader:   p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 1000, Signature: []byte("1"), Votes: signedVotes("1", 1000, "2")},
+			},
+			err: p2p.ErrLeaderTermTooHigh,
+		},
+		"high term when no leader is known": {
+			input: struct {
+				myLeader p2p.Leader
+				leader   p2p.Leader
+			}{
+				myLeader: p2p.Leader{},
+				leader:   p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 1000, Signature: []byte("1"), Votes: signedVotes("1", 1000, "2")},
+			},
+			err: nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		leaderApi := SetupLeaderApi(p2p.PLTable{
+			Leader: test.input.myLeader,
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}},
+				"2": {PeerId: p2p.PeerId{Id: "2"}},
+			},
+		})
+
+		// when
+		err := leaderApi.VerifyLeader(test.input.leader)
+
+		// then
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestLeaderApi_SignVote(t *testing.T) {
+
+	// given
+	leaderApi := SetupLeaderApi(p2p.PLTable{PeerTable: map[string]p2p.Peer{}})
+
+	// when
+	vote, err := leaderApi.SignVote("candidate", 7)
+
+	// then
+	assert.Equal(t, err, nil)
+	assert.Equal(t, vote.VoterId, "me")
+	assert.Equal(t, vote.LeaderId, "candidate")
+	assert.Equal(t, vote.Term, uint64(7))
+	assert.Equal(t, leaderApi.VerifyVote(vote), nil)
+}
+
+func TestLeaderApi_UpdateLeaderWithTerm(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			leaderId string
+			term     uint64
+		}
+		output struct {
+			leader p2p.Leader
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				leaderId string
+				term     uint64
+			}{leaderId: "2", term: 3},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "2"}, Term: 3}},
+			err:    nil,
+		},
+		"empty leader id": {
+			input: struct {
+				leaderId string
+				term     uint64
+			}{leaderId: "", term: 3},
+			output: struct{ leader p2p.Leader }{leader: p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}}},
+			err:    api.ErrEmptyLeaderId,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		leaderApi := SetupLeaderApi(p2p.PLTable{
+			Leader:    p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}},
+			PeerTable: map[string]p2p.Peer{},
+		})
+
+		err := leaderApi.UpdateLeaderWithTerm(p2p.Leader{LeaderId: p2p.LeaderId{Id: test.input.leaderId}, Term: test.input.term})
+		assert.Equal(t, err, test.err)
+
+		leader, _ := leaderApi.PeerRepository.GetLeader()
+		assert.Equal(t, leader, test.output.leader)
+	}
 }
 
 func SetupLeaderApi(myPLTable p2p.PLTable) api.LeaderApi {
@@ -251,7 +371,51 @@ func SetupLeaderApi(myPLTable p2p.PLTable) api.LeaderApi {
 		return nil
 	}
 
-	leaderApi := api.NewLeaderApi(&peerRepository, &eventService)
+	// leader id, voter id를 서명으로 쓰는 fake signer
+	signer := &p2pMock.MockLeaderSigner{}
+	signer.SignFunc = func(leader p2p.Leader) (p2p.Leader, error) {
+		leader.Signature = []byte(leader.GetID())
+		return leader, nil
+	}
+	signer.SignVoteFunc = func(vote p2p.Vote) (p2p.Vote, error) {
+		vote.Signature = []byte(vote.VoterId)
+		return vote, nil
+	}
+
+	verifier := &p2pMock.MockLeaderVerifier{}
+	verifier.VerifyVoteFunc = func(vote p2p.Vote) error {
+		if string(vote.Signature) != vote.VoterId {
+			return p2p.ErrInvalidLeaderSignature
+		}
+		return nil
+	}
+	verifier.VerifyFunc = func(leader p2p.Leader) error {
+		if string(leader.Signature) != leader.GetID() {
+			return p2p.ErrInvalidLeaderSignature
+		}
+		for _, vote := range leader.Votes {
+			if vote.LeaderId != leader.GetID() || vote.Term != leader.Term {
+				return p2p.ErrInvalidLeaderSignature
+			}
+			if err := verifier.VerifyVote(vote); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	leaderApi := api.NewLeaderApi(&peerRepository, &eventService, "me", signer, verifier)
 
 	return leaderApi
 }
+
+func signedVotes(leaderId string, term uint64, voterIds ...string) []p2p.Vote {
+
+	votes := make([]p2p.Vote, 0)
+
+	for _, voterId := range voterIds {
+		votes = append(votes, p2p.Vote{VoterId: voterId, LeaderId: leaderId, Term: term, Signature: []byte(voterId)})
+	}
+
+	return votes
+}
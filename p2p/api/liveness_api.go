@@ -0,0 +1,182 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/p2p"
+)
+
+// 연결된 peer 들에게 주기적으로 ping을 보내 RTT를 재고, 응답하지 않는 peer를 찾는다.
+// bifrost connection이 끊기지 않은 채로 응답하지 않는 half-open connection도 찾을 수 있다.
+type LivenessApi struct {
+	mux            sync.Mutex
+	nodeId         string
+	liveness       *p2p.Liveness
+	peerRepository p2p.PeerRepository
+	peerApi        PeerApi
+	client         p2p.Client
+	quit           chan struct{}
+}
+
+func NewLivenessApi(nodeId string, liveness *p2p.Liveness, peerRepository p2p.PeerRepository, peerApi PeerApi, client p2p.Client) *LivenessApi {
+
+	return &LivenessApi{
+		mux:            sync.Mutex{},
+		nodeId:         nodeId,
+		liveness:       liveness,
+		peerRepository: peerRepository,
+		peerApi:        peerApi,
+		client:         client,
+		quit:           make(chan struct{}),
+	}
+}
+
+// 죽은 peer는 peer table에서 지우고(PeerDeleted) connection을 닫는다.
+// 나머지 peer 들에게는 ping을 보낸다.
+func (la *LivenessApi) SendPing(now time.Time) error {
+
+	pLTable, err := la.peerRepository.GetPLTable()
+
+	if err != nil {
+		return err
+	}
+
+	peerIds := make([]string, 0)
+
+	for id := range pLTable.PeerTable {
+		if id != la.nodeId {
+			peerIds = append(peerIds, id)
+		}
+	}
+
+	dead := la.liveness.Ping(peerIds)
+
+	for _, id := range dead {
+		logger.Warn(nil, fmt.Sprintf("[P2P] Peer does not respond to ping - peer: [%s]", id))
+
+		if err := la.peerApi.Remove(p2p.PeerId{Id: id}); err != nil {
+			logger.Error(nil, fmt.Sprintf("[P2P] Fail to remove dead peer - peer: [%s], err: [%s]", id, err.Error()))
+		}
+
+		la.client.Call("connection.close", command.CloseConnection{ConnectionID: id}, func(_ struct{}, err rpc.Error) {})
+	}
+
+	recipients := make([]string, 0)
+
+	for _, id := range peerIds {
+		if !contains(dead, id) {
+			recipients = append(recipients, id)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return la.deliver(recipients, "PingProtocol", p2p.PingMessage{TimeUnix: now.UnixNano()})
+}
+
+// ping에 담긴 시간을 그대로 pong으로 돌려준다.
+func (la *LivenessApi) HandlePing(connectionId string, message p2p.PingMessage) error {
+
+	if connectionId == "" {
+		return ErrEmptyConnectionId
+	}
+
+	return la.deliver([]string{connectionId}, "PongProtocol", p2p.PongMessage{TimeUnix: message.TimeUnix})
+}
+
+// pong을 받은 peer는 살아 있는 것으로 보고, ping을 보낸 시간으로부터 RTT를 계산해 저장한다.
+func (la *LivenessApi) HandlePong(connectionId string, message p2p.PongMessage, now time.Time) error {
+
+	if connectionId == "" {
+		return ErrEmptyConnectionId
+	}
+
+	la.liveness.Pong(connectionId)
+
+	rtt := now.Sub(time.Unix(0, message.TimeUnix))
+
+	if rtt < 0 {
+		return nil
+	}
+
+	return la.peerRepository.UpdateLatency(connectionId, rtt)
+}
+
+// interval 마다 SendPing 한다. Stop을 호출할 때 까지 계속된다.
+func (la *LivenessApi) Start(interval time.Duration) {
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-la.quit:
+				return
+			case <-ticker.C:
+				if err := la.SendPing(time.Now()); err != nil {
+					logger.Error(nil, fmt.Sprintf("[P2P] Fail to send ping - err: [%s]", err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+func (la *LivenessApi) Stop() {
+
+	la.mux.Lock()
+	defer la.mux.Unlock()
+
+	select {
+	case <-la.quit:
+	default:
+		close(la.quit)
+	}
+}
+
+func (la *LivenessApi) deliver(recipients []string, protocol string, body interface{}) error {
+
+	grpcDeliverCommand, err := p2p.CreateGrpcDeliverCommand(protocol, body)
+
+	if err != nil {
+		return err
+	}
+
+	grpcDeliverCommand.RecipientList = recipients
+
+	return la.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
+}
+
+func contains(ids []string, id string) bool {
+
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/api"
+	"github.com/it-chain/engine/p2p/infra/mem"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLivenessApi_SendPing(t *testing.T) {
+
+	// given
+	now := time.Now()
+
+	peerRepository := mem.NewPeerReopository()
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "me"}})
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "alive"}})
+	peerRepository.Save(p2p.Peer{PeerId: p2p.PeerId{Id: "dead"}})
+
+	removed := make([]string, 0)
+	peerApi := &mock.MockPeerApi{}
+	peerApi.RemoveFunc = func(peerId p2p.PeerId) error {
+		removed = append(removed, peerId.Id)
+		return peerRepository.Remove(peerId.Id)
+	}
+
+	delivered := make([]command.DeliverGrpc, 0)
+	closed := make([]string, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		switch queue {
+		case "message.deliver":
+			delivered = append(delivered, params.(command.DeliverGrpc))
+		case "connection.close":
+			closed = append(closed, params.(command.CloseConnection).ConnectionID)
+		}
+		return nil
+	}
+
+	livenessApi := api.NewLivenessApi("me", p2p.NewLiveness(1), &peerRepository, peerApi, client)
+
+	// when
+	assert.NoError(t, livenessApi.SendPing(now))
+
+	// then
+	assert.Equal(t, 1, len(delivered))
+	assert.Equal(t, "PingProtocol", delivered[0].Protocol)
+	assert.ElementsMatch(t, []string{"alive", "dead"}, delivered[0].RecipientList)
+
+	message := p2p.PingMessage{}
+	assert.NoError(t, json.Unmarshal(delivered[0].Body, &message))
+	assert.Equal(t, now.UnixNano(), message.TimeUnix)
+
+	// when: only alive peer answers
+	assert.NoError(t, livenessApi.HandlePong("alive", p2p.PongMessage{TimeUnix: now.UnixNano()}, now.Add(20*time.Millisecond)))
+	assert.NoError(t, livenessApi.SendPing(now.Add(time.Second)))
+
+	// then
+	assert.Equal(t, []string{"dead"}, removed)
+	assert.Equal(t, []string{"dead"}, closed)
+	assert.Equal(t, []string{"alive"}, delivered[1].RecipientList)
+
+	peer, err := peerRepository.FindPeerById(p2p.PeerId{Id: "alive"})
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Millisecond, peer.Latency)
+}
+
+func TestLivenessApi_HandlePing(t *testing.T) {
+
+	// given
+	delivered := make([]command.DeliverGrpc, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		delivered = append(delivered, params.(command.DeliverGrpc))
+		return nil
+	}
+
+	peerRepository := mem.NewPeerReopository()
+	livenessApi := api.NewLivenessApi("me", p2p.NewLiveness(3), &peerRepository, &mock.MockPeerApi{}, client)
+
+	// when
+	err := livenessApi.HandlePing("1", p2p.PingMessage{TimeUnix: 10})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "PongProtocol", delivered[0].Protocol)
+	assert.Equal(t, []string{"1"}, delivered[0].RecipientList)
+
+	message := p2p.PongMessage{}
+	assert.NoError(t, json.Unmarshal(delivered[0].Body, &message))
+	assert.Equal(t, int64(10), message.TimeUnix)
+
+	// when
+	err = livenessApi.HandlePing("", p2p.PingMessage{TimeUnix: 10})
+
+	// then
+	assert.Equal(t, api.ErrEmptyConnectionId, err)
+}
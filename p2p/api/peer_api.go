@@ -32,6 +32,13 @@ type PeerApiImpl struct {
 	eventService   common.EventService
 }
 
+func NewPeerApi(peerRepository p2p.PeerRepository, eventService common.EventService) *PeerApiImpl {
+	return &PeerApiImpl{
+		peerRepository: peerRepository,
+		eventService:   eventService,
+	}
+}
+
 func (ps *PeerApiImpl) Save(peer p2p.Peer) error {
 
 	err := ps.peerRepository.Save(peer)
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/p2p"
+)
+
+type RedialConfig struct {
+	InitialBackoff     time.Duration
+	MaxBackoff         time.Duration
+	UnreachableTimeout time.Duration
+	TargetPeerCount    int // 연결된 peer가 이 수에 도달하면 더 이상 dial 하지 않는다. 0이면 제한하지 않는다.
+}
+
+type redialBackoff struct {
+	delay        time.Duration
+	nextAttempt  time.Time
+	firstAttempt time.Time // 이번 실행에서 연결되지 않은 peer에 처음 dial 한 시간
+}
+
+// 재시작한 node가 저장된 peer에 다시 연결하도록 한다.
+// 연결되지 않은 peer에는 exponential backoff로 dial 하고, 처음 dial 한 뒤 UnreachableTimeout 동안 연결되지 않은 peer는 잊는다.
+// 주기적으로 실행되므로 연결된 peer가 TargetPeerCount 보다 적으면 저장된 peer로 채운다.
+type RedialApi struct {
+	mux                  sync.Mutex
+	knownPeerRepository  p2p.KnownPeerRepository
+	peerQueryService     p2p.PeerQueryService
+	communicationService CommunicationService
+	config               RedialConfig
+	backoffs             map[string]*redialBackoff
+	quit                 chan struct{}
+}
+
+func NewRedialApi(knownPeerRepository p2p.KnownPeerRepository, peerQueryService p2p.PeerQueryService, communicationService CommunicationService, config RedialConfig) *RedialApi {
+
+	return &RedialApi{
+		mux:                  sync.Mutex{},
+		knownPeerRepository:  knownPeerRepository,
+		peerQueryService:     peerQueryService,
+		communicationService: communicationService,
+		config:               config,
+		backoffs:             make(map[string]*redialBackoff),
+		quit:                 make(chan struct{}),
+	}
+}
+
+// 저장된 peer 중 연결되지 않았고 backoff가 지난 peer에 dial 한다.
+func (ra *RedialApi) Redial(now time.Time) error {
+
+	ra.mux.Lock()
+	defer ra.mux.Unlock()
+
+	knownPeers, err := ra.knownPeerRepository.FindKnownPeers()
+
+	if err != nil {
+		return err
+	}
+
+	dialable, err := ra.dialableCount(len(knownPeers))
+
+	if err != nil {
+		return err
+	}
+
+	for _, knownPeer := range knownPeers {
+
+		id := knownPeer.Peer.PeerId.Id
+
+		// 이미 연결된 peer
+		if _, err := ra.peerQueryService.FindPeerById(knownPeer.Peer.PeerId); err == nil {
+			delete(ra.backoffs, id)
+			continue
+		}
+
+		backoff, ok := ra.backoffs[id]
+
+		if !ok {
+			backoff = &redialBackoff{delay: ra.config.InitialBackoff, nextAttempt: now}
+			ra.backoffs[id] = backoff
+		}
+
+		// LastSeen은 연결이 끊길 때만 저장되므로 재시작한 node에서는 오래된 값이다.
+		// 이번 실행에서 실제로 dial 한 뒤에도 UnreachableTimeout 동안 연결되지 않은 peer만 잊는다.
+		if !backoff.firstAttempt.IsZero() && now.Sub(backoff.firstAttempt) > ra.config.UnreachableTimeout {
+			logger.Info(nil, fmt.Sprintf("[P2P] Forget unreachable peer - peer: [%s], first redial: [%s], last seen: [%s]", id, backoff.firstAttempt, knownPeer.LastSeen))
+			delete(ra.backoffs, id)
+			ra.knownPeerRepository.Forget(id)
+			continue
+		}
+
+		if now.Before(backoff.nextAttempt) || knownPeer.Peer.IpAddress == "" || dialable <= 0 {
+			continue
+		}
+
+		if err := ra.communicationService.Dial(knownPeer.Peer.IpAddress); err != nil {
+			logger.Warn(nil, fmt.Sprintf("[P2P] Fail to redial peer - peer: [%s], err: [%s]", id, err.Error()))
+		}
+
+		dialable--
+
+		if backoff.firstAttempt.IsZero() {
+			backoff.firstAttempt = now
+		}
+
+		backoff.nextAttempt = now.Add(backoff.delay)
+		backoff.delay = backoff.delay * 2
+
+		if backoff.delay > ra.config.MaxBackoff {
+			backoff.delay = ra.config.MaxBackoff
+		}
+	}
+
+	return nil
+}
+
+// TargetPeerCount를 채우기 위해 더 dial 할 수 있는 peer의 수
+func (ra *RedialApi) dialableCount(numOfKnownPeers int) (int, error) {
+
+	if ra.config.TargetPeerCount <= 0 {
+		return numOfKnownPeers, nil
+	}
+
+	pLTable, err := ra.peerQueryService.GetPLTable()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ra.config.TargetPeerCount - len(pLTable.PeerTable), nil
+}
+
+// interval 마다 Redial 한다. Stop을 호출할 때 까지 계속된다.
+func (ra *RedialApi) Start(interval time.Duration) {
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := ra.Redial(time.Now()); err != nil {
+				logger.Error(nil, fmt.Sprintf("[P2P] Fail to redial known peers - err: [%s]", err.Error()))
+			}
+
+			select {
+			case <-ra.quit:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (ra *RedialApi) Stop() {
+
+	ra.mux.Lock()
+	defer ra.mux.Unlock()
+
+	select {
+	case <-ra.quit:
+	default:
+		close(ra.quit)
+	}
+}
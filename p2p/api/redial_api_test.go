@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/api"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedialApi_Redial(t *testing.T) {
+
+	// given
+	now := time.Now()
+
+	knownPeers := []p2p.KnownPeer{
+		{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "connected"}, IpAddress: "1.ipAddr"}, LastSeen: now},
+		{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "disconnected"}, IpAddress: "2.ipAddr"}, LastSeen: now.Add(-time.Minute)},
+	}
+
+	forgotten := make([]string, 0)
+	knownPeerRepository := &mock.MockKnownPeerRepository{}
+	knownPeerRepository.FindKnownPeersFunc = func() ([]p2p.KnownPeer, error) {
+		return knownPeers, nil
+	}
+	knownPeerRepository.ForgetFunc = func(id string) error {
+		forgotten = append(forgotten, id)
+		return nil
+	}
+
+	peerQueryService := mock.MockPeerQueryService{}
+	peerQueryService.FindPeerByIdFunc = func(peerId p2p.PeerId) (p2p.Peer, error) {
+		if peerId.Id == "connected" {
+			return p2p.Peer{PeerId: peerId}, nil
+		}
+		return p2p.Peer{}, p2p.ErrNoMatchingPeerId
+	}
+
+	dialed := make([]time.Duration, 0)
+	elapsed := time.Duration(0)
+	communicationService := &mock.MockCommunicationService{}
+	communicationService.DialFunc = func(ipAddress string) error {
+		assert.Equal(t, "2.ipAddr", ipAddress)
+		dialed = append(dialed, elapsed)
+		return nil
+	}
+
+	redialApi := api.NewRedialApi(knownPeerRepository, peerQueryService, communicationService, api.RedialConfig{
+		InitialBackoff:     time.Second,
+		MaxBackoff:         4 * time.Second,
+		UnreachableTimeout: time.Hour,
+	})
+
+	// when
+	for elapsed = 0; elapsed <= 20*time.Second; elapsed += 500 * time.Millisecond {
+		assert.NoError(t, redialApi.Redial(now.Add(elapsed)))
+	}
+
+	// then: backoff 1s, 2s, 4s, 4s ...
+	assert.Equal(t, []time.Duration{
+		0,
+		1 * time.Second,
+		3 * time.Second,
+		7 * time.Second,
+		11 * time.Second,
+		15 * time.Second,
+		19 * time.Second,
+	}, dialed)
+	assert.Equal(t, 0, len(forgotten))
+}
+
+func TestRedialApi_Redial_ForgetUnreachable(t *testing.T) {
+
+	// given: 재시작한 node에 저장된 peer는 LastSeen이 오래되었다.
+	now := time.Now()
+
+	knownPeerRepository := &mock.MockKnownPeerRepository{}
+	knownPeerRepository.FindKnownPeersFunc = func() ([]p2p.KnownPeer, error) {
+		return []p2p.KnownPeer{
+			{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "reachable"}, IpAddress: "1.ipAddr"}, LastSeen: now.Add(-2 * time.Hour)},
+			{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "unreachable"}, IpAddress: "2.ipAddr"}, LastSeen: now.Add(-2 * time.Hour)},
+		}, nil
+	}
+
+	forgotten := make([]string, 0)
+	knownPeerRepository.ForgetFunc = func(id string) error {
+		forgotten = append(forgotten, id)
+		return nil
+	}
+
+	connected := make(map[string]bool)
+	peerQueryService := mock.MockPeerQueryService{}
+	peerQueryService.FindPeerByIdFunc = func(peerId p2p.PeerId) (p2p.Peer, error) {
+		if connected[peerId.Id] {
+			return p2p.Peer{PeerId: peerId}, nil
+		}
+		return p2p.Peer{}, p2p.ErrNoMatchingPeerId
+	}
+
+	dialed := make([]string, 0)
+	communicationService := &mock.MockCommunicationService{}
+	communicationService.DialFunc = func(ipAddress string) error {
+		dialed = append(dialed, ipAddress)
+		if ipAddress == "1.ipAddr" {
+			connected["reachable"] = true
+		}
+		return nil
+	}
+
+	redialApi := api.NewRedialApi(knownPeerRepository, peerQueryService, communicationService, api.RedialConfig{
+		InitialBackoff:     time.Second,
+		MaxBackoff:         4 * time.Second,
+		UnreachableTimeout: time.Minute,
+	})
+
+	// when: first redial after restart
+	assert.NoError(t, redialApi.Redial(now))
+
+	// then: stale peers are dialed, not forgotten
+	assert.Equal(t, []string{"1.ipAddr", "2.ipAddr"}, dialed)
+	assert.Equal(t, 0, len(forgotten))
+
+	// when: still unreachable within timeout since the first redial
+	assert.NoError(t, redialApi.Redial(now.Add(time.Minute)))
+
+	// then
+	assert.Equal(t, 0, len(forgotten))
+
+	// when: unreachable longer than timeout since the first redial
+	assert.NoError(t, redialApi.Redial(now.Add(time.Minute+time.Second)))
+
+	// then: only the peer which failed to connect is forgotten
+	assert.Equal(t, []string{"unreachable"}, forgotten)
+}
+
+func TestRedialApi_Redial_TargetPeerCount(t *testing.T) {
+
+	// given
+	now := time.Now()
+
+	knownPeerRepository := &mock.MockKnownPeerRepository{}
+	knownPeerRepository.FindKnownPeersFunc = func() ([]p2p.KnownPeer, error) {
+		return []p2p.KnownPeer{
+			{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr"}, LastSeen: now},
+			{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "3"}, IpAddress: "3.ipAddr"}, LastSeen: now},
+			{Peer: p2p.Peer{PeerId: p2p.PeerId{Id: "4"}, IpAddress: "4.ipAddr"}, LastSeen: now},
+		}, nil
+	}
+
+	connected := map[string]p2p.Peer{
+		"1": {PeerId: p2p.PeerId{Id: "1"}, IpAddress: "1.ipAddr"},
+	}
+
+	peerQueryService := mock.MockPeerQueryService{}
+	peerQueryService.FindPeerByIdFunc = func(peerId p2p.PeerId) (p2p.Peer, error) {
+		if peer, ok := connected[peerId.Id]; ok {
+			return peer, nil
+		}
+		return p2p.Peer{}, p2p.ErrNoMatchingPeerId
+	}
+	peerQueryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{PeerTable: connected}, nil
+	}
+
+	dialed := make([]string, 0)
+	communicationService := &mock.MockCommunicationService{}
+	communicationService.DialFunc = func(ipAddress string) error {
+		dialed = append(dialed, ipAddress)
+		return nil
+	}
+
+	redialApi := api.NewRedialApi(knownPeerRepository, peerQueryService, communicationService, api.RedialConfig{
+		InitialBackoff:     time.Second,
+		MaxBackoff:         4 * time.Second,
+		UnreachableTimeout: time.Hour,
+		TargetPeerCount:    2,
+	})
+
+	// when: 1 peer is connected
+	assert.NoError(t, redialApi.Redial(now))
+
+	// then: dial only 1 peer to fill target peer count
+	assert.Equal(t, 1, len(dialed))
+
+	// when: target peer count is reached
+	connected["2"] = p2p.Peer{PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr"}
+	dialed = make([]string, 0)
+	assert.NoError(t, redialApi.Redial(now.Add(10*time.Second)))
+
+	// then
+	assert.Equal(t, 0, len(dialed))
+}
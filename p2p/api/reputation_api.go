@@ -0,0 +1,196 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/p2p"
+)
+
+var ErrBannedPeer = errors.New("peer is banned")
+
+type ReputationConfig struct {
+	BanThreshold int
+	BanDuration  time.Duration
+}
+
+// 규칙을 어긴 peer의 평판을 깎고, 평판이 BanThreshold 이하로 떨어진 peer는 연결을 끊고 BanDuration 동안 ban 한다.
+type ReputationApi struct {
+	reputation          *p2p.Reputation
+	banRepository       p2p.BanRepository
+	knownPeerRepository p2p.KnownPeerRepository
+	peerApi             PeerApi
+	client              p2p.Client
+	config              ReputationConfig
+}
+
+func NewReputationApi(reputation *p2p.Reputation, banRepository p2p.BanRepository, knownPeerRepository p2p.KnownPeerRepository, peerApi PeerApi, client p2p.Client, config ReputationConfig) *ReputationApi {
+
+	return &ReputationApi{
+		reputation:          reputation,
+		banRepository:       banRepository,
+		knownPeerRepository: knownPeerRepository,
+		peerApi:             peerApi,
+		client:              client,
+		config:              config,
+	}
+}
+
+func (ra *ReputationApi) Penalize(peerId string, violation string) error {
+
+	if peerId == "" {
+		return ErrEmptyConnectionId
+	}
+
+	score := ra.reputation.Penalize(peerId, violation)
+
+	logger.Warn(nil, fmt.Sprintf("[P2P] Peer penalized - peer: [%s], violation: [%s], score: [%d]", peerId, violation, score))
+
+	if score > ra.config.BanThreshold {
+		return nil
+	}
+
+	return ra.Ban(peerId, violation, time.Now())
+}
+
+// peer를 peer table과 known peer에서 지우고 연결을 끊는다.
+func (ra *ReputationApi) Ban(peerId string, reason string, now time.Time) error {
+
+	ban := p2p.Ban{
+		PeerId: peerId,
+		Reason: reason,
+		Until:  now.Add(ra.config.BanDuration),
+	}
+
+	if err := ra.banRepository.SaveBan(ban); err != nil {
+		return err
+	}
+
+	logger.Warn(nil, fmt.Sprintf("[P2P] Peer banned - peer: [%s], reason: [%s], until: [%s]", peerId, reason, ban.Until))
+
+	ra.reputation.Reset(peerId)
+
+	if err := ra.peerApi.Remove(p2p.PeerId{Id: peerId}); err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to remove banned peer - peer: [%s], err: [%s]", peerId, err.Error()))
+	}
+
+	// redial 하지 않도록 한다.
+	if err := ra.knownPeerRepository.Forget(peerId); err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to forget banned peer - peer: [%s], err: [%s]", peerId, err.Error()))
+	}
+
+	ra.disconnect(peerId)
+
+	return nil
+}
+
+// ban 된 peer와의 연결은 바로 끊는다.
+func (ra *ReputationApi) RejectIfBanned(peerId string, now time.Time) (bool, error) {
+
+	banned, err := ra.IsBanned(peerId, now)
+
+	if err != nil || !banned {
+		return false, err
+	}
+
+	logger.Info(nil, fmt.Sprintf("[P2P] Reject connection of banned peer - peer: [%s]", peerId))
+
+	ra.disconnect(peerId)
+
+	return true, nil
+}
+
+func (ra *ReputationApi) IsBanned(peerId string, now time.Time) (bool, error) {
+
+	bans, err := ra.GetBans(now)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, ban := range bans {
+		if ban.PeerId == peerId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// 기한이 지나지 않은 ban 들을 반환한다. 기한이 지난 ban은 지운다.
+func (ra *ReputationApi) GetBans(now time.Time) ([]p2p.Ban, error) {
+
+	bans, err := ra.banRepository.FindBans()
+
+	if err != nil {
+		return nil, err
+	}
+
+	activeBans := make([]p2p.Ban, 0)
+
+	for _, ban := range bans {
+		if !ban.IsExpired(now) {
+			activeBans = append(activeBans, ban)
+			continue
+		}
+
+		if err := ra.banRepository.RemoveBan(ban.PeerId); err != nil {
+			return nil, err
+		}
+	}
+
+	return activeBans, nil
+}
+
+func (ra *ReputationApi) Unban(peerId string) error {
+
+	if err := ra.banRepository.RemoveBan(peerId); err != nil {
+		return err
+	}
+
+	ra.reputation.Reset(peerId)
+
+	return nil
+}
+
+func (ra *ReputationApi) ClearBans() error {
+
+	bans, err := ra.banRepository.FindBans()
+
+	if err != nil {
+		return err
+	}
+
+	for _, ban := range bans {
+		if err := ra.Unban(ban.PeerId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ra *ReputationApi) disconnect(peerId string) {
+
+	ra.client.Call("connection.close", command.CloseConnection{ConnectionID: peerId}, func(_ struct{}, err rpc.Error) {})
+}
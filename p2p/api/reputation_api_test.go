@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/api"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type memBanRepository struct {
+	bans map[string]p2p.Ban
+}
+
+func (m *memBanRepository) SaveBan(ban p2p.Ban) error {
+	m.bans[ban.PeerId] = ban
+	return nil
+}
+
+func (m *memBanRepository) FindBans() ([]p2p.Ban, error) {
+	bans := make([]p2p.Ban, 0)
+	for _, ban := range m.bans {
+		bans = append(bans, ban)
+	}
+	return bans, nil
+}
+
+func (m *memBanRepository) RemoveBan(peerId string) error {
+	delete(m.bans, peerId)
+	return nil
+}
+
+func TestReputationApi_Penalize(t *testing.T) {
+
+	// given
+	banRepository := &memBanRepository{bans: make(map[string]p2p.Ban)}
+
+	forgotten := make([]string, 0)
+	knownPeerRepository := &mock.MockKnownPeerRepository{}
+	knownPeerRepository.ForgetFunc = func(id string) error {
+		forgotten = append(forgotten, id)
+		return nil
+	}
+
+	removed := make([]string, 0)
+	peerApi := &mock.MockPeerApi{}
+	peerApi.RemoveFunc = func(peerId p2p.PeerId) error {
+		removed = append(removed, peerId.Id)
+		return nil
+	}
+
+	closed := make([]string, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		assert.Equal(t, "connection.close", queue)
+		closed = append(closed, params.(command.CloseConnection).ConnectionID)
+		return nil
+	}
+
+	reputation := p2p.NewReputation()
+	reputationApi := api.NewReputationApi(reputation, banRepository, knownPeerRepository, peerApi, client, api.ReputationConfig{
+		BanThreshold: 0,
+		BanDuration:  time.Hour,
+	})
+
+	// when: score is still above threshold
+	assert.NoError(t, reputationApi.Penalize("1", p2p.BadBlock))
+
+	// then
+	assert.Equal(t, 0, len(banRepository.bans))
+	assert.Equal(t, api.ErrEmptyConnectionId, reputationApi.Penalize("", p2p.BadBlock))
+
+	// when: score reaches threshold
+	assert.NoError(t, reputationApi.Penalize("1", p2p.BadBlock))
+
+	// then
+	assert.Equal(t, p2p.BadBlock, banRepository.bans["1"].Reason)
+	assert.Equal(t, []string{"1"}, removed)
+	assert.Equal(t, []string{"1"}, forgotten)
+	assert.Equal(t, []string{"1"}, closed)
+	assert.Equal(t, p2p.InitialReputation, reputation.GetScore("1"))
+
+	banned, err := reputationApi.IsBanned("1", time.Now())
+	assert.NoError(t, err)
+	assert.True(t, banned)
+}
+
+func TestReputationApi_GetBans(t *testing.T) {
+
+	// given
+	now := time.Now()
+	banRepository := &memBanRepository{bans: map[string]p2p.Ban{
+		"active":  {PeerId: "active", Until: now.Add(time.Minute)},
+		"expired": {PeerId: "expired", Until: now.Add(-time.Minute)},
+	}}
+
+	closed := make([]string, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		closed = append(closed, params.(command.CloseConnection).ConnectionID)
+		return nil
+	}
+
+	reputationApi := api.NewReputationApi(p2p.NewReputation(), banRepository, &mock.MockKnownPeerRepository{}, &mock.MockPeerApi{}, client, api.ReputationConfig{})
+
+	// when
+	bans, err := reputationApi.GetBans(now)
+
+	// then: expired ban is removed
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bans))
+	assert.Equal(t, "active", bans[0].PeerId)
+	assert.Equal(t, 1, len(banRepository.bans))
+
+	// when
+	rejected, err := reputationApi.RejectIfBanned("active", now)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, rejected)
+	assert.Equal(t, []string{"active"}, closed)
+
+	// when
+	assert.NoError(t, reputationApi.ClearBans())
+
+	// then
+	assert.Equal(t, 0, len(banRepository.bans))
+}
@@ -18,8 +18,11 @@ package p2p
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
 )
 
 var ErrEmptyConnectionId = errors.New("empty connection ")
@@ -46,9 +49,11 @@ func (cs *CommunicationService) Dial(ipAddress string) error {
 		Address: ipAddress,
 	}
 
-	cs.client.Call("connection.create", c, func() {})
-
-	return nil
+	return cs.client.Call("connection.create", c, func(_ struct{}, err rpc.Error) {
+		if !err.IsNil() {
+			logger.Error(nil, fmt.Sprintf("[P2P] Fail to dial - address: [%s], err: [%s]", ipAddress, err.Message))
+		}
+	})
 }
 
 //deliver peer leader table to specific peer
@@ -75,5 +80,5 @@ func (cs *CommunicationService) DeliverPLTable(connectionId string, peerLeaderTa
 
 	grpcDeliverCommand.RecipientList = append(grpcDeliverCommand.RecipientList, connectionId)
 
-	return cs.client.Call("message.deliver", grpcDeliverCommand, func() {})
+	return cs.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
 }
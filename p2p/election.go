@@ -17,31 +17,47 @@
 package p2p
 
 import (
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/it-chain/engine/common/logger"
 )
 
+var ErrDuplicateVote = errors.New("peer already voted in this term")
+
 const Candidate = "Candidate"
 const Ticking = "Ticking"
+const Elected = "Elected"
+
+// election timeout 범위(ms). leader의 heartbeat를 이 시간 동안 받지 못하면 새 term을 시작한다.
+const ElectionTimeoutMin = 150
+const ElectionTimeoutMax = 300
 
 type Election struct {
-	ipAddress string
-	candidate *Peer  // candidate peer to be leader later
+	peerId    string // id of this node
+	term      uint64 // current term
+	candidate *Peer  // peer voted for in current term
+	leaderId  string // leader of current term
 	leftTime  int    //left time in millisecond
-	state     string //candidate, ticking
+	state     string //candidate, ticking, elected
 	voteCount int
+	voters    map[string]bool // peers voted for this node in current term
+	offset    int             // peer latency 만큼 늘린 election timeout(ms)
 	mux       sync.Mutex
 }
 
-func NewElection(ipAddress string, leftTime int, state string, voteCount int) Election {
+func NewElection(peerId string, leftTime int, state string, voteCount int) Election {
 
 	return Election{
-		ipAddress: ipAddress,
+		peerId:    peerId,
+		term:      0,
 		candidate: &Peer{},
+		leaderId:  "",
 		leftTime:  leftTime,
 		state:     state,
 		voteCount: voteCount,
+		voters:    make(map[string]bool),
 		mux:       sync.Mutex{},
 	}
 }
@@ -55,22 +71,36 @@ func (election *Election) SetLeftTime() int {
 }
 
 func (election *Election) ResetLeftTime() {
-	logger.Infof(nil, "reset lefttime from %s", election.ipAddress)
 
 	election.mux.Lock()
 	defer election.mux.Unlock()
 
-	election.leftTime = GenRandomInRange(290, 300)
+	election.leftTime = GenRandomInRange(ElectionTimeoutMin, ElectionTimeoutMax) + election.offset
+}
+
+// peer 사이의 RTT가 길면 heartbeat가 도착하기 전에 timeout 되지 않도록 election timeout을 RTT의 2배 만큼 늘린다.
+func (election *Election) SetNetworkLatency(latency time.Duration) {
+
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
+	election.offset = int(2 * latency / time.Millisecond)
 }
 
-//count down left time by tick millisecond  until 0
-func (election *Election) CountDownLeftTimeBy(tick int) {
+//count down left time by tick millisecond until 0 and return left time
+func (election *Election) CountDownLeftTimeBy(tick int) int {
+
+	election.mux.Lock()
+	defer election.mux.Unlock()
 
-	if election.leftTime == 0 {
-		return
+	if election.leftTime <= tick {
+		election.leftTime = 0
+		return 0
 	}
 
 	election.leftTime = election.leftTime - tick
+
+	return election.leftTime
 }
 
 func (election *Election) SetState(state string) {
@@ -93,6 +123,9 @@ func (election *Election) GetState() string {
 
 func (election *Election) GetLeftTime() int {
 
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
 	return election.leftTime
 }
 
@@ -110,6 +143,7 @@ func (election *Election) ResetVoteCount() {
 	defer election.mux.Unlock()
 
 	election.voteCount = 0
+	election.voters = make(map[string]bool)
 }
 
 func (election *Election) CountUp() {
@@ -121,14 +155,165 @@ func (election *Election) CountUp() {
 }
 
 func (e *Election) SetCandidate(peer *Peer) {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
 	e.candidate = peer
 }
 
 func (e *Election) GetCandidate() *Peer {
 
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
 	return e.candidate
 }
 
-func (e *Election) GetIpAddress() string {
-	return e.ipAddress
+func (e *Election) GetPeerId() string {
+	return e.peerId
+}
+
+func (e *Election) GetTerm() uint64 {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.term
+}
+
+func (e *Election) GetLeaderId() string {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.leaderId
+}
+
+// term을 하나 올리고 자기 자신에게 투표한 candidate가 된다.
+func (e *Election) StartNewTerm() uint64 {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	e.term = e.term + 1
+	e.state = Candidate
+	e.candidate = &Peer{PeerId: PeerId{Id: e.peerId}}
+	e.leaderId = ""
+	e.voteCount = 0
+	e.voters = make(map[string]bool)
+	e.leftTime = GenRandomInRange(ElectionTimeoutMin, ElectionTimeoutMax) + e.offset
+
+	logger.Infof(nil, "[P2P] Start new term - term: [%d]", e.term)
+
+	return e.term
+}
+
+// 더 큰 term을 받으면 그 term의 follower가 된다. term이 바뀌었으면 true를 반환한다.
+func (e *Election) UpdateTerm(term uint64) bool {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.updateTerm(term)
+}
+
+func (e *Election) updateTerm(term uint64) bool {
+
+	if term <= e.term {
+		return false
+	}
+
+	e.term = term
+	e.state = Ticking
+	e.candidate = &Peer{}
+	e.leaderId = ""
+	e.voteCount = 0
+	e.voters = make(map[string]bool)
+
+	return true
+}
+
+// term 당 한 번만 투표한다. 이미 같은 candidate에게 투표했다면 다시 true를 반환한다.
+func (e *Election) GrantVote(candidateId string, term uint64) bool {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if term < e.term {
+		return false
+	}
+
+	e.updateTerm(term)
+
+	if e.candidate.PeerId.Id != "" && e.candidate.PeerId.Id != candidateId {
+		return false
+	}
+
+	e.candidate = &Peer{PeerId: PeerId{Id: candidateId}}
+	e.leftTime = GenRandomInRange(ElectionTimeoutMin, ElectionTimeoutMax) + e.offset
+
+	return true
+}
+
+// 현재 term에서 받은 vote를 센다. candidate가 아니거나 term이 다르면 무시한다.
+// 같은 peer가 한 term에 두 번 이상 vote 하면 ErrDuplicateVote를 반환한다.
+func (e *Election) CountVote(voterId string, term uint64) (bool, error) {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if e.state != Candidate || term != e.term {
+		return false, nil
+	}
+
+	if e.voters[voterId] {
+		return false, ErrDuplicateVote
+	}
+
+	e.voters[voterId] = true
+	e.voteCount = e.voteCount + 1
+
+	return true, nil
+}
+
+// 과반의 vote를 받은 candidate가 leader가 된다.
+func (e *Election) BecomeLeader(term uint64) bool {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if e.state != Candidate || term != e.term {
+		return false
+	}
+
+	e.state = Elected
+	e.leaderId = e.peerId
+
+	return true
+}
+
+// term의 leader를 받아들이고 election timeout을 초기화 한다.
+// 이전 term의 leader면 false, 새로운 leader면 true를 반환한다.
+func (e *Election) AcceptLeader(leaderId string, term uint64) (accepted bool, changed bool) {
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if term < e.term {
+		return false, false
+	}
+
+	e.updateTerm(term)
+
+	e.state = Ticking
+	e.leftTime = GenRandomInRange(ElectionTimeoutMin, ElectionTimeoutMax) + e.offset
+
+	if e.leaderId == leaderId {
+		return true, false
+	}
+
+	e.leaderId = leaderId
+
+	return true, true
 }
@@ -17,6 +17,7 @@
 package p2p
 
 import (
+	"fmt"
 	"math/rand"
 	"sync"
 	"time"
@@ -24,167 +25,241 @@ import (
 	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
 	"github.com/rs/xid"
 )
 
+// leader는 election timeout보다 짧은 주기로 heartbeat를 보낸다.
+const HeartbeatInterval = 50 * time.Millisecond
+
+// 선출된 leader를 peer table에 반영하고 LeaderUpdated 이벤트를 publish 한다. (api.LeaderApi)
+// 자신이 leader가 되면 SignLeader로 받은 vote와 함께 서명된 leader를 만들어 알린다.
+type LeaderUpdater interface {
+	SignLeader(term uint64, votes []Vote) (Leader, error)
+	SignVote(leaderId string, term uint64) (Vote, error)
+	UpdateLeaderWithTerm(leader Leader) error
+}
+
 type ElectionService struct {
 	mux              sync.Mutex
 	Election         *Election
 	peerQueryService PeerQueryService
 	client           Client
+	leaderUpdater    LeaderUpdater
+	leader           Leader        // signed leader of current term announced by this node
+	votes            []Vote        // 이 node가 candidate인 term에 받은 vote. leader가 되면 선출의 증명으로 보낸다.
+	hasLeader        bool          // 시작한 뒤 leader를 받아들인 적이 있는지
+	minPeers         int           // 연결된 peer가 이 수보다 적으면 election을 시작하지 않는다.
+	reset            chan struct{} // leader 메세지를 받거나 투표하면 election timer를 다시 시작한다.
+	quit             chan struct{}
 }
 
-func NewElectionService(election *Election, peerQueryService PeerQueryService, client Client) ElectionService {
+// minPeers가 0이면 peer가 없는 node도 혼자 leader가 된다.
+func NewElectionService(election *Election, peerQueryService PeerQueryService, client Client, leaderUpdater LeaderUpdater, minPeers int) ElectionService {
 
 	return ElectionService{
 		mux:              sync.Mutex{},
 		Election:         election,
 		peerQueryService: peerQueryService,
 		client:           client,
+		leaderUpdater:    leaderUpdater,
+		minPeers:         minPeers,
+		reset:            make(chan struct{}, 1),
+		quit:             make(chan struct{}),
 	}
 }
 
-func (es *ElectionService) Vote(connectionId string) error {
-
-	peer, _ := es.peerQueryService.FindPeerById(PeerId{Id: connectionId})
-
-	candidate := es.Election.GetCandidate()
-
-	// if peer has already received request vote message return nil
-	if candidate.PeerId.Id == "" {
+// vote to candidate once per term
+func (es *ElectionService) Vote(connectionId string, term uint64) error {
 
-		es.Election.SetCandidate(&peer)
-	} else {
+	if !es.Election.GrantVote(connectionId, term) {
+		logger.Info(nil, fmt.Sprintf("[P2P] Vote rejected - candidate: [%s], term: [%d]", connectionId, term))
 		return nil
 	}
 
-	//if leftTime >0, reset left time and send VoteLeaderMessage
+	es.resetElectionTimer()
 
-	es.Election.ResetLeftTime()
+	vote, err := es.leaderUpdater.SignVote(connectionId, term)
 
-	voteLeaderMessage := VoteMessage{}
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to sign vote - candidate: [%s], term: [%d], err: [%s]", connectionId, term, err))
+		return err
+	}
+
+	voteLeaderMessage := VoteMessage{
+		Term: term,
+		Vote: vote,
+	}
 
 	grpcDeliverCommand, _ := CreateGrpcDeliverCommand("VoteLeaderProtocol", voteLeaderMessage)
 	grpcDeliverCommand.RecipientList = append(grpcDeliverCommand.RecipientList, connectionId)
 
-	es.client.Call("message.deliver", grpcDeliverCommand, func() {})
+	es.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
 
 	return nil
 }
 
-// broadcast leader to other peers
+// broadcast leader of current term to other peers
 func (es *ElectionService) BroadcastLeader(peer Peer) error {
 	logger.Info(nil, "broadcast leader!")
 
-	updateLeaderMessage := UpdateLeaderMessage{
-		Peer: peer,
+	return es.deliverToPeers("UpdateLeaderProtocol", UpdateLeaderMessage{
+		Term:   es.Election.GetTerm(),
+		Peer:   peer,
+		Leader: es.getLeader(),
+	})
+}
+
+// leader는 heartbeat로 자신의 term을 알린다.
+func (es *ElectionService) SendHeartbeat() error {
+
+	return es.deliverToPeers("HeartbeatProtocol", UpdateLeaderMessage{
+		Term:   es.Election.GetTerm(),
+		Peer:   es.self(),
+		Leader: es.getLeader(),
+	})
+}
+
+//count vote of term and broadcast leader when voted by majority
+// vote의 서명은 호출하기 전에 검증되어야 한다.
+func (es *ElectionService) DecideToBeLeader(vote Vote) error {
+
+	term := vote.Term
+
+	if vote.LeaderId != es.Election.GetPeerId() {
+		return nil
 	}
 
-	grpcDeliverCommand, _ := CreateGrpcDeliverCommand("UpdateLeaderProtocol", updateLeaderMessage)
+	counted, err := es.Election.CountVote(vote.VoterId, term)
 
-	pLTable, _ := es.peerQueryService.GetPLTable()
+	if err != nil {
+		return err
+	}
 
-	for _, peer := range pLTable.PeerTable {
-		grpcDeliverCommand.RecipientList = append(grpcDeliverCommand.RecipientList, peer.PeerId.Id)
+	if !counted {
+		return nil
 	}
 
-	es.client.Call("message.deliver", grpcDeliverCommand, func() {})
+	es.addVote(vote)
 
-	return nil
+	pLTable, _ := es.peerQueryService.GetPLTable()
+
+	// 자기 자신의 vote를 포함해서 과반을 얻어야 한다.
+	if es.Election.GetVoteCount()+1 < es.quorum(pLTable) {
+		return nil
+	}
+
+	return es.becomeLeader(term)
 }
 
-//broadcast leader when voted fully
-func (es *ElectionService) DecideToBeLeader() error {
+// 같거나 더 큰 term의 leader 메세지를 받으면 follower가 되고, leader가 바뀌었으면 반영한다.
+// leader와 vote의 서명은 호출하기 전에 검증되어야 한다.
+// 알려진 peer의 과반이 투표하지 않았거나 term이 local term 보다 너무 크면 받아들이지 않는다.
+func (es *ElectionService) UpdateLeader(leader Leader) error {
 
-	logger.Infof(nil, "current state", es.Election)
-	//	1. if candidate, reset left time
-	//	2. count up
-	if es.Election.GetState() == Candidate {
+	leaderId := leader.GetID()
+	term := leader.Term
 
-		es.Election.CountUp()
+	pLTable, err := es.peerQueryService.GetPLTable()
+
+	if err != nil {
+		return err
 	}
 
-	//	3. if counted is same with num of peer-1 set leader and publish
-	pLTable, _ := es.peerQueryService.GetPLTable()
-	numOfPeers := len(pLTable.PeerTable)
+	if !leader.IsVotedByMajority(pLTable, es.Election.GetPeerId()) {
+		logger.Info(nil, fmt.Sprintf("[P2P] Leader without majority votes rejected - leader: [%s], term: [%d]", leaderId, term))
+		return ErrNotElectedLeader
+	}
 
-	if es.Election.GetVoteCount() == numOfPeers-1 {
+	if !leader.IsTermInRange(es.Election.GetTerm(), es.getHasLeader()) {
+		logger.Info(nil, fmt.Sprintf("[P2P] Leader with too high term rejected - leader: [%s], term: [%d], local term: [%d]", leaderId, term, es.Election.GetTerm()))
+		return ErrLeaderTermTooHigh
+	}
 
-		peer := Peer{
-			PeerId:    PeerId{Id: ""},
-			IpAddress: es.Election.ipAddress,
-		}
+	accepted, changed := es.Election.AcceptLeader(leaderId, term)
 
-		if err := es.BroadcastLeader(peer); err != nil {
-			return err
-		}
+	if !accepted {
+		logger.Info(nil, fmt.Sprintf("[P2P] Stale leader ignored - leader: [%s], term: [%d]", leaderId, term))
+		return nil
 	}
 
-	return nil
-}
+	es.setHasLeader()
+	es.resetElectionTimer()
 
-func (es *ElectionService) ElectLeaderWithRaft() {
+	if !changed {
+		return nil
+	}
 
-	//1. Start random timeout
-	//2. timed out! alter state to 'candidate'
-	//3. while ticking, count down leader repo left time
-	//4. Send message having 'RequestVoteProtocol' to other node
-	go func() {
-		es.Election.state = Ticking
+	logger.Info(nil, fmt.Sprintf("[P2P] Leader updated - leader: [%s], term: [%d]", leaderId, term))
 
-		es.Election.leftTime = GenRandomInRange(150, 300)
+	return es.leaderUpdater.UpdateLeaderWithTerm(leader)
+}
 
-		timeout := time.After(time.Duration(es.Election.leftTime) * time.Millisecond)
-		tick := time.Tick(1 * time.Millisecond)
-		end := true
-		for end {
-			select {
+// start election loop
+// follower는 election timeout 동안 heartbeat를 받지 못하면 새 term을 시작하고,
+// leader는 HeartbeatInterval 마다 heartbeat를 보낸다. Stop을 호출할 때 까지 계속된다.
+func (es *ElectionService) ElectLeaderWithRaft() {
 
-			case <-timeout:
-				logger.Info(nil, "timed out!")
-				// when timed out
-				// 1. if state is ticking, be candidate and request vote
-				// 2. if state is candidate, reset state and left time
-				if es.Election.GetState() == Ticking {
-					logger.Infof(nil, "candidate process: %v", es.Election.candidate)
-					es.Election.SetState(Candidate)
+	es.Election.ResetLeftTime()
 
-					pLTable, _ := es.peerQueryService.GetPLTable()
+	go func() {
+		timeout := time.NewTimer(es.electionTimeout())
+		heartbeat := time.NewTicker(HeartbeatInterval)
+		defer timeout.Stop()
+		defer heartbeat.Stop()
 
-					peerList := pLTable.PeerTable
+		for {
+			select {
 
-					connectionIds := make([]string, 0)
+			case <-es.quit:
+				return
 
-					for _, peer := range peerList {
-						connectionIds = append(connectionIds, peer.PeerId.Id)
+			case <-es.reset:
+				if !timeout.Stop() {
+					select {
+					case <-timeout.C:
+					default:
 					}
+				}
+				timeout.Reset(es.electionTimeout())
 
-					es.RequestVote(connectionIds)
-
-				} else if es.Election.GetState() == Candidate {
-					//reset time and state chane candidate -> ticking when timed in candidate state
-					es.Election.ResetLeftTime()
-					es.Election.SetState(Ticking)
+			case <-timeout.C:
+				if es.Election.GetState() != Elected {
+					logger.Info(nil, "timed out!")
+					es.startElection()
 				}
+				timeout.Reset(es.electionTimeout())
 
-			case <-tick:
-				// count down left time while ticking
-				es.Election.CountDownLeftTimeBy(1)
-			case <-time.After(5 * time.Second):
-				end = false
-			}
+			case <-heartbeat.C:
+				es.applyNetworkLatency()
 
+				if es.Election.GetState() == Elected {
+					es.SendHeartbeat()
+				}
+			}
 		}
 	}()
 }
 
+func (es *ElectionService) Stop() {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	select {
+	case <-es.quit:
+	default:
+		close(es.quit)
+	}
+}
+
 func (es *ElectionService) RequestVote(connectionIds []string) error {
 
-	// 0. be candidate
-	es.Election.state = Candidate
-	// 1. create request vote message
+	// 1. create request vote message of current term
 	// 2. send message
-	requestVoteMessage := RequestVoteMessage{}
+	requestVoteMessage := RequestVoteMessage{
+		Term: es.Election.GetTerm(),
+	}
 
 	grpcDeliverCommand, _ := CreateGrpcDeliverCommand("RequestVoteProtocol", requestVoteMessage)
 
@@ -193,11 +268,211 @@ func (es *ElectionService) RequestVote(connectionIds []string) error {
 		grpcDeliverCommand.RecipientList = append(grpcDeliverCommand.RecipientList, connectionId)
 	}
 
-	es.client.Call("message.deliver", grpcDeliverCommand, func() {})
+	es.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
+
+	return nil
+}
+
+// timed out! start new term and request vote to other peers
+func (es *ElectionService) startElection() {
+
+	pLTable, _ := es.peerQueryService.GetPLTable()
+
+	// bootstrap 중이라 peer table이 아직 채워지지 않은 node가 혼자 leader가 되지 않도록
+	// minPeers 만큼 연결될 때 까지 기다린다.
+	if peerCount := len(es.otherPeerIds(pLTable)); peerCount < es.minPeers {
+		logger.Info(nil, fmt.Sprintf("[P2P] Election deferred - peers: [%d], min peers: [%d]", peerCount, es.minPeers))
+		es.Election.ResetLeftTime()
+		return
+	}
+
+	term := es.Election.StartNewTerm()
+	es.clearVotes()
+
+	// 혼자 남은 node는 바로 leader가 된다. (minPeers가 0인 경우)
+	if es.quorum(pLTable) <= 1 {
+		es.becomeLeader(term)
+		return
+	}
+
+	es.RequestVote(es.otherPeerIds(pLTable))
+}
+
+// timer는 election loop에서만 다루므로 channel로 알린다. 이미 알렸으면 무시한다.
+func (es *ElectionService) resetElectionTimer() {
+
+	select {
+	case es.reset <- struct{}{}:
+	default:
+	}
+}
+
+func (es *ElectionService) electionTimeout() time.Duration {
+
+	return time.Duration(es.Election.GetLeftTime()) * time.Millisecond
+}
+
+func (es *ElectionService) becomeLeader(term uint64) error {
+
+	if !es.Election.BecomeLeader(term) {
+		return nil
+	}
+
+	logger.Info(nil, fmt.Sprintf("[P2P] Elected as leader - term: [%d]", term))
+
+	leader, err := es.leaderUpdater.SignLeader(term, es.getVotes(term))
+
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to sign leader - term: [%d], err: [%s]", term, err))
+		return err
+	}
+
+	es.setLeader(leader)
+
+	if err := es.leaderUpdater.UpdateLeaderWithTerm(leader); err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to update leader - term: [%d], err: [%s]", term, err))
+	}
+
+	return es.BroadcastLeader(es.self())
+}
+
+func (es *ElectionService) deliverToPeers(protocol string, body interface{}) error {
+
+	grpcDeliverCommand, err := CreateGrpcDeliverCommand(protocol, body)
+
+	if err != nil {
+		return err
+	}
+
+	pLTable, _ := es.peerQueryService.GetPLTable()
+
+	grpcDeliverCommand.RecipientList = append(grpcDeliverCommand.RecipientList, es.otherPeerIds(pLTable)...)
+
+	es.client.Call("message.deliver", grpcDeliverCommand, func(_ struct{}, err rpc.Error) {})
 
 	return nil
 }
 
+// 가장 느린 peer의 RTT를 election timeout에 반영한다.
+func (es *ElectionService) applyNetworkLatency() {
+
+	pLTable, err := es.peerQueryService.GetPLTable()
+
+	if err != nil {
+		return
+	}
+
+	var latency time.Duration
+
+	for _, peer := range pLTable.PeerTable {
+		if peer.Latency > latency {
+			latency = peer.Latency
+		}
+	}
+
+	es.Election.SetNetworkLatency(latency)
+}
+
+// 과반 수. peer table에 자기 자신이 있든 없든 한 번만 센다.
+func (es *ElectionService) quorum(pLTable PLTable) int {
+
+	numOfNodes := len(es.otherPeerIds(pLTable)) + 1
+
+	return numOfNodes/2 + 1
+}
+
+func (es *ElectionService) otherPeerIds(pLTable PLTable) []string {
+
+	peerIds := make([]string, 0)
+
+	for _, peer := range pLTable.PeerTable {
+		if es.isSelf(peer) {
+			continue
+		}
+
+		peerIds = append(peerIds, peer.PeerId.Id)
+	}
+
+	return peerIds
+}
+
+func (es *ElectionService) getLeader() Leader {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	return es.leader
+}
+
+func (es *ElectionService) setLeader(leader Leader) {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	es.leader = leader
+}
+
+func (es *ElectionService) addVote(vote Vote) {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	es.votes = append(es.votes, vote)
+}
+
+// term에 받은 vote만 반환한다.
+func (es *ElectionService) getVotes(term uint64) []Vote {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	votes := make([]Vote, 0)
+
+	for _, vote := range es.votes {
+		if vote.Term == term {
+			votes = append(votes, vote)
+		}
+	}
+
+	return votes
+}
+
+func (es *ElectionService) clearVotes() {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	es.votes = nil
+}
+
+func (es *ElectionService) getHasLeader() bool {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	return es.hasLeader
+}
+
+func (es *ElectionService) setHasLeader() {
+
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	es.hasLeader = true
+}
+
+func (es *ElectionService) isSelf(peer Peer) bool {
+
+	return peer.PeerId.Id == es.Election.GetPeerId()
+}
+
+func (es *ElectionService) self() Peer {
+
+	return Peer{
+		PeerId: PeerId{Id: es.Election.GetPeerId()},
+	}
+}
+
 func CreateGrpcDeliverCommand(protocol string, body interface{}) (command.DeliverGrpc, error) {
 
 	data, err := common.Serialize(body)
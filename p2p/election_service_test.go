@@ -63,7 +63,7 @@ func TestElectionService_Vote(t *testing.T) {
 
 		t.Logf("before vote check state: %v", electionServiceOf2.Election.GetState())
 		t.Logf("election of 2: %v", electionServiceOf2.Election)
-		electionServiceOf1.Vote("2")
+		electionServiceOf1.Vote("2", electionServiceOf2.Election.GetTerm())
 		t.Logf("after vote check state: %v", electionServiceOf2.Election.GetState())
 
 		time.Sleep(5 * time.Second)
@@ -104,6 +104,7 @@ func TestElectionService_RequestVote(t *testing.T) {
 		t.Logf("before vote check state: %v", electionServiceOf1.Election.GetState())
 		t.Logf("election of 1: %v", electionServiceOf1.Election)
 
+		electionServiceOf1.Election.StartNewTerm()
 		electionServiceOf1.RequestVote([]string{"2"})
 		t.Logf("after vote check state: %v", electionServiceOf1.Election.GetState())
 
@@ -161,7 +162,7 @@ func TestElectionService_DecideToBeLeader(t *testing.T) {
 		message := p2p.UpdateLeaderMessage{}
 		common.Deserialize(params.(command.DeliverGrpc).Body, &message)
 
-		assert.Equal(t, "this.is.input.address", message.Peer.IpAddress)
+		assert.Equal(t, "this.is.input.address", message.Peer.PeerId.Id)
 
 		assert.Equal(t, "message.deliver", queue)
 		return nil
@@ -177,14 +178,22 @@ func TestElectionService_DecideToBeLeader(t *testing.T) {
 			},
 		}, nil
 	}
+	leaderApi := &mock.MockLeaderApi{}
+	leaderApi.SignLeaderFunc = func(term uint64, votes []p2p.Vote) (p2p.Leader, error) {
+		return p2p.Leader{LeaderId: p2p.LeaderId{Id: "this.is.input.address"}, Term: term, Votes: votes}, nil
+	}
+	leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+		assert.Equal(t, "this.is.input.address", leader.GetID())
+		return nil
+	}
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
 
-		electionService := p2p.NewElectionService(&test.input.election, queryService, client)
+		electionService := p2p.NewElectionService(&test.input.election, queryService, client, leaderApi, 1)
 
 		// when, then
-		err := electionService.DecideToBeLeader()
+		err := electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: electionService.Election.GetPeerId(), Term: 0})
 		assert.NoError(t, err)
 		// when, then
 		count := electionService.Election.GetVoteCount()
@@ -210,10 +219,10 @@ func TestElectionService_DecideToBeLeader_WhenElectionCandiateState(t *testing.T
 
 	client := mock.MockClient{}
 
-	electionService := p2p.NewElectionService(&election, queryService, client)
+	electionService := p2p.NewElectionService(&election, queryService, client, &mock.MockLeaderApi{}, 1)
 
 	// when, then
-	err := electionService.DecideToBeLeader()
+	err := electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: "ip.address", Term: 0})
 	assert.NoError(t, err)
 	// when, then
 	count := electionService.Election.GetVoteCount()
@@ -330,12 +339,123 @@ func TestElectionService_ElectLeaderWithRaft(t *testing.T) {
 	}
 }
 
+func TestElectionService_ElectLeaderWithRaft_MinPeers(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			peerIds  []string
+			minPeers int
+		}
+		output string
+	}{
+		"peer table is empty while bootstrapping": {
+			input: struct {
+				peerIds  []string
+				minPeers int
+			}{peerIds: []string{}, minPeers: 1},
+			output: p2p.Ticking,
+		},
+		"single node network": {
+			input: struct {
+				peerIds  []string
+				minPeers int
+			}{peerIds: []string{}, minPeers: 0},
+			output: p2p.Elected,
+		},
+		"enough peers connected": {
+			input: struct {
+				peerIds  []string
+				minPeers int
+			}{peerIds: []string{"1", "2"}, minPeers: 1},
+			output: p2p.Candidate,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		peerIds := test.input.peerIds
+		queryService := mock.MockPeerQueryService{}
+		queryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+			peerTable := make(map[string]p2p.Peer)
+			for _, id := range peerIds {
+				peerTable[id] = p2p.Peer{PeerId: p2p.PeerId{Id: id}}
+			}
+			return p2p.PLTable{PeerTable: peerTable}, nil
+		}
+
+		client := mock.MockClient{}
+		client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+			return nil
+		}
+
+		leaderApi := &mock.MockLeaderApi{}
+		leaderApi.SignLeaderFunc = func(term uint64, votes []p2p.Vote) (p2p.Leader, error) {
+			return p2p.Leader{LeaderId: p2p.LeaderId{Id: "me"}, Term: term, Votes: votes}, nil
+		}
+		leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+			return nil
+		}
+
+		election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+		electionService := p2p.NewElectionService(&election, queryService, client, leaderApi, test.input.minPeers)
+
+		// when
+		electionService.ElectLeaderWithRaft()
+		time.Sleep(2 * p2p.ElectionTimeoutMax * time.Millisecond)
+		electionService.Stop()
+
+		// then
+		assert.Equal(t, test.output, election.GetState())
+	}
+}
+
+func TestElectionService_ElectLeaderWithRaft_ResetOnHeartbeat(t *testing.T) {
+
+	// given
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}},
+				"2": {PeerId: p2p.PeerId{Id: "2"}},
+			},
+		}, nil
+	}
+
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		return nil
+	}
+
+	leaderApi := &mock.MockLeaderApi{}
+	leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+		return nil
+	}
+
+	election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+	electionService := p2p.NewElectionService(&election, queryService, client, leaderApi, 1)
+
+	// when
+	electionService.ElectLeaderWithRaft()
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, electionService.UpdateLeader(p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 1, Votes: votesFor("1", 1, "2")}))
+		time.Sleep(p2p.HeartbeatInterval)
+	}
+	electionService.Stop()
+
+	// then
+	assert.Equal(t, p2p.Ticking, election.GetState())
+	assert.Equal(t, uint64(1), election.GetTerm())
+	assert.Equal(t, "1", election.GetLeaderId())
+}
+
 func TestNewElectionService(t *testing.T) {
 	election := p2p.NewElection("this.is.ip.addres", 30, p2p.Ticking, 123)
 	queryService := mock.MockPeerQueryService{}
 	client := mock.MockClient{}
 
-	electionService := p2p.NewElectionService(&election, queryService, client)
+	electionService := p2p.NewElectionService(&election, queryService, client, &mock.MockLeaderApi{}, 1)
 
 	assert.Equal(t, 123, electionService.Election.GetVoteCount())
 	assert.Equal(t, 30, electionService.Election.GetLeftTime())
@@ -351,3 +471,305 @@ func TestGenRandomInRange(t *testing.T) {
 	t.Logf("%v", v2)
 	t.Logf("%v", v3)
 }
+
+func TestElectionService_Vote_OncePerTerm(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			votes []struct {
+				candidate string
+				term      uint64
+			}
+		}
+		output []string
+	}{
+		"vote once in same term": {
+			input: struct {
+				votes []struct {
+					candidate string
+					term      uint64
+				}
+			}{votes: []struct {
+				candidate string
+				term      uint64
+			}{{"1", 1}, {"2", 1}, {"1", 1}}},
+			output: []string{"1", "1"},
+		},
+		"vote again in new term": {
+			input: struct {
+				votes []struct {
+					candidate string
+					term      uint64
+				}
+			}{votes: []struct {
+				candidate string
+				term      uint64
+			}{{"1", 1}, {"2", 2}, {"3", 1}}},
+			output: []string{"1", "2"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+		voted := make([]string, 0)
+
+		client := mock.MockClient{}
+		client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+			deliverGrpc := params.(command.DeliverGrpc)
+			assert.Equal(t, "VoteLeaderProtocol", deliverGrpc.Protocol)
+			voted = append(voted, deliverGrpc.RecipientList...)
+			return nil
+		}
+
+		leaderApi := &mock.MockLeaderApi{}
+		leaderApi.SignVoteFunc = func(leaderId string, term uint64) (p2p.Vote, error) {
+			return p2p.Vote{VoterId: "me", LeaderId: leaderId, Term: term}, nil
+		}
+
+		electionService := p2p.NewElectionService(&election, mock.MockPeerQueryService{}, client, leaderApi, 1)
+
+		// when
+		for _, vote := range test.input.votes {
+			assert.NoError(t, electionService.Vote(vote.candidate, vote.term))
+		}
+
+		// then
+		assert.Equal(t, test.output, voted)
+	}
+}
+
+func TestElectionService_DecideToBeLeader_Majority(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{
+			PeerTable: map[string]p2p.Peer{
+				"me": p2p.Peer{IpAddress: "me.ipAddr", PeerId: p2p.PeerId{Id: "me"}},
+				"1":  p2p.Peer{IpAddress: "1.ipAddr", PeerId: p2p.PeerId{Id: "1"}},
+				"2":  p2p.Peer{IpAddress: "2.ipAddr", PeerId: p2p.PeerId{Id: "2"}},
+				"3":  p2p.Peer{IpAddress: "3.ipAddr", PeerId: p2p.PeerId{Id: "3"}},
+				"4":  p2p.Peer{IpAddress: "4.ipAddr", PeerId: p2p.PeerId{Id: "4"}},
+			},
+		}, nil
+	}
+
+	broadcasted := make([]p2p.UpdateLeaderMessage, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		message := p2p.UpdateLeaderMessage{}
+		common.Deserialize(params.(command.DeliverGrpc).Body, &message)
+		assert.ElementsMatch(t, []string{"1", "2", "3", "4"}, params.(command.DeliverGrpc).RecipientList)
+		broadcasted = append(broadcasted, message)
+		return nil
+	}
+
+	updated := make([]uint64, 0)
+	leaderApi := &mock.MockLeaderApi{}
+	leaderApi.SignLeaderFunc = func(term uint64, votes []p2p.Vote) (p2p.Leader, error) {
+		return p2p.Leader{LeaderId: p2p.LeaderId{Id: "me"}, Term: term, Signature: []byte("sig"), Votes: votes}, nil
+	}
+	leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+		assert.Equal(t, "me", leader.GetID())
+		updated = append(updated, leader.Term)
+		return nil
+	}
+
+	electionService := p2p.NewElectionService(&election, queryService, client, leaderApi, 1)
+	term := election.StartNewTerm()
+
+	// when: vote of previous term is ignored
+	assert.NoError(t, electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: "me", Term: term - 1}))
+
+	// then
+	assert.Equal(t, 0, election.GetVoteCount())
+
+	// when: vote for other candidate is ignored
+	assert.NoError(t, electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: "2", Term: term}))
+
+	// then
+	assert.Equal(t, 0, election.GetVoteCount())
+
+	// when: 2 votes with own vote are majority of 5 nodes
+	assert.NoError(t, electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: "me", Term: term}))
+	assert.Equal(t, p2p.Candidate, election.GetState())
+
+	// duplicate vote of same peer is not counted
+	assert.Equal(t, p2p.ErrDuplicateVote, electionService.DecideToBeLeader(p2p.Vote{VoterId: "1", LeaderId: "me", Term: term}))
+	assert.Equal(t, p2p.Candidate, election.GetState())
+
+	assert.NoError(t, electionService.DecideToBeLeader(p2p.Vote{VoterId: "2", LeaderId: "me", Term: term}))
+
+	// then
+	assert.Equal(t, p2p.Elected, election.GetState())
+	assert.Equal(t, []uint64{term}, updated)
+	assert.Equal(t, 1, len(broadcasted))
+	assert.Equal(t, term, broadcasted[0].Term)
+	assert.Equal(t, "me", broadcasted[0].Peer.PeerId.Id)
+	assert.Equal(t, []byte("sig"), broadcasted[0].Leader.Signature)
+	assert.Equal(t, votesFor("me", term, "1", "2"), broadcasted[0].Leader.Votes)
+
+	// when: late vote after elected
+	assert.NoError(t, electionService.DecideToBeLeader(p2p.Vote{VoterId: "3", LeaderId: "me", Term: term}))
+
+	// then
+	assert.Equal(t, 1, len(broadcasted))
+}
+
+func TestElectionService_UpdateLeader(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			leaderId string
+			term     uint64
+			voterIds []string
+		}
+		output struct {
+			updated bool
+			term    uint64
+			err     error
+		}
+	}{
+		"leader of new term": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 4, voterIds: []string{"1", "3"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: true, term: 4},
+		},
+		"new leader of current term": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 3, voterIds: []string{"1", "3"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: true, term: 3},
+		},
+		"heartbeat of current leader": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "1", term: 3, voterIds: []string{"2", "3"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: false, term: 3},
+		},
+		"leader of stale term": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 2, voterIds: []string{"1", "3"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: false, term: 3},
+		},
+		"self elected leader of huge term": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 1 << 62, voterIds: []string{}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: false, term: 3, err: p2p.ErrNotElectedLeader},
+		},
+		"leader voted by unknown peers": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 4, voterIds: []string{"x", "y"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: false, term: 3, err: p2p.ErrNotElectedLeader},
+		},
+		"leader of too high term": {
+			input: struct {
+				leaderId string
+				term     uint64
+				voterIds []string
+			}{leaderId: "2", term: 3 + p2p.MaxLeaderTermGap + 1, voterIds: []string{"1", "3"}},
+			output: struct {
+				updated bool
+				term    uint64
+				err     error
+			}{updated: false, term: 3, err: p2p.ErrLeaderTermTooHigh},
+		},
+	}
+
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}},
+				"2": {PeerId: p2p.PeerId{Id: "2"}},
+				"3": {PeerId: p2p.PeerId{Id: "3"}},
+			},
+		}, nil
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given: leader 1 of term 3 is accepted
+		election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+
+		updated := false
+		leaderApi := &mock.MockLeaderApi{}
+		leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+			updated = true
+			return nil
+		}
+
+		electionService := p2p.NewElectionService(&election, queryService, mock.MockClient{}, leaderApi, 1)
+		assert.NoError(t, electionService.UpdateLeader(p2p.Leader{LeaderId: p2p.LeaderId{Id: "1"}, Term: 3, Votes: votesFor("1", 3, "2", "3")}))
+
+		updated = false
+		leaderApi.UpdateLeaderWithTermFunc = func(leader p2p.Leader) error {
+			assert.Equal(t, test.input.leaderId, leader.GetID())
+			assert.Equal(t, test.input.term, leader.Term)
+			updated = true
+			return nil
+		}
+
+		// when
+		err := electionService.UpdateLeader(p2p.Leader{LeaderId: p2p.LeaderId{Id: test.input.leaderId}, Term: test.input.term, Votes: votesFor(test.input.leaderId, test.input.term, test.input.voterIds...)})
+
+		// then
+		assert.Equal(t, test.output.err, err)
+		assert.Equal(t, test.output.updated, updated)
+		assert.Equal(t, test.output.term, election.GetTerm())
+	}
+}
+
+func votesFor(leaderId string, term uint64, voterIds ...string) []p2p.Vote {
+
+	votes := make([]p2p.Vote, 0)
+
+	for _, voterId := range voterIds {
+		votes = append(votes, p2p.Vote{VoterId: voterId, LeaderId: leaderId, Term: term})
+	}
+
+	return votes
+}
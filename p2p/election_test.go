@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElection_StartNewTerm(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 30, p2p.Ticking, 3)
+
+	// when
+	term := election.StartNewTerm()
+
+	// then
+	assert.Equal(t, uint64(1), term)
+	assert.Equal(t, p2p.Candidate, election.GetState())
+	assert.Equal(t, 0, election.GetVoteCount())
+	assert.Equal(t, "me", election.GetCandidate().PeerId.Id)
+	assert.True(t, election.GetLeftTime() >= p2p.ElectionTimeoutMin)
+
+	// candidate는 자기 자신에게 투표했으므로 같은 term에 다른 peer에게 투표하지 않는다.
+	assert.False(t, election.GrantVote("other", term))
+}
+
+func TestElection_SetNetworkLatency(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+
+	// when
+	election.SetNetworkLatency(100 * time.Millisecond)
+	election.ResetLeftTime()
+
+	// then
+	assert.True(t, election.GetLeftTime() >= p2p.ElectionTimeoutMin+200)
+	assert.True(t, election.GetLeftTime() < p2p.ElectionTimeoutMax+200)
+}
+
+func TestElection_UpdateTerm(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+	term := election.StartNewTerm()
+	election.CountUp()
+
+	// when, then
+	assert.False(t, election.UpdateTerm(term))
+	assert.Equal(t, p2p.Candidate, election.GetState())
+
+	// when, then
+	assert.True(t, election.UpdateTerm(term+1))
+	assert.Equal(t, p2p.Ticking, election.GetState())
+	assert.Equal(t, 0, election.GetVoteCount())
+	assert.Equal(t, "", election.GetCandidate().PeerId.Id)
+}
+
+func TestElection_BecomeLeader(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 30, p2p.Ticking, 0)
+
+	// when, then: follower cannot be leader
+	assert.False(t, election.BecomeLeader(0))
+
+	// when, then
+	term := election.StartNewTerm()
+	assert.False(t, election.BecomeLeader(term+1))
+	assert.True(t, election.BecomeLeader(term))
+	assert.Equal(t, p2p.Elected, election.GetState())
+	assert.Equal(t, "me", election.GetLeaderId())
+
+	// when, then: leader steps down when it meets leader of higher term
+	accepted, changed := election.AcceptLeader("other", term+1)
+	assert.True(t, accepted)
+	assert.True(t, changed)
+	assert.Equal(t, p2p.Ticking, election.GetState())
+	assert.Equal(t, "other", election.GetLeaderId())
+}
+
+func TestElection_CountDownLeftTimeBy(t *testing.T) {
+	// given
+	election := p2p.NewElection("me", 3, p2p.Ticking, 0)
+
+	// when, then
+	assert.Equal(t, 1, election.CountDownLeftTimeBy(2))
+	assert.Equal(t, 0, election.CountDownLeftTimeBy(2))
+	assert.Equal(t, 0, election.CountDownLeftTimeBy(1))
+}
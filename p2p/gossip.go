@@ -0,0 +1,134 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrInvalidGossipSignature = errors.New("invalid gossip signature")
+
+const GossipProtocol = "GossipProtocol"
+
+// gossip으로 전달되는 메세지. Origin은 처음 broadcast 한 node의 id 이다.
+// 여러 peer를 거쳐 전달되므로 Origin이 자신의 key로 서명하고, 받은 node는 서명으로 Origin을 확인한다.
+type GossipMessage struct {
+	MessageId string
+	Origin    string
+	Protocol  string
+	Body      []byte
+	TTL       int
+	PubKey    []byte // PEM encoded public key of origin
+	Signature []byte
+}
+
+// origin이 서명하는 값. 전달될 때 마다 바뀌는 TTL은 제외한다.
+func (m GossipMessage) Digest() []byte {
+
+	hash := sha256.New()
+	hash.Write([]byte(fmt.Sprintf("%s:%s:%s:", m.MessageId, m.Origin, m.Protocol)))
+	hash.Write(m.Body)
+
+	return hash.Sum(nil)
+}
+
+type GossipSigner interface {
+	Sign(message GossipMessage) (GossipMessage, error)
+}
+
+// 서명이 맞는지, 서명한 key가 Origin의 id와 맞는지 검증한다.
+type GossipVerifier interface {
+	Verify(message GossipMessage) error
+}
+
+// 이미 받은 gossip 메세지를 기억해서 같은 메세지를 두 번 처리하거나 전달하지 않도록 한다.
+// cache가 capacity에 도달하면 expiration이 지난 메세지 id 부터 지운다.
+type GossipCache struct {
+	mux        sync.Mutex
+	seen       map[string]time.Time
+	capacity   int
+	expiration time.Duration
+}
+
+func NewGossipCache(capacity int, expiration time.Duration) *GossipCache {
+	return &GossipCache{
+		mux:        sync.Mutex{},
+		seen:       make(map[string]time.Time),
+		capacity:   capacity,
+		expiration: expiration,
+	}
+}
+
+// 처음 보는 메세지면 기록하고 true를 반환한다.
+func (c *GossipCache) Mark(messageId string, now time.Time) bool {
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if seenAt, ok := c.seen[messageId]; ok && now.Sub(seenAt) <= c.expiration {
+		return false
+	}
+
+	if len(c.seen) >= c.capacity {
+		c.prune(now)
+	}
+
+	// 모두 유효하면 가장 오래된 메세지를 지운다.
+	if len(c.seen) >= c.capacity {
+		c.evictOldest()
+	}
+
+	c.seen[messageId] = now
+
+	return true
+}
+
+func (c *GossipCache) Size() int {
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return len(c.seen)
+}
+
+func (c *GossipCache) prune(now time.Time) {
+
+	for messageId, seenAt := range c.seen {
+		if now.Sub(seenAt) > c.expiration {
+			delete(c.seen, messageId)
+		}
+	}
+}
+
+func (c *GossipCache) evictOldest() {
+
+	oldestId := ""
+	var oldest time.Time
+
+	for messageId, seenAt := range c.seen {
+		if oldestId == "" || seenAt.Before(oldest) {
+			oldestId = messageId
+			oldest = seenAt
+		}
+	}
+
+	delete(c.seen, oldestId)
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/logger"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/rs/xid"
+)
+
+var ErrInvalidGossipProtocol = errors.New("gossip message can not wrap p2p protocol")
+
+// full mesh가 아니어도 네트워크 전체에 메세지를 전달하기 위해 gossip을 사용한다.
+// 메세지는 fanout 만큼의 임의의 peer에게 전달되고, 받은 peer는 TTL이 남아있으면 다시 전달한다.
+type GossipService struct {
+	nodeId           string
+	cache            *GossipCache
+	peerQueryService PeerQueryService
+	client           Client
+	publish          Publish
+	signer           GossipSigner
+	verifier         GossipVerifier
+	fanout           int
+	ttl              int
+}
+
+func NewGossipService(nodeId string, cache *GossipCache, peerQueryService PeerQueryService, client Client, publish Publish, signer GossipSigner, verifier GossipVerifier, fanout int, ttl int) *GossipService {
+
+	return &GossipService{
+		nodeId:           nodeId,
+		cache:            cache,
+		peerQueryService: peerQueryService,
+		client:           client,
+		publish:          publish,
+		signer:           signer,
+		verifier:         verifier,
+		fanout:           fanout,
+		ttl:              ttl,
+	}
+}
+
+// 이 node에서 시작하는 gossip
+func (gs *GossipService) Broadcast(messageId string, protocol string, body []byte) error {
+
+	if !isGossipable(protocol) {
+		return ErrInvalidGossipProtocol
+	}
+
+	if messageId == "" {
+		messageId = xid.New().String()
+	}
+
+	message, err := gs.signer.Sign(GossipMessage{
+		MessageId: messageId,
+		Origin:    gs.nodeId,
+		Protocol:  protocol,
+		Body:      body,
+		TTL:       gs.ttl,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	gs.cache.Mark(messageId, time.Now())
+
+	return gs.forward(message, gs.nodeId)
+}
+
+// 다른 peer로 부터 받은 gossip
+// 처음 받은 메세지는 이 node의 component들에게 전달하고, TTL이 남아있으면 다른 peer에게 전달한다.
+// origin의 서명이 맞지 않는 메세지는 전달하지 않는다.
+func (gs *GossipService) HandleGossip(senderId string, message GossipMessage) error {
+
+	if !isGossipable(message.Protocol) {
+		return ErrInvalidGossipProtocol
+	}
+
+	if err := gs.verifier.Verify(message); err != nil {
+		return ErrInvalidGossipSignature
+	}
+
+	if !gs.cache.Mark(message.MessageId, time.Now()) {
+		return nil
+	}
+
+	// gossip으로 받은 메세지의 ConnectionID는 서명으로 확인한 origin node의 id 이다.
+	err := gs.publish(command.ReceiveTopic(message.Protocol), command.ReceiveGrpc{
+		MessageId:    message.MessageId,
+		Body:         message.Body,
+		ConnectionID: message.Origin,
+		Protocol:     message.Protocol,
+	})
+
+	if err != nil {
+		logger.Error(nil, fmt.Sprintf("[P2P] Fail to publish gossip message - message: [%s], err: [%s]", message.MessageId, err.Error()))
+	}
+
+	if message.TTL <= 1 {
+		return nil
+	}
+
+	message.TTL = message.TTL - 1
+
+	return gs.forward(message, senderId, message.Origin)
+}
+
+func (gs *GossipService) forward(message GossipMessage, excludes ...string) error {
+
+	body, err := json.Marshal(message)
+
+	if err != nil {
+		return err
+	}
+
+	recipients := gs.selectPeers(excludes...)
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return gs.client.Call("message.deliver", command.DeliverGrpc{
+		MessageId:     xid.New().String(),
+		RecipientList: recipients,
+		Body:          body,
+		Protocol:      GossipProtocol,
+	}, func(_ struct{}, err rpc.Error) {})
+}
+
+// exclude 되지 않은 peer 중 fanout 만큼을 임의로 고른다.
+func (gs *GossipService) selectPeers(excludes ...string) []string {
+
+	pLTable, _ := gs.peerQueryService.GetPLTable()
+
+	candidates := make([]string, 0)
+
+	for id := range pLTable.PeerTable {
+		if !contains(excludes, id) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	if len(candidates) <= gs.fanout {
+		return candidates
+	}
+
+	selected := make([]string, 0, gs.fanout)
+
+	for _, i := range rand.Perm(len(candidates))[:gs.fanout] {
+		selected = append(selected, candidates[i])
+	}
+
+	return selected
+}
+
+// p2p protocol은 연결된 peer 사이의 상태(handshake, ping, election 등)를 다루므로 gossip으로 전달하지 않는다.
+func isGossipable(protocol string) bool {
+
+	return command.ComponentOf(protocol) != command.P2PComponent
+}
+
+func contains(ids []string, id string) bool {
+
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGossipService_Broadcast(t *testing.T) {
+	// given
+	delivered := make([]command.DeliverGrpc, 0)
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		assert.Equal(t, "message.deliver", queue)
+		delivered = append(delivered, params.(command.DeliverGrpc))
+		return nil
+	}
+
+	gossipService := p2p.NewGossipService("me", p2p.NewGossipCache(100, time.Minute), newGossipQueryService(), client, nil, newGossipSigner(), newGossipVerifier(), 2, 3)
+
+	// when
+	err := gossipService.Broadcast("message1", "BlockProtocol", []byte("block"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(delivered))
+	assert.Equal(t, p2p.GossipProtocol, delivered[0].Protocol)
+	assert.Equal(t, 2, len(delivered[0].RecipientList))
+	assert.NotContains(t, delivered[0].RecipientList, "me")
+
+	message := p2p.GossipMessage{}
+	assert.NoError(t, json.Unmarshal(delivered[0].Body, &message))
+	assert.Equal(t, p2p.GossipMessage{
+		MessageId: "message1",
+		Origin:    "me",
+		Protocol:  "BlockProtocol",
+		Body:      []byte("block"),
+		TTL:       3,
+		Signature: []byte("me"),
+	}, message)
+
+	// when, then: p2p protocol can not be gossiped
+	assert.Equal(t, p2p.ErrInvalidGossipProtocol, gossipService.Broadcast("message2", p2p.GossipProtocol, nil))
+	assert.Equal(t, p2p.ErrInvalidGossipProtocol, gossipService.Broadcast("message3", "HeartbeatProtocol", nil))
+}
+
+func TestGossipService_HandleGossip(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			sender  string
+			message p2p.GossipMessage
+		}
+		output struct {
+			published  int
+			forwarded  int
+			recipients int
+		}
+	}{
+		"forward while ttl remains": {
+			input: struct {
+				sender  string
+				message p2p.GossipMessage
+			}{sender: "1", message: p2p.GossipMessage{MessageId: "m", Origin: "2", Protocol: "BlockProtocol", TTL: 2, Signature: []byte("2")}},
+			output: struct {
+				published  int
+				forwarded  int
+				recipients int
+			}{published: 1, forwarded: 1, recipients: 2},
+		},
+		"stop forwarding at last hop": {
+			input: struct {
+				sender  string
+				message p2p.GossipMessage
+			}{sender: "1", message: p2p.GossipMessage{MessageId: "m", Origin: "2", Protocol: "BlockProtocol", TTL: 1, Signature: []byte("2")}},
+			output: struct {
+				published  int
+				forwarded  int
+				recipients int
+			}{published: 1, forwarded: 0},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		published := make([]command.ReceiveGrpc, 0)
+		publish := func(topic string, data interface{}) error {
+			assert.Equal(t, "message.receive.blockchain.BlockProtocol", topic)
+			published = append(published, data.(command.ReceiveGrpc))
+			return nil
+		}
+
+		forwarded := make([]command.DeliverGrpc, 0)
+		client := mock.MockClient{}
+		client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+			forwarded = append(forwarded, params.(command.DeliverGrpc))
+			return nil
+		}
+
+		gossipService := p2p.NewGossipService("me", p2p.NewGossipCache(100, time.Minute), newGossipQueryService(), client, publish, newGossipSigner(), newGossipVerifier(), 3, 3)
+
+		// when: same message is received twice
+		assert.NoError(t, gossipService.HandleGossip(test.input.sender, test.input.message))
+		assert.NoError(t, gossipService.HandleGossip(test.input.sender, test.input.message))
+
+		// then
+		assert.Equal(t, test.output.published, len(published))
+		assert.Equal(t, "2", published[0].ConnectionID)
+		assert.Equal(t, "BlockProtocol", published[0].Protocol)
+		assert.Equal(t, test.output.forwarded, len(forwarded))
+
+		if test.output.forwarded == 0 {
+			continue
+		}
+
+		// sender and origin are excluded
+		assert.ElementsMatch(t, []string{"3", "4"}, forwarded[0].RecipientList)
+
+		message := p2p.GossipMessage{}
+		assert.NoError(t, json.Unmarshal(forwarded[0].Body, &message))
+		assert.Equal(t, test.input.message.TTL-1, message.TTL)
+	}
+}
+
+func TestGossipService_HandleGossip_Rejected(t *testing.T) {
+	tests := map[string]struct {
+		input  p2p.GossipMessage
+		output error
+	}{
+		"origin is forged": {
+			input:  p2p.GossipMessage{MessageId: "m", Origin: "2", Protocol: "BlockProtocol", TTL: 2, Signature: []byte("1")},
+			output: p2p.ErrInvalidGossipSignature,
+		},
+		"not signed": {
+			input:  p2p.GossipMessage{MessageId: "m", Origin: "2", Protocol: "BlockProtocol", TTL: 2},
+			output: p2p.ErrInvalidGossipSignature,
+		},
+		"p2p protocol": {
+			input:  p2p.GossipMessage{MessageId: "m", Origin: "2", Protocol: "HandshakeProtocol", TTL: 2, Signature: []byte("2")},
+			output: p2p.ErrInvalidGossipProtocol,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		published := 0
+		publish := func(topic string, data interface{}) error {
+			published++
+			return nil
+		}
+
+		forwarded := 0
+		client := mock.MockClient{}
+		client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+			forwarded++
+			return nil
+		}
+
+		cache := p2p.NewGossipCache(100, time.Minute)
+		gossipService := p2p.NewGossipService("me", cache, newGossipQueryService(), client, publish, newGossipSigner(), newGossipVerifier(), 3, 3)
+
+		// when
+		err := gossipService.HandleGossip("1", test.input)
+
+		// then
+		assert.Equal(t, test.output, err)
+		assert.Equal(t, 0, published)
+		assert.Equal(t, 0, forwarded)
+		assert.Equal(t, 0, cache.Size())
+	}
+}
+
+// 테스트에서는 origin의 id를 서명으로 사용한다.
+func newGossipSigner() *mock.MockGossipSigner {
+	signer := &mock.MockGossipSigner{}
+	signer.SignFunc = func(message p2p.GossipMessage) (p2p.GossipMessage, error) {
+		message.Signature = []byte(message.Origin)
+		return message, nil
+	}
+
+	return signer
+}
+
+func newGossipVerifier() *mock.MockGossipVerifier {
+	verifier := &mock.MockGossipVerifier{}
+	verifier.VerifyFunc = func(message p2p.GossipMessage) error {
+		if string(message.Signature) != message.Origin {
+			return p2p.ErrInvalidGossipSignature
+		}
+		return nil
+	}
+
+	return verifier
+}
+
+func newGossipQueryService() mock.MockPeerQueryService {
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetPLTableFunc = func() (p2p.PLTable, error) {
+		return p2p.PLTable{
+			PeerTable: map[string]p2p.Peer{
+				"1": {PeerId: p2p.PeerId{Id: "1"}},
+				"2": {PeerId: p2p.PeerId{Id: "2"}},
+				"3": {PeerId: p2p.PeerId{Id: "3"}},
+				"4": {PeerId: p2p.PeerId{Id: "4"}},
+			},
+		}, nil
+	}
+
+	return queryService
+}
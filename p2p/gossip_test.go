@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGossipCache_Mark(t *testing.T) {
+	// given
+	now := time.Now()
+	cache := p2p.NewGossipCache(2, time.Minute)
+
+	// when, then
+	assert.True(t, cache.Mark("1", now))
+	assert.False(t, cache.Mark("1", now.Add(time.Second)))
+
+	// expired message is treated as new
+	assert.True(t, cache.Mark("1", now.Add(2*time.Minute)))
+
+	// oldest message is evicted when cache is full
+	assert.True(t, cache.Mark("2", now.Add(2*time.Minute+time.Second)))
+	assert.True(t, cache.Mark("3", now.Add(2*time.Minute+2*time.Second)))
+	assert.Equal(t, 2, cache.Size())
+	assert.True(t, cache.Mark("1", now.Add(2*time.Minute+3*time.Second)))
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p
+
+import (
+	"bytes"
+	"errors"
+)
+
+// p2p message 형식이 바뀌면 올린다. 다른 protocol version의 node와는 연결하지 않는다.
+const ProtocolVersion = 1
+
+var ErrNetworkIdMismatch = errors.New("network id mismatch")
+var ErrProtocolVersionMismatch = errors.New("protocol version mismatch")
+var ErrGenesisSealMismatch = errors.New("genesis seal mismatch")
+
+// 연결된 node와 handshake로 교환하는 node의 정보
+// Height는 handshake 할 때의 마지막 block height로, block sync 할 peer를 고르는데 쓰인다.
+type NodeMetadata struct {
+	SoftwareVersion string
+	ProtocolVersion int
+	NetworkId       string
+	GenesisSeal     []byte
+	Height          uint64
+}
+
+// 같은 network이고 같은 genesis block에서 시작한 node인지 확인한다.
+// software version이 달라도 protocol version이 같으면 연결한다.
+func (m NodeMetadata) CheckCompatible(opposite NodeMetadata) error {
+
+	if m.NetworkId != opposite.NetworkId {
+		return ErrNetworkIdMismatch
+	}
+
+	if m.ProtocolVersion != opposite.ProtocolVersion {
+		return ErrProtocolVersionMismatch
+	}
+
+	// genesis block을 아직 만들지 않은 node와는 비교하지 않는다.
+	if len(m.GenesisSeal) != 0 && len(opposite.GenesisSeal) != 0 && !bytes.Equal(m.GenesisSeal, opposite.GenesisSeal) {
+		return ErrGenesisSealMismatch
+	}
+
+	return nil
+}
+
+// handshake에 필요한 chain 정보를 blockchain component로 부터 가져온다.
+type ChainQueryService interface {
+	GetGenesisSeal() ([]byte, error)
+	GetLastHeight() (uint64, error)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeMetadata_CheckCompatible(t *testing.T) {
+
+	mine := p2p.NodeMetadata{SoftwareVersion: "0.1.1", ProtocolVersion: 1, NetworkId: "default", GenesisSeal: []byte("genesis"), Height: 3}
+
+	tests := map[string]struct {
+		input p2p.NodeMetadata
+		err   error
+	}{
+		"same network with other software version and height": {
+			input: p2p.NodeMetadata{SoftwareVersion: "0.1.2", ProtocolVersion: 1, NetworkId: "default", GenesisSeal: []byte("genesis"), Height: 10},
+			err:   nil,
+		},
+		"other network": {
+			input: p2p.NodeMetadata{SoftwareVersion: "0.1.1", ProtocolVersion: 1, NetworkId: "test", GenesisSeal: []byte("genesis")},
+			err:   p2p.ErrNetworkIdMismatch,
+		},
+		"other protocol version": {
+			input: p2p.NodeMetadata{SoftwareVersion: "0.1.1", ProtocolVersion: 2, NetworkId: "default", GenesisSeal: []byte("genesis")},
+			err:   p2p.ErrProtocolVersionMismatch,
+		},
+		"other genesis block": {
+			input: p2p.NodeMetadata{SoftwareVersion: "0.1.1", ProtocolVersion: 1, NetworkId: "default", GenesisSeal: []byte("other")},
+			err:   p2p.ErrGenesisSealMismatch,
+		},
+		"genesis block is not created": {
+			input: p2p.NodeMetadata{SoftwareVersion: "0.1.1", ProtocolVersion: 1, NetworkId: "default"},
+			err:   nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := mine.CheckCompatible(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+// genesis block의 height
+const genesisHeight = 0
+
+// blockchain component의 block repository로 부터 handshake에 필요한 chain 정보를 읽는다.
+type ChainQueryService struct {
+	blockRepository blockchain.BlockRepository
+}
+
+func NewChainQueryService(blockRepository blockchain.BlockRepository) *ChainQueryService {
+	return &ChainQueryService{
+		blockRepository: blockRepository,
+	}
+}
+
+func (s *ChainQueryService) GetGenesisSeal() ([]byte, error) {
+
+	block, err := s.blockRepository.FindByHeight(genesisHeight)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return block.GetSeal(), nil
+}
+
+func (s *ChainQueryService) GetLastHeight() (uint64, error) {
+
+	block, err := s.blockRepository.FindLast()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return block.GetHeight(), nil
+}
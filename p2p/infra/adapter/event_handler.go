@@ -19,6 +19,7 @@ package adapter
 import (
 	"errors"
 	"log"
+	"time"
 
 	"github.com/it-chain/engine/common/event"
 	"github.com/it-chain/engine/p2p"
@@ -33,39 +34,49 @@ type CommunicationApi interface {
 }
 
 type EventHandler struct {
-	communicationApi CommunicationApi // api.CommunicationApi
-	peerApi          api.PeerApi
+	peerApi       api.PeerApi
+	reputationApi ReputationApi
+	handshakeApi  HandshakeApi
 }
 
-func NewEventHandler(communicationApi CommunicationApi, peerApi api.PeerApi) EventHandler {
+func NewEventHandler(peerApi api.PeerApi, reputationApi ReputationApi, handshakeApi HandshakeApi) EventHandler {
 
 	return EventHandler{
-		communicationApi: communicationApi,
-		peerApi:          peerApi,
+		peerApi:       peerApi,
+		reputationApi: reputationApi,
+		handshakeApi:  handshakeApi,
 	}
 }
 
 //handler connection created event
 func (eh *EventHandler) HandleConnCreatedEvent(event event.ConnectionCreated) error {
 
+	//0. ban 된 peer는 peer table에 넣지 않고 연결을 끊는다
+	banned, err := eh.reputationApi.RejectIfBanned(event.ConnectionID, time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if banned {
+		return api.ErrBannedPeer
+	}
+
 	//1. addPeer
 	peer := p2p.Peer{
 		PeerId: p2p.PeerId{
 			Id: event.ConnectionID,
 		},
 		IpAddress: event.Address,
+		Version:   time.Now().UnixNano(),
 	}
 
-	err := eh.peerApi.Save(peer)
-
-	if err != nil {
+	if err := eh.peerApi.Save(peer); err != nil {
 		return err
 	}
 
-	//2. send peer table
-	eh.communicationApi.DeliverPLTable(event.ConnectionID)
-
-	return nil
+	//2. send handshake, peer table은 handshake가 확인된 후에 보낸다
+	return eh.handshakeApi.SendHandshake(event.ConnectionID)
 }
 
 //todo deleted peer if disconnected peer is leader
@@ -18,11 +18,13 @@ package adapter_test
 
 import (
 	"testing"
+	"time"
 
 	"fmt"
 
 	"github.com/it-chain/engine/common/event"
 	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/api"
 	"github.com/it-chain/engine/p2p/infra/adapter"
 	"github.com/it-chain/engine/p2p/test/mock"
 	"github.com/magiconair/properties/assert"
@@ -51,12 +53,13 @@ func TestEventHandler_HandleConnCreatedEvent(t *testing.T) {
 			}{nodeId: "123", address: ""},
 			err: p2p.ErrEmptyAddress,
 		},
-	}
-
-	communicationApi := mock.MockCommunicationApi{}
-
-	communicationApi.DeliverPLTableFunc = func(connectionId string) error {
-		return nil
+		"banned peer test": {
+			input: struct {
+				nodeId  string
+				address string
+			}{nodeId: "banned", address: "123"},
+			err: api.ErrBannedPeer,
+		},
 	}
 
 	peerService := &mock.MockPeerService{}
@@ -68,8 +71,18 @@ func TestEventHandler_HandleConnCreatedEvent(t *testing.T) {
 		return nil
 	}
 
-	eventHandler := adapter.NewEventHandler(&communicationApi, peerService)
-	fmt.Println(communicationApi)
+	reputationApi := &mock.MockReputationApi{}
+	reputationApi.RejectIfBannedFunc = func(peerId string, now time.Time) (bool, error) {
+		return peerId == "banned", nil
+	}
+
+	handshakeApi := &mock.MockHandshakeApi{}
+	handshakeApi.SendHandshakeFunc = func(connectionId string) error {
+		assert.Equal(t, connectionId, "123")
+		return nil
+	}
+
+	eventHandler := adapter.NewEventHandler(peerService, reputationApi, handshakeApi)
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
@@ -105,14 +118,12 @@ func TestEventHandler_HandleConnDisconnectedEvent(t *testing.T) {
 		},
 	}
 
-	communicationApi := &mock.MockCommunicationApi{}
-
 	peerService := &mock.MockPeerService{}
 
 	peerService.RemoveFunc = func(peerId p2p.PeerId) error {
 		return nil
 	}
-	eventHandler := adapter.NewEventHandler(communicationApi, peerService)
+	eventHandler := adapter.NewEventHandler(peerService, &mock.MockReputationApi{}, &mock.MockHandshakeApi{})
 
 	for testName, test := range tests {
 
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+)
+
+type GossipService interface {
+	Broadcast(messageId string, protocol string, body []byte) error
+	HandleGossip(senderId string, message p2p.GossipMessage) error
+}
+
+// 다른 component가 요청한 gossip을 시작하고, 다른 peer로 부터 받은 gossip을 처리한다.
+type GossipCommandHandler struct {
+	gossipService GossipService
+	reputationApi ReputationApi
+}
+
+func NewGossipCommandHandler(gossipService GossipService, reputationApi ReputationApi) GossipCommandHandler {
+	return GossipCommandHandler{
+		gossipService: gossipService,
+		reputationApi: reputationApi,
+	}
+}
+
+func (g *GossipCommandHandler) HandleMessageGossip(command command.GossipGrpc) error {
+
+	return g.gossipService.Broadcast(command.MessageId, command.Protocol, command.Body)
+}
+
+func (g *GossipCommandHandler) HandleMessageReceive(command command.ReceiveGrpc) error {
+
+	if command.Protocol != p2p.GossipProtocol {
+		return nil
+	}
+
+	message := p2p.GossipMessage{}
+	if err := json.Unmarshal(command.Body, &message); err != nil {
+		g.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+		return ErrUnmarshal
+	}
+
+	// 검증하지 않고 전달한 peer도 penalty를 받는다.
+	err := g.gossipService.HandleGossip(command.ConnectionID, message)
+	if err == p2p.ErrInvalidGossipSignature {
+		g.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
+	}
+
+	return err
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/heimdall/key"
+)
+
+// gossip을 시작한 node가 자신의 heimdall private key로 메세지를 서명한다.
+type ECDSAGossipSigner struct {
+	priKey *ecdsa.PrivateKey
+	pubPEM []byte
+}
+
+func NewECDSAGossipSigner(priKey key.PriKey) (*ECDSAGossipSigner, error) {
+
+	ecdsaKey, pubPEM, err := common.ParseECDSAPrivateKey(priKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECDSAGossipSigner{
+		priKey: ecdsaKey,
+		pubPEM: pubPEM,
+	}, nil
+}
+
+func (s *ECDSAGossipSigner) Sign(message p2p.GossipMessage) (p2p.GossipMessage, error) {
+
+	signature, err := common.SignDigest(s.priKey, message.Digest())
+
+	if err != nil {
+		return p2p.GossipMessage{}, err
+	}
+
+	message.PubKey = s.pubPEM
+	message.Signature = signature
+
+	return message, nil
+}
+
+// gossip 메세지의 서명을 검증하고, 서명한 key로 부터 만든 node id가 Origin과 같은지 확인한다.
+type ECDSAGossipVerifier struct{}
+
+func NewECDSAGossipVerifier() *ECDSAGossipVerifier {
+	return &ECDSAGossipVerifier{}
+}
+
+func (v *ECDSAGossipVerifier) Verify(message p2p.GossipMessage) error {
+
+	if !verifyDigest(message.PubKey, message.Signature, message.Digest(), message.Origin) {
+		return p2p.ErrInvalidGossipSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGossipSignerWithNodeId(t *testing.T) (*adapter.ECDSAGossipSigner, string) {
+
+	priKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := adapter.NewECDSAGossipSigner(fakePriKey{priKey: priKey})
+	assert.NoError(t, err)
+
+	return signer, common.NodeIdFromECDSAPublicKey(&priKey.PublicKey)
+}
+
+func TestECDSAGossipVerifier_Verify(t *testing.T) {
+
+	// given
+	signer, nodeId := newGossipSignerWithNodeId(t)
+	otherSigner, otherNodeId := newGossipSignerWithNodeId(t)
+
+	message := p2p.GossipMessage{MessageId: "m", Origin: nodeId, Protocol: "BlockProtocol", Body: []byte("block"), TTL: 3}
+
+	signed, err := signer.Sign(message)
+	assert.NoError(t, err)
+
+	forged, err := otherSigner.Sign(message)
+	assert.NoError(t, err)
+
+	relayed := signed
+	relayed.TTL = 1
+
+	tampered := signed
+	tampered.Body = []byte("other block")
+
+	impersonated := signed
+	impersonated.Origin = otherNodeId
+
+	tests := map[string]struct {
+		input  p2p.GossipMessage
+		output error
+	}{
+		"signed message": {
+			input:  signed,
+			output: nil,
+		},
+		"ttl is decreased while relayed": {
+			input:  relayed,
+			output: nil,
+		},
+		"signed with key of other node": {
+			input:  forged,
+			output: p2p.ErrInvalidGossipSignature,
+		},
+		"body is changed after signed": {
+			input:  tampered,
+			output: p2p.ErrInvalidGossipSignature,
+		},
+		"origin is changed after signed": {
+			input:  impersonated,
+			output: p2p.ErrInvalidGossipSignature,
+		},
+		"not signed": {
+			input:  message,
+			output: p2p.ErrInvalidGossipSignature,
+		},
+	}
+
+	verifier := adapter.NewECDSAGossipVerifier()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := verifier.Verify(test.input)
+
+		// then
+		assert.Equal(t, test.output, err)
+	}
+}
@@ -17,11 +17,10 @@
 package adapter
 
 import (
+	"encoding/json"
 	"errors"
 
-	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/common/command"
-	"github.com/it-chain/engine/common/logger"
 	"github.com/it-chain/engine/p2p"
 	"github.com/it-chain/engine/p2p/api"
 )
@@ -36,17 +35,21 @@ type GrpcCommandHandler struct {
 	electionService  *p2p.ElectionService
 	communicationApi CommunicationApi // api.CommunicationApi
 	pLTableService   p2p.PLTableService
+	peerService      *PeerService
+	reputationApi    ReputationApi
 }
 
 func NewGrpcCommandHandler(
 	leaderApi api.ILeaderApi,
 	electionService *p2p.ElectionService, communicationApi CommunicationApi,
-	pLTableService p2p.PLTableService) GrpcCommandHandler {
+	pLTableService p2p.PLTableService, peerService *PeerService, reputationApi ReputationApi) GrpcCommandHandler {
 	return GrpcCommandHandler{
 		leaderApi:        leaderApi,
 		electionService:  electionService,
 		communicationApi: communicationApi,
 		pLTableService:   pLTableService,
+		peerService:      peerService,
+		reputationApi:    reputationApi,
 	}
 }
 
@@ -57,44 +60,114 @@ func (gch *GrpcCommandHandler) HandleMessageReceive(command command.ReceiveGrpc)
 	case "PLTableDeliverProtocol": //receive peer table
 
 		//1. receive peer table
-		pLTable, _ := gch.pLTableService.GetPLTableFromCommand(command)
+		pLTable, err := gch.pLTableService.GetPLTableFromCommand(command)
+		if err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
 
-		//2. update leader and peer list by info of node which has longer peer list
-		gch.leaderApi.UpdateLeaderWithLargePeerTable(pLTable)
+		//2. update leader if leader of peer table has higher term and valid signature
+		if err := gch.leaderApi.UpdateLeaderWithPLTable(pLTable); err == p2p.ErrInvalidLeaderSignature {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
+		}
 
-		//3. dial according to peer table
+		//3. dial to unconnected peers in union of peer tables
 		gch.communicationApi.DialToUnConnectedNode(pLTable.PeerTable)
 
 		break
 
 	case "RequestVoteProtocol":
-		logger.Infof(nil, "handling request vote from process: %v", gch.electionService.Election.GetIpAddress())
-		gch.electionService.Vote(command.ConnectionID)
+		message := p2p.RequestVoteMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return gch.electionService.Vote(command.ConnectionID, message.Term)
 
 	case "VoteLeaderProtocol":
-		//	1. if candidate, reset left time
-		//	2. count up
-		//	3. if counted is same with num of peer-1 set leader and publish
-
-		logger.Infof(nil, "received VoteLeaderProtocol command:", command)
-		logger.Infof(nil, "received VoteLeaderProtocol current election :", gch.electionService.Election)
-		gch.electionService.DecideToBeLeader()
-
-	case "UpdateLeaderProtocol":
-		// if received leader is not what i voted for, return nil
-		if gch.electionService.Election.GetCandidate().PeerId.Id != command.ConnectionID {
-			return nil
+		//	1. count up if vote is for current term
+		//	2. if voted by majority, be leader and broadcast
+		message := p2p.VoteMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
 		}
 
-		toBeLeader := &p2p.UpdateLeaderMessage{}
-		err := common.Deserialize(command.Body, toBeLeader)
+		// 보낸 peer가 서명한 이번 term의 vote만 센다.
+		if message.Vote.VoterId != command.ConnectionID || message.Vote.Term != message.Term {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
+			return p2p.ErrInvalidLeaderSignature
+		}
 
-		logger.Infof(nil, "update leader with", toBeLeader.Peer)
-		if err != nil {
+		if err := gch.leaderApi.VerifyVote(message.Vote); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
 			return err
 		}
 
-		gch.leaderApi.UpdateLeaderWithAddress(toBeLeader.Peer.IpAddress)
+		err := gch.electionService.DecideToBeLeader(message.Vote)
+		if err == p2p.ErrDuplicateVote {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.VoteSpam)
+		}
+
+		return err
+
+	case "UpdateLeaderProtocol", "HeartbeatProtocol":
+		// leader 메세지를 보낸 peer가 해당 term의 leader이다.
+		message := p2p.UpdateLeaderMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		// 다른 peer의 leadership을 알리거나 서명이 맞지 않는 메세지는 받아들이지 않는다.
+		if message.Leader.GetID() != command.ConnectionID || message.Leader.Term != message.Term {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
+			return p2p.ErrInvalidLeaderSignature
+		}
+
+		if err := gch.leaderApi.VerifyLeader(message.Leader); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.InvalidSignature)
+			return err
+		}
+
+		return gch.electionService.UpdateLeader(message.Leader)
+
+	case "LeaderInfoRequestProtocol":
+		message := p2p.LeaderInfoRequestMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return gch.peerService.RespondLeaderInfo(command.ConnectionID, message)
+
+	case "LeaderInfoResponseProtocol":
+		message := p2p.LeaderInfoResponseMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return gch.peerService.HandleLeaderInfoResponse(command.ConnectionID, message)
+
+	case "PeerListRequestProtocol":
+		message := p2p.PeerListRequestMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return gch.peerService.RespondPeerList(command.ConnectionID, message)
+
+	case "PeerListResponseProtocol":
+		message := p2p.PeerListResponseMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			gch.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return gch.peerService.HandlePeerListResponse(command.ConnectionID, message)
 	}
 
 	return nil
@@ -19,6 +19,7 @@ package adapter_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/p2p"
@@ -67,7 +68,7 @@ func TestGrpcCommandHandler_HandleMessageReceive(t *testing.T) {
 
 	pLTableService := p2p.PLTableService{}
 
-	messageHandler := adapter.NewGrpcCommandHandler(leaderApi, &electionService, communicationApi, pLTableService)
+	messageHandler := adapter.NewGrpcCommandHandler(leaderApi, &electionService, communicationApi, pLTableService, adapter.NewPeerService(mock.MockClient{}, mock.MockPeerQueryService{}, time.Second), &mock.MockReputationApi{})
 
 	for testName, test := range tests {
 		grpcReceiveCommand := command.ReceiveGrpc{
@@ -81,3 +82,88 @@ func TestGrpcCommandHandler_HandleMessageReceive(t *testing.T) {
 	}
 
 }
+
+func TestGrpcCommandHandler_HandleMessageReceive_Penalize(t *testing.T) {
+
+	// given
+	penalized := make([]string, 0)
+	reputationApi := &mock.MockReputationApi{}
+	reputationApi.PenalizeFunc = func(peerId string, violation string) error {
+		assert.Equal(t, peerId, "1")
+		penalized = append(penalized, violation)
+		return nil
+	}
+
+	messageHandler := adapter.NewGrpcCommandHandler(&mock.MockLeaderApi{}, &p2p.ElectionService{}, &mock.MockCommunicationApi{}, p2p.PLTableService{}, &adapter.PeerService{}, reputationApi)
+
+	for _, protocol := range []string{"PLTableDeliverProtocol", "RequestVoteProtocol", "VoteLeaderProtocol", "HeartbeatProtocol"} {
+		t.Logf("running test case %s", protocol)
+
+		// when
+		err := messageHandler.HandleMessageReceive(command.ReceiveGrpc{
+			Body:         []byte("malformed"),
+			ConnectionID: "1",
+			Protocol:     protocol,
+		})
+
+		// then
+		assert.Equal(t, err, adapter.ErrUnmarshal)
+	}
+
+	assert.Equal(t, penalized, []string{p2p.MalformedMessage, p2p.MalformedMessage, p2p.MalformedMessage, p2p.MalformedMessage})
+}
+
+func TestGrpcCommandHandler_HandleMessageReceive_InvalidVote(t *testing.T) {
+
+	tests := map[string]struct {
+		input p2p.VoteMessage
+		err   error
+	}{
+		"vote of other peer": {
+			input: p2p.VoteMessage{Term: 1, Vote: p2p.Vote{VoterId: "2", LeaderId: "me", Term: 1, Signature: []byte("2")}},
+			err:   p2p.ErrInvalidLeaderSignature,
+		},
+		"vote of other term": {
+			input: p2p.VoteMessage{Term: 1, Vote: p2p.Vote{VoterId: "1", LeaderId: "me", Term: 2, Signature: []byte("1")}},
+			err:   p2p.ErrInvalidLeaderSignature,
+		},
+		"forged vote": {
+			input: p2p.VoteMessage{Term: 1, Vote: p2p.Vote{VoterId: "1", LeaderId: "me", Term: 1, Signature: []byte("forged")}},
+			err:   p2p.ErrInvalidLeaderSignature,
+		},
+	}
+
+	leaderApi := &mock.MockLeaderApi{}
+	leaderApi.VerifyVoteFunc = func(vote p2p.Vote) error {
+		if string(vote.Signature) != vote.VoterId {
+			return p2p.ErrInvalidLeaderSignature
+		}
+		return nil
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		penalized := make([]string, 0)
+		reputationApi := &mock.MockReputationApi{}
+		reputationApi.PenalizeFunc = func(peerId string, violation string) error {
+			penalized = append(penalized, violation)
+			return nil
+		}
+
+		messageHandler := adapter.NewGrpcCommandHandler(leaderApi, &p2p.ElectionService{}, &mock.MockCommunicationApi{}, p2p.PLTableService{}, &adapter.PeerService{}, reputationApi)
+		body, _ := json.Marshal(test.input)
+
+		// when
+		err := messageHandler.HandleMessageReceive(command.ReceiveGrpc{
+			Body:         body,
+			ConnectionID: "1",
+			Protocol:     "VoteLeaderProtocol",
+		})
+
+		// then
+		assert.Equal(t, err, test.err)
+		assert.Equal(t, penalized, []string{p2p.InvalidSignature})
+	}
+}
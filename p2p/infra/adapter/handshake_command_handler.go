@@ -0,0 +1,63 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+)
+
+type HandshakeApi interface {
+	SendHandshake(connectionId string) error
+	HandleHandshake(connectionId string, message p2p.HandshakeMessage) error
+}
+
+// 연결된 node의 handshake를 확인하고, 호환되는 node에게만 PLTable을 보낸다.
+type HandshakeCommandHandler struct {
+	handshakeApi     HandshakeApi
+	communicationApi CommunicationApi
+	reputationApi    ReputationApi
+}
+
+func NewHandshakeCommandHandler(handshakeApi HandshakeApi, communicationApi CommunicationApi, reputationApi ReputationApi) HandshakeCommandHandler {
+	return HandshakeCommandHandler{
+		handshakeApi:     handshakeApi,
+		communicationApi: communicationApi,
+		reputationApi:    reputationApi,
+	}
+}
+
+func (h *HandshakeCommandHandler) HandleMessageReceive(command command.ReceiveGrpc) error {
+
+	if command.Protocol != "HandshakeProtocol" {
+		return nil
+	}
+
+	message := p2p.HandshakeMessage{}
+	if err := json.Unmarshal(command.Body, &message); err != nil {
+		h.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+		return ErrUnmarshal
+	}
+
+	if err := h.handshakeApi.HandleHandshake(command.ConnectionID, message); err != nil {
+		return err
+	}
+
+	return h.communicationApi.DeliverPLTable(command.ConnectionID)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/heimdall/key"
+)
+
+// node의 heimdall private key로 leader를 서명한다.
+type ECDSALeaderSigner struct {
+	priKey *ecdsa.PrivateKey
+	pubPEM []byte
+}
+
+func NewECDSALeaderSigner(priKey key.PriKey) (*ECDSALeaderSigner, error) {
+
+	ecdsaKey, pubPEM, err := common.ParseECDSAPrivateKey(priKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECDSALeaderSigner{
+		priKey: ecdsaKey,
+		pubPEM: pubPEM,
+	}, nil
+}
+
+func (s *ECDSALeaderSigner) Sign(leader p2p.Leader) (p2p.Leader, error) {
+
+	signature, err := common.SignDigest(s.priKey, leader.Digest())
+
+	if err != nil {
+		return p2p.Leader{}, err
+	}
+
+	leader.PubKey = s.pubPEM
+	leader.Signature = signature
+
+	return leader, nil
+}
+
+func (s *ECDSALeaderSigner) SignVote(vote p2p.Vote) (p2p.Vote, error) {
+
+	signature, err := common.SignDigest(s.priKey, vote.Digest())
+
+	if err != nil {
+		return p2p.Vote{}, err
+	}
+
+	vote.PubKey = s.pubPEM
+	vote.Signature = signature
+
+	return vote, nil
+}
+
+// leader의 서명을 검증하고, 서명한 key로 부터 만든 node id가 leader id와 같은지 확인한다.
+// leader가 가진 vote도 같은 방식으로 voter id와 함께 검증한다.
+type ECDSALeaderVerifier struct{}
+
+func NewECDSALeaderVerifier() *ECDSALeaderVerifier {
+	return &ECDSALeaderVerifier{}
+}
+
+func (v *ECDSALeaderVerifier) Verify(leader p2p.Leader) error {
+
+	if !verifyDigest(leader.PubKey, leader.Signature, leader.Digest(), leader.GetID()) {
+		return p2p.ErrInvalidLeaderSignature
+	}
+
+	for _, vote := range leader.Votes {
+
+		if vote.LeaderId != leader.GetID() || vote.Term != leader.Term {
+			return p2p.ErrInvalidLeaderSignature
+		}
+
+		if err := v.VerifyVote(vote); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *ECDSALeaderVerifier) VerifyVote(vote p2p.Vote) error {
+
+	if !verifyDigest(vote.PubKey, vote.Signature, vote.Digest(), vote.VoterId) {
+		return p2p.ErrInvalidLeaderSignature
+	}
+
+	return nil
+}
+
+// 서명이 맞는지, 서명한 key로 부터 만든 node id가 nodeId와 같은지 확인한다.
+func verifyDigest(pubPEM []byte, signature []byte, digest []byte, nodeId string) bool {
+
+	signerId, err := common.VerifyDigest(pubPEM, signature, digest)
+
+	return err == nil && signerId == nodeId
+}
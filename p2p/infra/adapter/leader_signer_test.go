@@ -0,0 +1,164 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/adapter"
+	"github.com/it-chain/heimdall/key"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePriKey struct {
+	priKey *ecdsa.PrivateKey
+}
+
+func (k fakePriKey) SKI() []byte                  { return nil }
+func (fakePriKey) Algorithm() key.KeyGenOpts      { return key.ECDSA256 }
+func (fakePriKey) Type() key.KeyType              { return "" }
+func (fakePriKey) PublicKey() (key.PubKey, error) { return nil, nil }
+func (k fakePriKey) ToPEM() ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(k.priKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSignerWithNodeId(t *testing.T) (*adapter.ECDSALeaderSigner, string) {
+
+	priKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := adapter.NewECDSALeaderSigner(fakePriKey{priKey: priKey})
+	assert.NoError(t, err)
+
+	return signer, common.NodeIdFromECDSAPublicKey(&priKey.PublicKey)
+}
+
+func TestECDSALeaderVerifier_Verify(t *testing.T) {
+
+	// given
+	signer, nodeId := newSignerWithNodeId(t)
+	otherSigner, _ := newSignerWithNodeId(t)
+
+	signed, err := signer.Sign(p2p.Leader{LeaderId: p2p.LeaderId{Id: nodeId}, Term: 3})
+	assert.NoError(t, err)
+
+	forged, err := otherSigner.Sign(p2p.Leader{LeaderId: p2p.LeaderId{Id: nodeId}, Term: 3})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input  p2p.Leader
+		output error
+	}{
+		"signed leader": {
+			input:  signed,
+			output: nil,
+		},
+		"signed with key of other node": {
+			input:  forged,
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+		"term is changed after signed": {
+			input:  p2p.Leader{LeaderId: signed.LeaderId, Term: 4, PubKey: signed.PubKey, Signature: signed.Signature},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+		"signature of other node": {
+			input:  p2p.Leader{LeaderId: signed.LeaderId, Term: 3, PubKey: signed.PubKey, Signature: forged.Signature},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+		"not signed": {
+			input:  p2p.Leader{LeaderId: signed.LeaderId, Term: 3},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+	}
+
+	verifier := adapter.NewECDSALeaderVerifier()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := verifier.Verify(test.input)
+
+		// then
+		assert.Equal(t, test.output, err)
+	}
+}
+
+func TestECDSALeaderVerifier_Verify_Votes(t *testing.T) {
+
+	// given
+	leaderSigner, leaderId := newSignerWithNodeId(t)
+	voterSigner, voterId := newSignerWithNodeId(t)
+	otherSigner, _ := newSignerWithNodeId(t)
+
+	vote, err := voterSigner.SignVote(p2p.Vote{VoterId: voterId, LeaderId: leaderId, Term: 3})
+	assert.NoError(t, err)
+
+	forgedVote, err := otherSigner.SignVote(p2p.Vote{VoterId: voterId, LeaderId: leaderId, Term: 3})
+	assert.NoError(t, err)
+
+	staleVote, err := voterSigner.SignVote(p2p.Vote{VoterId: voterId, LeaderId: leaderId, Term: 2})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input  []p2p.Vote
+		output error
+	}{
+		"signed vote": {
+			input:  []p2p.Vote{vote},
+			output: nil,
+		},
+		"vote signed with key of other node": {
+			input:  []p2p.Vote{forgedVote},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+		"vote of other term": {
+			input:  []p2p.Vote{staleVote},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+		"vote for other leader": {
+			input:  []p2p.Vote{{VoterId: voterId, LeaderId: "other", Term: 3, PubKey: vote.PubKey, Signature: vote.Signature}},
+			output: p2p.ErrInvalidLeaderSignature,
+		},
+	}
+
+	verifier := adapter.NewECDSALeaderVerifier()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		leader, err := leaderSigner.Sign(p2p.Leader{LeaderId: p2p.LeaderId{Id: leaderId}, Term: 3, Votes: test.input})
+		assert.NoError(t, err)
+
+		// when
+		err = verifier.Verify(leader)
+
+		// then
+		assert.Equal(t, test.output, err)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+)
+
+type LivenessApi interface {
+	HandlePing(connectionId string, message p2p.PingMessage) error
+	HandlePong(connectionId string, message p2p.PongMessage, now time.Time) error
+}
+
+// 다른 peer로 부터 받은 ping에 답하고, pong으로 RTT를 기록한다.
+type LivenessCommandHandler struct {
+	livenessApi   LivenessApi
+	reputationApi ReputationApi
+}
+
+func NewLivenessCommandHandler(livenessApi LivenessApi, reputationApi ReputationApi) LivenessCommandHandler {
+	return LivenessCommandHandler{
+		livenessApi:   livenessApi,
+		reputationApi: reputationApi,
+	}
+}
+
+func (l *LivenessCommandHandler) HandleMessageReceive(command command.ReceiveGrpc) error {
+
+	switch command.Protocol {
+
+	case "PingProtocol":
+		message := p2p.PingMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			l.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return l.livenessApi.HandlePing(command.ConnectionID, message)
+
+	case "PongProtocol":
+		message := p2p.PongMessage{}
+		if err := json.Unmarshal(command.Body, &message); err != nil {
+			l.reputationApi.Penalize(command.ConnectionID, p2p.MalformedMessage)
+			return ErrUnmarshal
+		}
+
+		return l.livenessApi.HandlePong(command.ConnectionID, message, time.Now())
+	}
+
+	return nil
+}
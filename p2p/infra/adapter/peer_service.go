@@ -17,14 +17,43 @@
 package adapter
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
 	"github.com/it-chain/engine/p2p"
+	"github.com/rs/xid"
 )
 
+var ErrRequestTimeout = errors.New("request timed out")
+var ErrUnknownResponse = errors.New("response of unknown request")
+
+type pendingRequest struct {
+	connectionId string
+	response     chan interface{}
+}
+
+// 다른 peer에게 leader와 peer list를 묻고, 다른 peer의 요청에 답한다.
+// 요청은 응답을 받거나 timeout 될 때 까지 기다린다. 응답도 message.receive로 오므로 message handler 안에서 요청하면 안 된다.
 type PeerService struct {
-	client p2p.Client
+	mux              sync.Mutex
+	client           p2p.Client
+	peerQueryService p2p.PeerQueryService
+	timeout          time.Duration
+	pending          map[string]pendingRequest
+}
+
+func NewPeerService(client p2p.Client, peerQueryService p2p.PeerQueryService, timeout time.Duration) *PeerService {
+
+	return &PeerService{
+		mux:              sync.Mutex{},
+		client:           client,
+		peerQueryService: peerQueryService,
+		timeout:          timeout,
+		pending:          make(map[string]pendingRequest),
+	}
 }
 
 func (ps *PeerService) Dial(ipAddress string) error {
@@ -32,50 +61,151 @@ func (ps *PeerService) Dial(ipAddress string) error {
 	connectionCreateCommand := command.CreateConnection{
 		Address: ipAddress,
 	}
-	ps.client.Call("connection.create", connectionCreateCommand, func() {})
-	return nil
+
+	return ps.client.Call("connection.create", connectionCreateCommand, func(_ struct{}, err rpc.Error) {})
 }
 
 //request leader information in p2p network to the node specified by peerId
-func (ps *PeerService) RequestLeaderInfo(connectionId string) error {
+func (ps *PeerService) RequestLeaderInfo(connectionId string) (p2p.Leader, error) {
 
 	if connectionId == "" {
-		return ErrEmptyPeerId
+		return p2p.Leader{}, ErrEmptyPeerId
 	}
 
+	requestId := xid.New().String()
+
 	body := p2p.LeaderInfoRequestMessage{
-		TimeUnix: time.Now().Unix(),
+		RequestId: requestId,
+		TimeUnix:  time.Now().Unix(),
 	}
 
-	//message deliver command for delivering leader info
-	deliverCommand, err := CreateGrpcDeliverCommand("LeaderInfoRequestMessage", body)
+	response, err := ps.request(connectionId, requestId, "LeaderInfoRequestProtocol", body)
 
 	if err != nil {
-		return err
+		return p2p.Leader{}, err
 	}
 
-	deliverCommand.RecipientList = append(deliverCommand.RecipientList, connectionId)
-
-	return ps.client.Call("message.deliver", deliverCommand, func() {})
+	return response.(p2p.LeaderInfoResponseMessage).Leader, nil
 }
 
 // command message which requests node list of specific node
-func (ps *PeerService) RequestPeerList(peerId p2p.PeerId) error {
+func (ps *PeerService) RequestPeerList(peerId p2p.PeerId) ([]p2p.Peer, error) {
 
 	if peerId.Id == "" {
-		return ErrEmptyPeerId
+		return nil, ErrEmptyPeerId
 	}
-	body := p2p.LeaderInfoRequestMessage{
-		TimeUnix: time.Now().Unix(),
+
+	requestId := xid.New().String()
+
+	body := p2p.PeerListRequestMessage{
+		RequestId: requestId,
+		TimeUnix:  time.Now().Unix(),
 	}
 
-	deliverCommand, err := CreateGrpcDeliverCommand("PeerListRequestMessage", body)
+	response, err := ps.request(peerId.ToString(), requestId, "PeerListRequestProtocol", body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(p2p.PeerListResponseMessage).PeerList, nil
+}
+
+// 자신의 leader를 요청한 peer에게 보낸다.
+func (ps *PeerService) RespondLeaderInfo(connectionId string, message p2p.LeaderInfoRequestMessage) error {
+
+	leader, err := ps.peerQueryService.GetLeader()
+
+	if err != nil {
+		return err
+	}
+
+	return ps.deliver(connectionId, "LeaderInfoResponseProtocol", p2p.LeaderInfoResponseMessage{
+		RequestId: message.RequestId,
+		Leader:    leader,
+	})
+}
+
+// 자신의 peer list를 요청한 peer에게 보낸다.
+func (ps *PeerService) RespondPeerList(connectionId string, message p2p.PeerListRequestMessage) error {
+
+	peerList, err := ps.peerQueryService.GetPeerList()
 
 	if err != nil {
 		return err
 	}
 
-	deliverCommand.RecipientList = append(deliverCommand.RecipientList, peerId.ToString())
+	return ps.deliver(connectionId, "PeerListResponseProtocol", p2p.PeerListResponseMessage{
+		RequestId: message.RequestId,
+		PeerList:  peerList,
+	})
+}
+
+func (ps *PeerService) HandleLeaderInfoResponse(connectionId string, message p2p.LeaderInfoResponseMessage) error {
+
+	return ps.resolve(connectionId, message.RequestId, message)
+}
+
+func (ps *PeerService) HandlePeerListResponse(connectionId string, message p2p.PeerListResponseMessage) error {
+
+	return ps.resolve(connectionId, message.RequestId, message)
+}
+
+func (ps *PeerService) request(connectionId string, requestId string, protocol string, body interface{}) (interface{}, error) {
+
+	response := make(chan interface{}, 1)
+
+	ps.mux.Lock()
+	ps.pending[requestId] = pendingRequest{connectionId: connectionId, response: response}
+	ps.mux.Unlock()
+
+	defer func() {
+		ps.mux.Lock()
+		delete(ps.pending, requestId)
+		ps.mux.Unlock()
+	}()
+
+	if err := ps.deliver(connectionId, protocol, body); err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-response:
+		return r, nil
+	case <-time.After(ps.timeout):
+		return nil, ErrRequestTimeout
+	}
+}
+
+// 요청한 peer로 부터 온 응답만 받아들인다. timeout 된 요청의 응답은 ErrUnknownResponse 이다.
+func (ps *PeerService) resolve(connectionId string, requestId string, response interface{}) error {
+
+	ps.mux.Lock()
+	defer ps.mux.Unlock()
+
+	request, ok := ps.pending[requestId]
+
+	if !ok || request.connectionId != connectionId {
+		return ErrUnknownResponse
+	}
+
+	select {
+	case request.response <- response:
+	default:
+	}
+
+	return nil
+}
+
+func (ps *PeerService) deliver(connectionId string, protocol string, body interface{}) error {
+
+	deliverCommand, err := CreateGrpcDeliverCommand(protocol, body)
+
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.RecipientList = append(deliverCommand.RecipientList, connectionId)
 
-	return ps.client.Call("message.deliver", deliverCommand, func() {})
+	return ps.client.Call("message.deliver", deliverCommand, func(_ struct{}, err rpc.Error) {})
 }
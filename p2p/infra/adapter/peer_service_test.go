@@ -0,0 +1,146 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/adapter"
+	"github.com/it-chain/engine/p2p/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// requester의 요청을 responder에게, responder의 응답을 requester에게 전달한다.
+func setPeerServices(requesterId string, responderId string, responderQueryService p2p.PeerQueryService) *adapter.PeerService {
+
+	var requester *adapter.PeerService
+	var responder *adapter.PeerService
+
+	requesterClient := mock.MockClient{}
+	requesterClient.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		deliver := params.(command.DeliverGrpc)
+
+		go func() {
+			switch deliver.Protocol {
+			case "LeaderInfoRequestProtocol":
+				message := p2p.LeaderInfoRequestMessage{}
+				json.Unmarshal(deliver.Body, &message)
+				responder.RespondLeaderInfo(requesterId, message)
+			case "PeerListRequestProtocol":
+				message := p2p.PeerListRequestMessage{}
+				json.Unmarshal(deliver.Body, &message)
+				responder.RespondPeerList(requesterId, message)
+			}
+		}()
+
+		return nil
+	}
+
+	responderClient := mock.MockClient{}
+	responderClient.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		deliver := params.(command.DeliverGrpc)
+
+		switch deliver.Protocol {
+		case "LeaderInfoResponseProtocol":
+			message := p2p.LeaderInfoResponseMessage{}
+			json.Unmarshal(deliver.Body, &message)
+			return requester.HandleLeaderInfoResponse(responderId, message)
+		case "PeerListResponseProtocol":
+			message := p2p.PeerListResponseMessage{}
+			json.Unmarshal(deliver.Body, &message)
+			return requester.HandlePeerListResponse(responderId, message)
+		}
+
+		return nil
+	}
+
+	requester = adapter.NewPeerService(requesterClient, mock.MockPeerQueryService{}, time.Second)
+	responder = adapter.NewPeerService(responderClient, responderQueryService, time.Second)
+
+	return requester
+}
+
+func TestPeerService_RequestLeaderInfo(t *testing.T) {
+
+	// given
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetLeaderFunc = func() (p2p.Leader, error) {
+		return p2p.Leader{LeaderId: p2p.LeaderId{Id: "leader"}}, nil
+	}
+
+	requester := setPeerServices("1", "2", queryService)
+
+	// when
+	leader, err := requester.RequestLeaderInfo("2")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "leader", leader.LeaderId.Id)
+
+	// when
+	_, err = requester.RequestLeaderInfo("")
+
+	// then
+	assert.Equal(t, adapter.ErrEmptyPeerId, err)
+}
+
+func TestPeerService_RequestPeerList(t *testing.T) {
+
+	// given
+	peerList := []p2p.Peer{
+		{PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr"},
+		{PeerId: p2p.PeerId{Id: "3"}, IpAddress: "3.ipAddr"},
+	}
+
+	queryService := mock.MockPeerQueryService{}
+	queryService.GetPeerListFunc = func() ([]p2p.Peer, error) {
+		return peerList, nil
+	}
+
+	requester := setPeerServices("1", "2", queryService)
+
+	// when
+	received, err := requester.RequestPeerList(p2p.PeerId{Id: "2"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, peerList, received)
+}
+
+func TestPeerService_RequestTimeout(t *testing.T) {
+
+	// given: nobody answers
+	client := mock.MockClient{}
+	client.CallFunc = func(queue string, params interface{}, callback interface{}) error {
+		return nil
+	}
+
+	peerService := adapter.NewPeerService(client, mock.MockPeerQueryService{}, 10*time.Millisecond)
+
+	// when
+	_, err := peerService.RequestPeerList(p2p.PeerId{Id: "2"})
+
+	// then
+	assert.Equal(t, adapter.ErrRequestTimeout, err)
+
+	// late response is unknown
+	assert.Equal(t, adapter.ErrUnknownResponse, peerService.HandlePeerListResponse("2", p2p.PeerListResponseMessage{RequestId: "late"}))
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"time"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/p2p"
+)
+
+type ReputationApi interface {
+	Penalize(peerId string, violation string) error
+	RejectIfBanned(peerId string, now time.Time) (bool, error)
+	GetBans(now time.Time) ([]p2p.Ban, error)
+	Unban(peerId string) error
+	ClearBans() error
+}
+
+// 다른 component(blockchain, consensus 등)가 알린 peer의 위반을 처리한다.
+type ReputationCommandHandler struct {
+	reputationApi ReputationApi
+}
+
+func NewReputationCommandHandler(reputationApi ReputationApi) ReputationCommandHandler {
+	return ReputationCommandHandler{
+		reputationApi: reputationApi,
+	}
+}
+
+func (r *ReputationCommandHandler) HandleReportPeer(command command.ReportPeer) error {
+
+	return r.reputationApi.Penalize(command.PeerId, command.Violation)
+}
+
+// ban list를 조회하고 해제하는 관리자 command를 처리한다.
+type BanCommandHandler struct {
+	reputationApi ReputationApi
+}
+
+func NewBanCommandHandler(reputationApi ReputationApi) BanCommandHandler {
+	return BanCommandHandler{
+		reputationApi: reputationApi,
+	}
+}
+
+func (b *BanCommandHandler) HandleListBans(_ command.ListBans) ([]p2p.Ban, rpc.Error) {
+
+	bans, err := b.reputationApi.GetBans(time.Now())
+
+	if err != nil {
+		return nil, rpc.Error{Message: err.Error()}
+	}
+
+	return bans, rpc.Error{}
+}
+
+func (b *BanCommandHandler) HandleClearBan(command command.ClearBan) (struct{}, rpc.Error) {
+
+	var err error
+
+	if command.PeerId == "" {
+		err = b.reputationApi.ClearBans()
+	} else {
+		err = b.reputationApi.Unban(command.PeerId)
+	}
+
+	if err != nil {
+		return struct{}{}, rpc.Error{Message: err.Error()}
+	}
+
+	return struct{}{}, rpc.Error{}
+}
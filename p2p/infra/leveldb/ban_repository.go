@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+)
+
+var banPrefix = []byte("ban_")
+
+// ban list는 재시작 후에도 유지되도록 known peer와 같은 leveldb에 저장한다.
+func (pr *PeerRepository) SaveBan(ban p2p.Ban) error {
+
+	if ban.PeerId == "" {
+		return p2p.ErrEmptyPeerId
+	}
+
+	b, err := common.Serialize(ban)
+
+	if err != nil {
+		return err
+	}
+
+	return pr.leveldb.Put(banKey(ban.PeerId), b, true)
+}
+
+func (pr *PeerRepository) FindBans() ([]p2p.Ban, error) {
+
+	iter := pr.leveldb.GetIteratorWithPrefix(banPrefix)
+	bans := make([]p2p.Ban, 0)
+
+	for iter.Next() {
+		ban := p2p.Ban{}
+		if err := json.Unmarshal(iter.Value(), &ban); err != nil {
+			return nil, err
+		}
+
+		bans = append(bans, ban)
+	}
+
+	return bans, nil
+}
+
+func (pr *PeerRepository) RemoveBan(peerId string) error {
+
+	if peerId == "" {
+		return p2p.ErrEmptyPeerId
+	}
+
+	return pr.leveldb.Delete(banKey(peerId), true)
+}
+
+func banKey(peerId string) []byte {
+	return append(append([]byte{}, banPrefix...), []byte(peerId)...)
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerRepository_Ban(t *testing.T) {
+	dbPath := "./.ban_db"
+	defer os.RemoveAll(dbPath)
+
+	peerRepository := leveldb.NewPeerRepository(dbPath)
+
+	until := time.Unix(1000, 0).UTC()
+	ban := p2p.Ban{PeerId: "1", Reason: p2p.BadBlock, Until: until}
+
+	// case 1 : ban list is kept after restart
+	assert.NoError(t, peerRepository.SaveBan(ban))
+	assert.Equal(t, p2p.ErrEmptyPeerId, peerRepository.SaveBan(p2p.Ban{}))
+
+	peerRepository.Close()
+	peerRepository = leveldb.NewPeerRepository(dbPath)
+	defer peerRepository.Close()
+
+	bans, err := peerRepository.FindBans()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bans))
+	assert.Equal(t, "1", bans[0].PeerId)
+	assert.True(t, until.Equal(bans[0].Until))
+
+	// case 2 : ban is not a known peer
+	knownPeers, err := peerRepository.FindKnownPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(knownPeers))
+
+	// case 3 : remove ban
+	assert.NoError(t, peerRepository.RemoveBan("1"))
+
+	bans, err = peerRepository.FindBans()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(bans))
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/mem"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+var knownPeerPrefix = []byte("known_peer_")
+
+// 연결된 peer table은 메모리에 유지하고, 한 번이라도 연결된 peer는 마지막 연결 시간과 함께 leveldb에 저장한다.
+// 재시작한 node는 저장된 peer에 다시 연결할 수 있다.
+type PeerRepository struct {
+	*mem.PeerRepository
+	leveldb *leveldbwrapper.DB
+}
+
+func NewPeerRepository(path string) *PeerRepository {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+
+	peerRepository := mem.NewPeerReopository()
+
+	return &PeerRepository{
+		PeerRepository: &peerRepository,
+		leveldb:        db,
+	}
+}
+
+func (pr *PeerRepository) Save(peer p2p.Peer) error {
+
+	if err := pr.PeerRepository.Save(peer); err != nil {
+		return err
+	}
+
+	return pr.saveKnownPeer(peer, time.Now())
+}
+
+// 연결이 끊긴 peer는 peer table에서만 지우고, 마지막 연결 시간을 갱신해 둔다.
+func (pr *PeerRepository) Remove(id string) error {
+
+	peer, findErr := pr.PeerRepository.FindPeerById(p2p.PeerId{Id: id})
+
+	if err := pr.PeerRepository.Remove(id); err != nil {
+		return err
+	}
+
+	// peer table에 없던 peer는 갱신할 기록도 없다.
+	if findErr != nil {
+		return nil
+	}
+
+	return pr.saveKnownPeer(peer, time.Now())
+}
+
+func (pr *PeerRepository) FindKnownPeers() ([]p2p.KnownPeer, error) {
+
+	iter := pr.leveldb.GetIteratorWithPrefix(knownPeerPrefix)
+	knownPeers := make([]p2p.KnownPeer, 0)
+
+	for iter.Next() {
+		knownPeer := p2p.KnownPeer{}
+		if err := json.Unmarshal(iter.Value(), &knownPeer); err != nil {
+			return nil, err
+		}
+
+		knownPeers = append(knownPeers, knownPeer)
+	}
+
+	return knownPeers, nil
+}
+
+func (pr *PeerRepository) Forget(id string) error {
+
+	if id == "" {
+		return p2p.ErrEmptyPeerId
+	}
+
+	return pr.leveldb.Delete(knownPeerKey(id), true)
+}
+
+func (pr *PeerRepository) Close() {
+	pr.leveldb.Close()
+}
+
+func (pr *PeerRepository) saveKnownPeer(peer p2p.Peer, lastSeen time.Time) error {
+
+	if peer.PeerId.Id == "" {
+		return p2p.ErrEmptyPeerId
+	}
+
+	b, err := common.Serialize(p2p.KnownPeer{
+		Peer:     peer,
+		LastSeen: lastSeen,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return pr.leveldb.Put(knownPeerKey(peer.PeerId.Id), b, true)
+}
+
+func knownPeerKey(id string) []byte {
+	return append(append([]byte{}, knownPeerPrefix...), []byte(id)...)
+}
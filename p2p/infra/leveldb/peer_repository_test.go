@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/it-chain/engine/p2p/infra/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerRepository(t *testing.T) {
+	dbPath := "./.db"
+	defer os.RemoveAll(dbPath)
+
+	peerRepository := leveldb.NewPeerRepository(dbPath)
+
+	peer1 := p2p.Peer{PeerId: p2p.PeerId{Id: "1"}, IpAddress: "1.ipAddr"}
+	peer2 := p2p.Peer{PeerId: p2p.PeerId{Id: "2"}, IpAddress: "2.ipAddr"}
+
+	// case 1 : saved peers are in peer table and known peers
+	assert.NoError(t, peerRepository.Save(peer1))
+	assert.NoError(t, peerRepository.Save(peer2))
+
+	pLTable, _ := peerRepository.GetPLTable()
+	assert.Equal(t, 2, len(pLTable.PeerTable))
+
+	knownPeers, err := peerRepository.FindKnownPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(knownPeers))
+
+	// case 2 : disconnected peer is still known
+	assert.NoError(t, peerRepository.Remove("2"))
+
+	_, err = peerRepository.FindPeerById(p2p.PeerId{Id: "2"})
+	assert.Equal(t, p2p.ErrNoMatchingPeerId, err)
+
+	knownPeers, err = peerRepository.FindKnownPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(knownPeers))
+
+	// case 3 : known peers are recovered after restart, but peer table is empty
+	peerRepository.Close()
+	peerRepository = leveldb.NewPeerRepository(dbPath)
+	defer peerRepository.Close()
+
+	pLTable, _ = peerRepository.GetPLTable()
+	assert.Equal(t, 0, len(pLTable.PeerTable))
+
+	knownPeers, err = peerRepository.FindKnownPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(knownPeers))
+
+	for _, knownPeer := range knownPeers {
+		assert.False(t, knownPeer.LastSeen.IsZero())
+	}
+
+	// case 4 : forget
+	assert.NoError(t, peerRepository.Forget("1"))
+
+	knownPeers, err = peerRepository.FindKnownPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, []p2p.Peer{peer2}, []p2p.Peer{knownPeers[0].Peer})
+}
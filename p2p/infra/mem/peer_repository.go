@@ -19,6 +19,7 @@ package mem
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/it-chain/engine/p2p"
 )
@@ -33,7 +34,7 @@ type PeerRepository struct {
 func NewPeerReopository() PeerRepository {
 	return PeerRepository{
 		mux:     sync.RWMutex{},
-		pLTable: *p2p.NewPLTable(p2p.Leader{p2p.LeaderId{""}}, make(map[string]p2p.Peer)),
+		pLTable: *p2p.NewPLTable(p2p.Leader{LeaderId: p2p.LeaderId{Id: ""}}, make(map[string]p2p.Peer)),
 	}
 }
 
@@ -108,6 +109,40 @@ func (pr *PeerRepository) SetLeader(leader p2p.Leader) error {
 	return nil
 }
 
+func (pr *PeerRepository) UpdateLatency(id string, latency time.Duration) error {
+
+	pr.mux.Lock()
+	defer pr.mux.Unlock()
+
+	peer, exist := pr.pLTable.PeerTable[id]
+
+	if !exist {
+		return p2p.ErrNoMatchingPeerId
+	}
+
+	peer.Latency = latency
+	pr.pLTable.PeerTable[id] = peer
+
+	return nil
+}
+
+func (pr *PeerRepository) UpdateMetadata(id string, metadata p2p.NodeMetadata) error {
+
+	pr.mux.Lock()
+	defer pr.mux.Unlock()
+
+	peer, exist := pr.pLTable.PeerTable[id]
+
+	if !exist {
+		return p2p.ErrNoMatchingPeerId
+	}
+
+	peer.Metadata = metadata
+	pr.pLTable.PeerTable[id] = peer
+
+	return nil
+}
+
 func (pr *PeerRepository) Remove(id string) error {
 
 	pr.mux.Lock()
@@ -16,8 +16,45 @@
 
 package p2p
 
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidLeaderSignature = errors.New("invalid leader signature")
+var ErrNotElectedLeader = errors.New("leader is not voted by majority")
+var ErrLeaderTermTooHigh = errors.New("leader term is too far from local term")
+
+// 알려진 leader가 있는 node는 local term 보다 이 값 이상 큰 term의 leader를 받아들이지 않는다.
+const MaxLeaderTermGap = 100
+
+// leader는 선출된 term과 함께 자신의 key로 서명하여 알린다.
+// 서명이 없거나 맞지 않는 leader는 다른 node로 부터 받아들이지 않는다.
+// 과반의 peer가 서명한 vote를 함께 보내야 선출되었음을 증명할 수 있다.
 type Leader struct {
-	LeaderId LeaderId
+	LeaderId  LeaderId
+	Term      uint64
+	PubKey    []byte // PEM encoded public key of leader
+	Signature []byte
+	Votes     []Vote
+}
+
+// peer가 term의 candidate에게 투표했음을 자신의 key로 서명한다.
+type Vote struct {
+	VoterId   string
+	LeaderId  string
+	Term      uint64
+	PubKey    []byte // PEM encoded public key of voter
+	Signature []byte
+}
+
+// voter가 서명하는 값. VoterId, LeaderId와 Term을 묶는다.
+func (v Vote) Digest() []byte {
+
+	digest := sha256.Sum256([]byte(fmt.Sprintf("vote:%s:%s:%d", v.VoterId, v.LeaderId, v.Term)))
+
+	return digest[:]
 }
 
 type LeaderId struct {
@@ -31,3 +68,54 @@ func (lid LeaderId) ToString() string {
 func (l Leader) GetID() string {
 	return l.LeaderId.ToString()
 }
+
+// leader가 서명하는 값. LeaderId와 Term을 묶는다.
+func (l Leader) Digest() []byte {
+
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", l.LeaderId.Id, l.Term)))
+
+	return digest[:]
+}
+
+// leader 자신과 leader에게 투표한 peer 중 pLTable에 있는 peer가 과반인지 확인한다.
+// 모르는 peer의 vote는 세지 않는다. vote의 서명은 LeaderVerifier로 검증되어야 한다.
+func (l Leader) IsVotedByMajority(pLTable PLTable, selfId string) bool {
+
+	known := map[string]bool{selfId: true}
+
+	for id := range pLTable.PeerTable {
+		known[id] = true
+	}
+
+	voters := make(map[string]bool)
+
+	if known[l.GetID()] {
+		voters[l.GetID()] = true
+	}
+
+	for _, vote := range l.Votes {
+		if known[vote.VoterId] && vote.LeaderId == l.GetID() && vote.Term == l.Term {
+			voters[vote.VoterId] = true
+		}
+	}
+
+	return len(voters) >= len(known)/2+1
+}
+
+// 알려진 leader가 없는 node(재시작한 node 등)는 term을 비교할 수 없으므로 과반의 vote 만으로 받아들인다.
+func (l Leader) IsTermInRange(localTerm uint64, hasLeader bool) bool {
+
+	return !hasLeader || l.Term <= localTerm+MaxLeaderTermGap
+}
+
+type LeaderSigner interface {
+	Sign(leader Leader) (Leader, error)
+	SignVote(vote Vote) (Vote, error)
+}
+
+// 서명이 맞는지, 서명한 key가 leader의 id와 맞는지 검증한다.
+// leader가 가진 vote의 서명과 vote가 leader와 term에 대한 것인지도 검증한다.
+type LeaderVerifier interface {
+	Verify(leader Leader) error
+	VerifyVote(vote Vote) error
+}
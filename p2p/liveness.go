@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p
+
+import "sync"
+
+// 각 peer가 응답하지 않은 ping 수를 센다.
+// pong을 받으면 0으로 돌아가고, maxMissed 번 연속으로 응답하지 않은 peer는 죽은 것으로 본다.
+type Liveness struct {
+	mux       sync.Mutex
+	maxMissed int
+	missed    map[string]int
+}
+
+func NewLiveness(maxMissed int) *Liveness {
+
+	return &Liveness{
+		mux:       sync.Mutex{},
+		maxMissed: maxMissed,
+		missed:    make(map[string]int),
+	}
+}
+
+// ping을 보낼 peer 들을 받아 응답하지 않은 수를 올리고, 죽은 peer id 들을 반환한다.
+// 목록에 없는 peer와 죽은 peer는 더 이상 세지 않는다.
+func (l *Liveness) Ping(peerIds []string) []string {
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	dead := make([]string, 0)
+	missed := make(map[string]int)
+
+	for _, id := range peerIds {
+
+		if l.missed[id] >= l.maxMissed {
+			dead = append(dead, id)
+			continue
+		}
+
+		missed[id] = l.missed[id] + 1
+	}
+
+	l.missed = missed
+
+	return dead
+}
+
+func (l *Liveness) Pong(peerId string) {
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if _, ok := l.missed[peerId]; ok {
+		l.missed[peerId] = 0
+	}
+}
+
+func (l *Liveness) GetMissed(peerId string) int {
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.missed[peerId]
+}
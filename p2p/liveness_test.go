@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveness_Ping(t *testing.T) {
+	// given
+	liveness := p2p.NewLiveness(2)
+
+	// when: 2 pings without pong
+	assert.Equal(t, []string{}, liveness.Ping([]string{"1", "2"}))
+	liveness.Pong("1")
+	assert.Equal(t, []string{}, liveness.Ping([]string{"1", "2"}))
+
+	// then
+	assert.Equal(t, 1, liveness.GetMissed("1"))
+	assert.Equal(t, 2, liveness.GetMissed("2"))
+
+	// when: third ping
+	dead := liveness.Ping([]string{"1", "2"})
+
+	// then
+	assert.Equal(t, []string{"2"}, dead)
+	assert.Equal(t, 0, liveness.GetMissed("2"))
+
+	// when: removed peer is not counted any more
+	liveness.Ping([]string{"2"})
+
+	// then
+	assert.Equal(t, 0, liveness.GetMissed("1"))
+}
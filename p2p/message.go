@@ -16,20 +16,59 @@
 
 package p2p
 
+// 요청과 응답은 RequestId로 짝지어진다.
 type LeaderInfoRequestMessage struct {
-	TimeUnix int64
+	RequestId string
+	TimeUnix  int64
+}
+
+type LeaderInfoResponseMessage struct {
+	RequestId string
+	Leader    Leader
+}
+
+type PeerListRequestMessage struct {
+	RequestId string
+	TimeUnix  int64
+}
+
+type PeerListResponseMessage struct {
+	RequestId string
+	PeerList  []Peer
 }
 
+// leader가 선출될 때와 heartbeat 마다 같은 메세지를 보낸다.
+// Leader는 leader가 서명한 term의 leader이다.
 type UpdateLeaderMessage struct {
-	Peer Peer
+	Term   uint64
+	Peer   Peer
+	Leader Leader
 }
 
 type PLTableMessage struct {
 	PLTable PLTable
 }
 
+// 연결되면 서로의 NodeMetadata를 교환한다.
+type HandshakeMessage struct {
+	Metadata NodeMetadata
+}
+
+// ping을 보낸 시간을 pong으로 그대로 돌려받아 RTT를 잰다.
+type PingMessage struct {
+	TimeUnix int64
+}
+
+type PongMessage struct {
+	TimeUnix int64
+}
+
 type RequestVoteMessage struct {
+	Term uint64
 }
 
+// 선출된 leader가 과반의 투표를 증명할 수 있도록 서명된 vote를 보낸다.
 type VoteMessage struct {
+	Term uint64
+	Vote Vote
 }
@@ -1,51 +0,0 @@
-/*
- * Copyright 2018 It-chain
- *
- * Licensed under the Apache License, Version 2.0 (the "License");
- * you may not use this file except in compliance with the License.
- * You may obtain a copy of the License at
- *
- * https://www.apache.org/licenses/LICENSE-2.0
- *
- * Unless required by applicable law or agreed to in writing, software
- * distributed under the License is distributed on an "AS IS" BASIS,
- * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
- * See the License for the specific language governing permissions and
- * limitations under the License.
- */
-
-package p2p
-
-//todo implement it!
-func init() {
-	//
-	//peerRepository := mem.NewPeerReopository()
-	//
-	//peerQueryService := api_gateway.NewPeerQueryApi(&peerRepository)
-	//
-	//communicationService := NewCommunicationService(mpp.Publish)
-	//
-	//communicationApi := api.NewCommunicationApi(&peerQueryService, communicationService)
-	//
-	////todo should be replaced with real struct
-	//peerApi := &MockPeerApi{}
-	//
-	////todo should be replaced with real struct
-	//eventService := common.EventServiceImpl{}
-	//
-	//mpp.eventHandler = adapter.NewEventHandler(&communicationApi, peerApi)
-	//
-	//leaderApi := api.NewLeaderApi(&peerRepository, &eventService)
-	//
-	//election := NewElection(0, "candidate", 0)
-	//
-	//electionRepository := NewElectionRepository(election)
-	//
-	////todo should be replaced with mock client
-	//client := rpc.NewClient("")
-	//
-	//electionService := NewElectionService(electionRepository, &peerQueryService, client)
-	//
-	//pLTableService := PLTableService{}
-
-}
@@ -19,6 +19,7 @@ package p2p
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/it-chain/engine/common"
 )
@@ -28,9 +29,15 @@ var ErrEmptyAddress = errors.New("empty ip address proposed")
 var ErrNoMatchingPeerId = errors.New("no matching peer id")
 
 // 노드 구조체 선언.
+// Latency는 마지막으로 측정한 ping/pong RTT이다. 측정되지 않았으면 0이다.
+// Version은 peer 정보가 확인된 시간(unix nano)으로, PLTable을 합칠 때 더 최근 정보를 고르는데 쓰인다.
+// Metadata는 handshake로 받은 peer의 정보이다.
 type Peer struct {
 	IpAddress string
 	PeerId    PeerId
+	Latency   time.Duration
+	Version   int64
+	Metadata  NodeMetadata
 }
 
 // PeerId 선언
@@ -71,4 +78,18 @@ type PeerRepository interface {
 	Save(peer Peer) error
 	SetLeader(leader Leader) error
 	Remove(id string) error
+	UpdateLatency(id string, latency time.Duration) error
+	UpdateMetadata(id string, metadata NodeMetadata) error
+}
+
+// 재시작 후 다시 연결하기 위해 저장해 두는 peer 이다.
+// LastSeen은 해당 peer와 마지막으로 연결되어 있던 시간이다.
+type KnownPeer struct {
+	Peer     Peer
+	LastSeen time.Time
+}
+
+type KnownPeerRepository interface {
+	FindKnownPeers() ([]KnownPeer, error)
+	Forget(id string) error
 }
@@ -59,6 +59,30 @@ func (pt *PLTable) GetPeerTable() (map[string]Peer, error) {
 	return pt.PeerTable, nil
 }
 
+// 두 peer table의 합집합을 만든다. 양쪽에 모두 있는 peer는 Version이 큰 쪽을 따른다.
+func MergePeerTable(mine map[string]Peer, opposite map[string]Peer) map[string]Peer {
+
+	merged := make(map[string]Peer)
+
+	for id, peer := range mine {
+		merged[id] = peer
+	}
+
+	for id, peer := range opposite {
+		if id == "" || id != peer.PeerId.Id {
+			continue
+		}
+
+		if existing, ok := merged[id]; ok && existing.Version >= peer.Version {
+			continue
+		}
+
+		merged[id] = peer
+	}
+
+	return merged
+}
+
 type PLTableService struct{}
 
 func (plts *PLTableService) GetPLTableFromCommand(command command.ReceiveGrpc) (PLTable, error) {
@@ -66,8 +90,7 @@ func (plts *PLTableService) GetPLTableFromCommand(command command.ReceiveGrpc) (
 	peerTable := PLTable{}
 
 	if err := json.Unmarshal(command.Body, &peerTable); err != nil {
-		//todo error 처리
-		return PLTable{}, nil
+		return PLTable{}, err
 	}
 
 	return peerTable, nil
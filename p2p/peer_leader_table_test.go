@@ -142,3 +142,26 @@ func TestPLTableService_GetPLTableFromCommand(t *testing.T) {
 
 	assert.Equal(t, extracted, *pt)
 }
+
+func TestMergePeerTable(t *testing.T) {
+	mine := map[string]Peer{
+		"1": {PeerId: PeerId{Id: "1"}, IpAddress: "1.old", Version: 1},
+		"2": {PeerId: PeerId{Id: "2"}, IpAddress: "2.new", Version: 5},
+	}
+
+	opposite := map[string]Peer{
+		"1": {PeerId: PeerId{Id: "1"}, IpAddress: "1.new", Version: 2},
+		"2": {PeerId: PeerId{Id: "2"}, IpAddress: "2.old", Version: 3},
+		"3": {PeerId: PeerId{Id: "3"}, IpAddress: "3", Version: 1},
+		"4": {PeerId: PeerId{Id: "fake"}, IpAddress: "4", Version: 1},
+	}
+
+	merged := MergePeerTable(mine, opposite)
+
+	// union of both tables, newer version wins and peer whose key does not match its id is ignored
+	assert.Equal(t, merged, map[string]Peer{
+		"1": {PeerId: PeerId{Id: "1"}, IpAddress: "1.new", Version: 2},
+		"2": {PeerId: PeerId{Id: "2"}, IpAddress: "2.new", Version: 5},
+		"3": {PeerId: PeerId{Id: "3"}, IpAddress: "3", Version: 1},
+	})
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p
+
+import (
+	"sync"
+	"time"
+)
+
+// peer가 규칙을 어겼을 때 깎이는 점수
+const (
+	MalformedMessage = "MalformedMessage" // 해석할 수 없는 메세지
+	VoteSpam         = "VoteSpam"         // 같은 term에 여러 번 vote
+	InvalidSignature = "InvalidSignature" // 서명이 맞지 않는 메세지
+	BadBlock         = "BadBlock"         // 검증에 실패한 block
+)
+
+const InitialReputation = 100
+
+var penalties = map[string]int{
+	MalformedMessage: 10,
+	VoteSpam:         10,
+	InvalidSignature: 30,
+	BadBlock:         50,
+}
+
+// peer 별 평판 점수. 모든 peer는 InitialReputation 으로 시작하고 규칙을 어길 때 마다 점수가 깎인다.
+type Reputation struct {
+	mux    sync.Mutex
+	scores map[string]int
+}
+
+func NewReputation() *Reputation {
+
+	return &Reputation{
+		mux:    sync.Mutex{},
+		scores: make(map[string]int),
+	}
+}
+
+// violation 만큼 점수를 깎고 깎인 점수를 반환한다. 알 수 없는 violation은 MalformedMessage로 본다.
+func (r *Reputation) Penalize(peerId string, violation string) int {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	penalty, ok := penalties[violation]
+
+	if !ok {
+		penalty = penalties[MalformedMessage]
+	}
+
+	r.scores[peerId] = r.score(peerId) - penalty
+
+	return r.scores[peerId]
+}
+
+func (r *Reputation) GetScore(peerId string) int {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.score(peerId)
+}
+
+func (r *Reputation) Reset(peerId string) {
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	delete(r.scores, peerId)
+}
+
+func (r *Reputation) score(peerId string) int {
+
+	score, ok := r.scores[peerId]
+
+	if !ok {
+		return InitialReputation
+	}
+
+	return score
+}
+
+// 평판이 낮아 Until 까지 연결이 거부되는 peer
+type Ban struct {
+	PeerId string
+	Reason string
+	Until  time.Time
+}
+
+func (b Ban) IsExpired(now time.Time) bool {
+
+	return !now.Before(b.Until)
+}
+
+type BanRepository interface {
+	SaveBan(ban Ban) error
+	FindBans() ([]Ban, error)
+	RemoveBan(peerId string) error
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package p2p_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReputation_Penalize(t *testing.T) {
+	// given
+	reputation := p2p.NewReputation()
+
+	// when, then
+	assert.Equal(t, p2p.InitialReputation, reputation.GetScore("1"))
+	assert.Equal(t, p2p.InitialReputation-50, reputation.Penalize("1", p2p.BadBlock))
+	assert.Equal(t, p2p.InitialReputation-80, reputation.Penalize("1", p2p.InvalidSignature))
+
+	// unknown violation is treated as malformed message
+	assert.Equal(t, p2p.InitialReputation-10, reputation.Penalize("2", "unknown"))
+
+	// when
+	reputation.Reset("1")
+
+	// then
+	assert.Equal(t, p2p.InitialReputation, reputation.GetScore("1"))
+}
+
+func TestBan_IsExpired(t *testing.T) {
+	// given
+	now := time.Now()
+	ban := p2p.Ban{PeerId: "1", Until: now}
+
+	// when, then
+	assert.False(t, ban.IsExpired(now.Add(-time.Second)))
+	assert.True(t, ban.IsExpired(now))
+}
@@ -16,7 +16,11 @@
 
 package mock
 
-import "github.com/it-chain/engine/p2p"
+import (
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+)
 
 type MockPLTableApi struct {
 	getPLTableFunc func() p2p.PLTable
@@ -41,14 +45,41 @@ func (mpa *MockPeerApi) Remove(peerId p2p.PeerId) error {
 }
 
 type MockLeaderApi struct {
+	UpdateLeaderWithAddressFunc func(ipAddress string) error
+	UpdateLeaderWithPLTableFunc func(oppositePLTable p2p.PLTable) error
+	UpdateLeaderWithTermFunc    func(leader p2p.Leader) error
+	SignLeaderFunc              func(term uint64, votes []p2p.Vote) (p2p.Leader, error)
+	SignVoteFunc                func(leaderId string, term uint64) (p2p.Vote, error)
+	VerifyLeaderFunc            func(leader p2p.Leader) error
+	VerifyVoteFunc              func(vote p2p.Vote) error
 }
 
 func (mla *MockLeaderApi) UpdateLeaderWithAddress(ipAddress string) error {
-	return mla.UpdateLeaderWithAddress(ipAddress)
+	return mla.UpdateLeaderWithAddressFunc(ipAddress)
+}
+
+func (mla *MockLeaderApi) UpdateLeaderWithPLTable(oppositePLTable p2p.PLTable) error {
+	return mla.UpdateLeaderWithPLTableFunc(oppositePLTable)
+}
+
+func (mla *MockLeaderApi) UpdateLeaderWithTerm(leader p2p.Leader) error {
+	return mla.UpdateLeaderWithTermFunc(leader)
+}
+
+func (mla *MockLeaderApi) SignLeader(term uint64, votes []p2p.Vote) (p2p.Leader, error) {
+	return mla.SignLeaderFunc(term, votes)
 }
 
-func (mla *MockLeaderApi) UpdateLeaderWithLargePeerTable(oppositePLTable p2p.PLTable) error {
-	return mla.UpdateLeaderWithLargePeerTable(oppositePLTable)
+func (mla *MockLeaderApi) SignVote(leaderId string, term uint64) (p2p.Vote, error) {
+	return mla.SignVoteFunc(leaderId, term)
+}
+
+func (mla *MockLeaderApi) VerifyLeader(leader p2p.Leader) error {
+	return mla.VerifyLeaderFunc(leader)
+}
+
+func (mla *MockLeaderApi) VerifyVote(vote p2p.Vote) error {
+	return mla.VerifyVoteFunc(vote)
 }
 
 type MockCommunicationApi struct {
@@ -65,3 +96,44 @@ func (mca *MockCommunicationApi) DialToUnConnectedNode(peerTable map[string]p2p.
 
 	return mca.DialToUnConnectedNodeFuc(peerTable)
 }
+
+type MockReputationApi struct {
+	PenalizeFunc       func(peerId string, violation string) error
+	RejectIfBannedFunc func(peerId string, now time.Time) (bool, error)
+	GetBansFunc        func(now time.Time) ([]p2p.Ban, error)
+	UnbanFunc          func(peerId string) error
+	ClearBansFunc      func() error
+}
+
+func (mra *MockReputationApi) Penalize(peerId string, violation string) error {
+	return mra.PenalizeFunc(peerId, violation)
+}
+
+func (mra *MockReputationApi) RejectIfBanned(peerId string, now time.Time) (bool, error) {
+	return mra.RejectIfBannedFunc(peerId, now)
+}
+
+func (mra *MockReputationApi) GetBans(now time.Time) ([]p2p.Ban, error) {
+	return mra.GetBansFunc(now)
+}
+
+func (mra *MockReputationApi) Unban(peerId string) error {
+	return mra.UnbanFunc(peerId)
+}
+
+func (mra *MockReputationApi) ClearBans() error {
+	return mra.ClearBansFunc()
+}
+
+type MockHandshakeApi struct {
+	SendHandshakeFunc   func(connectionId string) error
+	HandleHandshakeFunc func(connectionId string, message p2p.HandshakeMessage) error
+}
+
+func (m *MockHandshakeApi) SendHandshake(connectionId string) error {
+	return m.SendHandshakeFunc(connectionId)
+}
+
+func (m *MockHandshakeApi) HandleHandshake(connectionId string, message p2p.HandshakeMessage) error {
+	return m.HandleHandshakeFunc(connectionId, message)
+}
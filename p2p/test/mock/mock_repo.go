@@ -16,7 +16,11 @@
 
 package mock
 
-import "github.com/it-chain/engine/p2p"
+import (
+	"time"
+
+	"github.com/it-chain/engine/p2p"
+)
 
 func MakeFakePeerTable() map[string]p2p.Peer {
 
@@ -67,6 +71,8 @@ type MockPeerRepository struct {
 	SaveFunc              func(peer p2p.Peer) error
 	SetLeaderFunc         func(leader p2p.Leader) error
 	DeleteFunc            func(id string) error
+	UpdateLatencyFunc     func(id string, latency time.Duration) error
+	UpdateMetadataFunc    func(id string, metadata p2p.NodeMetadata) error
 }
 
 func (mpr *MockPeerRepository) GetPLTable() (p2p.PLTable, error) {
@@ -91,3 +97,22 @@ func (mpr *MockPeerRepository) SetLeader(leader p2p.Leader) error {
 func (mpr *MockPeerRepository) Delete(id string) error {
 	return mpr.DeleteFunc(id)
 }
+func (mpr *MockPeerRepository) UpdateLatency(id string, latency time.Duration) error {
+	return mpr.UpdateLatencyFunc(id, latency)
+}
+func (mpr *MockPeerRepository) UpdateMetadata(id string, metadata p2p.NodeMetadata) error {
+	return mpr.UpdateMetadataFunc(id, metadata)
+}
+
+type MockKnownPeerRepository struct {
+	FindKnownPeersFunc func() ([]p2p.KnownPeer, error)
+	ForgetFunc         func(id string) error
+}
+
+func (m *MockKnownPeerRepository) FindKnownPeers() ([]p2p.KnownPeer, error) {
+	return m.FindKnownPeersFunc()
+}
+
+func (m *MockKnownPeerRepository) Forget(id string) error {
+	return m.ForgetFunc(id)
+}
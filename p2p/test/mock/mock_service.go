@@ -163,3 +163,58 @@ type MockClient struct {
 func (c MockClient) Call(queue string, params interface{}, callback interface{}) error {
 	return c.CallFunc(queue, params, callback)
 }
+
+type MockLeaderSigner struct {
+	SignFunc     func(leader p2p.Leader) (p2p.Leader, error)
+	SignVoteFunc func(vote p2p.Vote) (p2p.Vote, error)
+}
+
+func (m *MockLeaderSigner) Sign(leader p2p.Leader) (p2p.Leader, error) {
+	return m.SignFunc(leader)
+}
+
+func (m *MockLeaderSigner) SignVote(vote p2p.Vote) (p2p.Vote, error) {
+	return m.SignVoteFunc(vote)
+}
+
+type MockLeaderVerifier struct {
+	VerifyFunc     func(leader p2p.Leader) error
+	VerifyVoteFunc func(vote p2p.Vote) error
+}
+
+func (m *MockLeaderVerifier) Verify(leader p2p.Leader) error {
+	return m.VerifyFunc(leader)
+}
+
+func (m *MockLeaderVerifier) VerifyVote(vote p2p.Vote) error {
+	return m.VerifyVoteFunc(vote)
+}
+
+type MockChainQueryService struct {
+	GetGenesisSealFunc func() ([]byte, error)
+	GetLastHeightFunc  func() (uint64, error)
+}
+
+func (m *MockChainQueryService) GetGenesisSeal() ([]byte, error) {
+	return m.GetGenesisSealFunc()
+}
+
+func (m *MockChainQueryService) GetLastHeight() (uint64, error) {
+	return m.GetLastHeightFunc()
+}
+
+type MockGossipSigner struct {
+	SignFunc func(message p2p.GossipMessage) (p2p.GossipMessage, error)
+}
+
+func (m *MockGossipSigner) Sign(message p2p.GossipMessage) (p2p.GossipMessage, error) {
+	return m.SignFunc(message)
+}
+
+type MockGossipVerifier struct {
+	VerifyFunc func(message p2p.GossipMessage) error
+}
+
+func (m *MockGossipVerifier) Verify(message p2p.GossipMessage) error {
+	return m.VerifyFunc(message)
+}
@@ -17,6 +17,8 @@
 package test
 
 import (
+	"time"
+
 	"github.com/it-chain/avengers/mock"
 	"github.com/it-chain/engine/api_gateway"
 	"github.com/it-chain/engine/common/logger"
@@ -57,18 +59,43 @@ func SetTestEnvironment(processList []ProcessIdentity) map[string]*mock.Process
 			return nil
 		}
 
+		// avengers 환경에서는 서명을 하지 않는다.
+		signer := &mock2.MockLeaderSigner{}
+		signer.SignFunc = func(leader p2p.Leader) (p2p.Leader, error) {
+			return leader, nil
+		}
+		signer.SignVoteFunc = func(vote p2p.Vote) (p2p.Vote, error) {
+			return vote, nil
+		}
+
+		verifier := &mock2.MockLeaderVerifier{}
+		verifier.VerifyFunc = func(leader p2p.Leader) error {
+			return nil
+		}
+		verifier.VerifyVoteFunc = func(vote p2p.Vote) error {
+			return nil
+		}
+
+		leaderApi := api.NewLeaderApi(&peerRepository, &eventService, entity.Id, signer, verifier)
+
 		// inject avengers client
-		electionService := p2p.NewElectionService(&election, &peerQueryService, &client)
+		electionService := p2p.NewElectionService(&election, &peerQueryService, &client, &leaderApi, 1)
 
 		pLTableService := p2p.PLTableService{}
 
 		// inject avengers client
 		communicationService := p2p.NewCommunicationService(&client)
 
-		communicationApi := api.NewCommunicationApi(&peerQueryService, communicationService)
+		communicationApi := api.NewCommunicationApi(&peerQueryService, communicationService, 0)
+
+		reputationApi := &mock2.MockReputationApi{}
+		reputationApi.PenalizeFunc = func(peerId string, violation string) error {
+			return nil
+		}
+
+		peerService := adapter.NewPeerService(&client, &peerQueryService, time.Second)
 
-		leaderApi := api.NewLeaderApi(&peerRepository, &eventService)
-		grpcCommandHandler := adapter.NewGrpcCommandHandler(&leaderApi, &electionService, &communicationApi, pLTableService)
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(&leaderApi, &electionService, &communicationApi, pLTableService, peerService, reputationApi)
 
 		// avengers server register command handler
 		server.Register("message.receive", grpcCommandHandler.HandleMessageReceive)
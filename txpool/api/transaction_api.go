@@ -17,25 +17,36 @@
 package api
 
 import (
+	"errors"
 	"log"
 
 	"github.com/it-chain/engine/txpool"
 )
 
+var ErrSenderNotSame = errors.New("transaction is not created by sender")
+
 type TransactionApi struct {
 	publisherId           string
 	transactionRepository txpool.TransactionRepository
+	membershipService     txpool.MembershipService
 }
 
-func NewTransactionApi(publisherId string, transactionRepository txpool.TransactionRepository) TransactionApi {
+func NewTransactionApi(publisherId string, transactionRepository txpool.TransactionRepository, membershipService txpool.MembershipService) TransactionApi {
 	return TransactionApi{
 		publisherId:           publisherId,
 		transactionRepository: transactionRepository,
+		membershipService:     membershipService,
 	}
 }
 
+// 승인되지 않은 membership 변경은 txpool에 넣지 않는다.
 func (t TransactionApi) CreateTransaction(txData txpool.TxData) (txpool.Transaction, error) {
 
+	if err := t.membershipService.Authorize(txData); err != nil {
+		log.Printf("fail to authorize membership transaction: [%v]", err)
+		return txpool.Transaction{}, err
+	}
+
 	transaction, err := txpool.CreateTransaction(t.publisherId, txData)
 
 	if err != nil {
@@ -48,7 +59,42 @@ func (t TransactionApi) CreateTransaction(txData txpool.TxData) (txpool.Transact
 	return transaction, err
 }
 
+// leader가 아닌 node가 보낸 transaction을 block에 넣기 위해 저장한다.
+// 보낸 node가 만든 transaction이 아니거나 승인되지 않은 membership 변경은 저장하지 않는다.
+func (t TransactionApi) SaveLeaderTransactions(senderId string, transactions []txpool.Transaction) error {
+
+	for _, transaction := range transactions {
+
+		if transaction.PeerID != senderId {
+			log.Printf("fail to save leader transaction: [%v], tx: [%s]", ErrSenderNotSame, transaction.ID)
+			continue
+		}
+
+		if err := t.membershipService.Authorize(txDataOf(transaction)); err != nil {
+			log.Printf("fail to authorize membership transaction: [%v], tx: [%s]", err, transaction.ID)
+			continue
+		}
+
+		if err := t.transactionRepository.Save(transaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t TransactionApi) DeleteTransaction(id txpool.TransactionId) {
 
 	t.transactionRepository.Remove(id)
 }
+
+func txDataOf(transaction txpool.Transaction) txpool.TxData {
+
+	return txpool.TxData{
+		Jsonrpc:   transaction.Jsonrpc,
+		ICodeID:   transaction.ICodeID,
+		Function:  transaction.Function,
+		Args:      transaction.Args,
+		Signature: transaction.Signature,
+	}
+}
@@ -19,6 +19,7 @@ package api_test
 import (
 	"testing"
 
+	"github.com/it-chain/engine/consensus"
 	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/engine/txpool/api"
 	"github.com/it-chain/engine/txpool/infra/mem"
@@ -47,15 +48,35 @@ func TestTransactionApi_CreateTransaction(t *testing.T) {
 			},
 			err: nil,
 		},
+		"membership change is not approved": {
+			input: struct {
+				txData txpool.TxData
+			}{
+				txData: txpool.TxData{
+					ICodeID:  consensus.MembershipICodeID,
+					Function: consensus.Join,
+					Args:     []string{"node1"},
+					Jsonrpc:  "2.0",
+				},
+			},
+			err: consensus.ErrMembershipNotApproved,
+		},
 	}
 
 	transactionRepository := mem.NewTransactionRepository()
-	transactionApi := api.NewTransactionApi("zf", transactionRepository)
+	transactionApi := api.NewTransactionApi("zf", transactionRepository, membershipService{})
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
 
-	for _, test := range tests {
 		tx, err := transactionApi.CreateTransaction(test.input.txData)
 
 		assert.Equal(t, test.err, err)
+		if err != nil {
+			assert.Equal(t, txpool.Transaction{}, tx)
+			continue
+		}
+
 		assert.Equal(t, tx.ICodeID, test.input.txData.ICodeID)
 		assert.Equal(t, tx.Args, test.input.txData.Args)
 		assert.Equal(t, tx.Signature, test.input.txData.Signature)
@@ -77,7 +98,7 @@ func TestTransactionApi_DeleteTransaction(t *testing.T) {
 	}
 
 	transactionRepository := mem.NewTransactionRepository()
-	transactionApi := api.NewTransactionApi("zf", transactionRepository)
+	transactionApi := api.NewTransactionApi("zf", transactionRepository, membershipService{})
 
 	transactionRepository.Save(txpool.Transaction{
 		ID: "transactionID",
@@ -90,3 +111,63 @@ func TestTransactionApi_DeleteTransaction(t *testing.T) {
 		assert.Equal(t, err, test.err)
 	}
 }
+
+func TestTransactionApi_SaveLeaderTransactions(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			senderId    string
+			transaction txpool.Transaction
+		}
+		saved bool
+	}{
+		"transaction of sender": {
+			input: struct {
+				senderId    string
+				transaction txpool.Transaction
+			}{senderId: "node1", transaction: txpool.Transaction{ID: "tx1", PeerID: "node1", ICodeID: "gg"}},
+			saved: true,
+		},
+		"transaction of other node": {
+			input: struct {
+				senderId    string
+				transaction txpool.Transaction
+			}{senderId: "node1", transaction: txpool.Transaction{ID: "tx2", PeerID: "node2", ICodeID: "gg"}},
+			saved: false,
+		},
+		"membership change is not approved": {
+			input: struct {
+				senderId    string
+				transaction txpool.Transaction
+			}{senderId: "node1", transaction: txpool.Transaction{ID: "tx3", PeerID: "node1", ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"node4"}}},
+			saved: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		transactionRepository := mem.NewTransactionRepository()
+		transactionApi := api.NewTransactionApi("zf", transactionRepository, membershipService{})
+
+		// when
+		err := transactionApi.SaveLeaderTransactions(test.input.senderId, []txpool.Transaction{test.input.transaction})
+
+		// then
+		assert.NoError(t, err)
+		_, err = transactionRepository.FindById(test.input.transaction.ID)
+		assert.Equal(t, test.saved, err == nil)
+	}
+}
+
+// membership 변경 transaction은 모두 승인되지 않은 것으로 본다.
+type membershipService struct{}
+
+func (membershipService) Authorize(txData txpool.TxData) error {
+	if txData.ICodeID == consensus.MembershipICodeID {
+		return consensus.ErrMembershipNotApproved
+	}
+
+	return nil
+}
@@ -24,29 +24,34 @@ import (
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/common/logger"
 	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/consensus"
 	"github.com/it-chain/engine/txpool"
 	"github.com/rs/xid"
 )
 
 type BlockProposalService struct {
 	client           rpc.Client // midgard.client
-	engineMode       string
+	consensus        consensus.Consensus
 	txpoolRepository txpool.TransactionRepository
+	transferService  txpool.TransferService
+	sent             map[txpool.TransactionId]struct{}
 	sync.RWMutex
 }
 
-func NewBlockProposalService(client rpc.Client, txpoolRepository txpool.TransactionRepository, engineMode string) *BlockProposalService {
+func NewBlockProposalService(client rpc.Client, txpoolRepository txpool.TransactionRepository, consensus consensus.Consensus, transferService txpool.TransferService) *BlockProposalService {
 	return &BlockProposalService{
 		client:           client,
-		engineMode:       engineMode,
+		consensus:        consensus,
 		RWMutex:          sync.RWMutex{},
 		txpoolRepository: txpoolRepository,
+		transferService:  transferService,
+		sent:             make(map[txpool.TransactionId]struct{}),
 	}
 }
 
 // todo do not delete transaction immediately
 // todo transaction will be deleted when block are committed
-func (b BlockProposalService) ProposeBlock() error {
+func (b *BlockProposalService) ProposeBlock() error {
 
 	b.Lock()
 	defer b.Unlock()
@@ -62,23 +67,71 @@ func (b BlockProposalService) ProposeBlock() error {
 		return nil
 	}
 
-	if b.engineMode == "solo" {
-		//propose transaction when solo mode
-		if err := b.sendBlockProposal(transactions); err != nil {
-			return err
-		}
+	// block은 합의 방식이 정한 proposer만 제안할 수 있다. 다른 node는 transaction을 leader에게 보낸다.
+	if !b.consensus.IsProposer() {
+		return b.sendLeaderTransactions(transactions)
+	}
+
+	if err := b.sendBlockProposal(transactions); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		b.txpoolRepository.Remove(tx.ID)
+		delete(b.sent, tx.ID)
+	}
 
-		for _, tx := range transactions {
-			b.txpoolRepository.Remove(tx.ID)
+	return nil
+}
+
+// gossip은 leader가 받았는지 알 수 없으므로, 보낸 transaction은 확정된 block에 포함될 때까지 지우지 않는다.
+// 아직 보내지 않은 transaction만 보내고, leader가 바뀌면 다시 보낸다.
+func (b *BlockProposalService) sendLeaderTransactions(transactions []txpool.Transaction) error {
+
+	unsent := make([]txpool.Transaction, 0)
+	for _, tx := range transactions {
+		if _, ok := b.sent[tx.ID]; !ok {
+			unsent = append(unsent, tx)
 		}
+	}
 
+	if len(unsent) == 0 {
 		return nil
 	}
 
+	if err := b.transferService.SendLeaderTransactions(unsent); err != nil {
+		return err
+	}
+
+	for _, tx := range unsent {
+		b.sent[tx.ID] = struct{}{}
+	}
+
 	return nil
 }
 
-func (b BlockProposalService) sendBlockProposal(transactions []txpool.Transaction) error {
+// 확정된 block에 포함된 transaction을 지운다.
+func (b *BlockProposalService) RemoveCommittedTransactions(txIDs []txpool.TransactionId) {
+
+	b.Lock()
+	defer b.Unlock()
+
+	for _, id := range txIDs {
+		b.txpoolRepository.Remove(id)
+		delete(b.sent, id)
+	}
+}
+
+// 이전 leader에게 보낸 transaction은 block에 포함된다는 보장이 없으므로 새 leader에게 다시 보낸다.
+func (b *BlockProposalService) ResendLeaderTransactions() {
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.sent = make(map[txpool.TransactionId]struct{})
+}
+
+func (b *BlockProposalService) sendBlockProposal(transactions []txpool.Transaction) error {
 
 	if len(transactions) == 0 {
 		return errors.New("Empty transaction list proposed")
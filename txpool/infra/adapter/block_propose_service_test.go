@@ -17,10 +17,13 @@
 package adapter_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/common/command"
 	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/consensus/solo"
 	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/engine/txpool/infra/adapter"
 	"github.com/it-chain/engine/txpool/infra/mem"
@@ -45,10 +48,107 @@ func TestBlockService_ProposeBlock(t *testing.T) {
 	transactions, _ := txpoolRepository.FindAll()
 	assert.Equal(t, 2, len(transactions))
 
-	blockService := adapter.NewBlockProposalService(client, txpoolRepository, "solo")
+	blockService := adapter.NewBlockProposalService(client, txpoolRepository, solo.NewConsensus(), adapter.NewTransferService(nil))
 	err = blockService.ProposeBlock()
 	assert.NoError(t, err)
 
 	transactions, _ = txpoolRepository.FindAll()
 	assert.Equal(t, 0, len(transactions))
 }
+
+func TestBlockService_ProposeBlock_NotProposer(t *testing.T) {
+
+	// given
+	txpoolRepository := mem.NewTransactionRepository()
+	txpoolRepository.Save(txpool.Transaction{ID: "tx1"})
+	txpoolRepository.Save(txpool.Transaction{ID: "tx2"})
+
+	sent := make([]txpool.Transaction, 0)
+	publisher := func(topic string, data interface{}) error {
+		assert.Equal(t, "message.gossip", topic)
+
+		transactions := make([]txpool.Transaction, 0)
+		assert.NoError(t, common.Deserialize(data.(command.GossipGrpc).Body, &transactions))
+		sent = append(sent, transactions...)
+
+		return nil
+	}
+
+	blockService := adapter.NewBlockProposalService(rpc.Client{}, txpoolRepository, follower{solo.NewConsensus()}, adapter.NewTransferService(publisher))
+
+	// when
+	err := blockService.ProposeBlock()
+
+	// then : 확정된 block에 포함될 때까지 가지고 있는다.
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(sent))
+
+	transactions, _ := txpoolRepository.FindAll()
+	assert.Equal(t, 2, len(transactions))
+
+	// when : 이미 보낸 transaction은 다시 보내지 않는다.
+	txpoolRepository.Save(txpool.Transaction{ID: "tx3"})
+	err = blockService.ProposeBlock()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(sent))
+	assert.Equal(t, "tx3", sent[2].ID)
+
+	// when : leader가 바뀌면 가지고 있는 transaction을 다시 보낸다.
+	blockService.ResendLeaderTransactions()
+	err = blockService.ProposeBlock()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(sent))
+
+	// when : 확정된 block에 포함된 transaction은 지운다.
+	blockService.RemoveCommittedTransactions([]txpool.TransactionId{"tx1", "tx2"})
+
+	// then
+	transactions, _ = txpoolRepository.FindAll()
+	assert.Equal(t, 1, len(transactions))
+	assert.Equal(t, "tx3", transactions[0].ID)
+}
+
+func TestBlockService_ProposeBlock_SendFailed(t *testing.T) {
+
+	// given
+	txpoolRepository := mem.NewTransactionRepository()
+	txpoolRepository.Save(txpool.Transaction{ID: "tx1"})
+
+	sent := 0
+	publishErr := errors.New("publish error")
+	publisher := func(topic string, data interface{}) error {
+		sent++
+		if sent == 1 {
+			return publishErr
+		}
+		return nil
+	}
+
+	blockService := adapter.NewBlockProposalService(rpc.Client{}, txpoolRepository, follower{solo.NewConsensus()}, adapter.NewTransferService(publisher))
+
+	// when
+	err := blockService.ProposeBlock()
+
+	// then
+	assert.Equal(t, publishErr, err)
+
+	// when : 보내지 못한 transaction은 다음 주기에 다시 보낸다.
+	err = blockService.ProposeBlock()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+}
+
+// block을 제안할 수 없는 node
+type follower struct {
+	*solo.Consensus
+}
+
+func (follower) IsProposer() bool {
+	return false
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/txpool"
+)
+
+// block이 확정되거나 leader가 바뀌면 leader에게 보낸 transaction을 정리하거나 다시 보낸다.
+type EventHandler struct {
+	blockProposalService *BlockProposalService
+}
+
+func NewEventHandler(blockProposalService *BlockProposalService) *EventHandler {
+	return &EventHandler{
+		blockProposalService: blockProposalService,
+	}
+}
+
+func (e *EventHandler) HandleBlockCommittedEvent(blockCommittedEvent event.BlockCommitted) {
+
+	txIDs := make([]txpool.TransactionId, 0)
+	for _, tx := range blockCommittedEvent.TxList {
+		txIDs = append(txIDs, tx.ID)
+	}
+
+	e.blockProposalService.RemoveCommittedTransactions(txIDs)
+}
+
+func (e *EventHandler) HandleLeaderUpdatedEvent(leaderUpdatedEvent event.LeaderUpdated) {
+	e.blockProposalService.ResendLeaderTransactions()
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common/event"
+	"github.com/it-chain/engine/common/rabbitmq/rpc"
+	"github.com/it-chain/engine/consensus/solo"
+	"github.com/it-chain/engine/txpool"
+	"github.com/it-chain/engine/txpool/infra/adapter"
+	"github.com/it-chain/engine/txpool/infra/mem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHandler(t *testing.T) {
+
+	// given
+	txpoolRepository := mem.NewTransactionRepository()
+	txpoolRepository.Save(txpool.Transaction{ID: "tx1"})
+	txpoolRepository.Save(txpool.Transaction{ID: "tx2"})
+
+	sent := 0
+	publisher := func(topic string, data interface{}) error {
+		sent++
+		return nil
+	}
+
+	blockService := adapter.NewBlockProposalService(rpc.Client{}, txpoolRepository, follower{solo.NewConsensus()}, adapter.NewTransferService(publisher))
+	eventHandler := adapter.NewEventHandler(blockService)
+	assert.NoError(t, blockService.ProposeBlock())
+
+	// when
+	eventHandler.HandleBlockCommittedEvent(event.BlockCommitted{TxList: []event.Tx{{ID: "tx1"}}})
+
+	// then
+	transactions, _ := txpoolRepository.FindAll()
+	assert.Equal(t, []txpool.Transaction{{ID: "tx2"}}, transactions)
+
+	// when
+	eventHandler.HandleLeaderUpdatedEvent(event.LeaderUpdated{LeaderId: "leader", Term: 2})
+	assert.NoError(t, blockService.ProposeBlock())
+
+	// then
+	assert.Equal(t, 2, sent)
+}
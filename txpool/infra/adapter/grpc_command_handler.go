@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/txpool"
+	"github.com/it-chain/engine/txpool/api"
+)
+
+// 다른 node가 보낸 txpool protocol 메세지를 처리한다.
+type GrpcCommandHandler struct {
+	transactionApi api.TransactionApi
+	consensus      consensus.Consensus
+}
+
+func NewGrpcCommandHandler(transactionApi api.TransactionApi, consensus consensus.Consensus) GrpcCommandHandler {
+	return GrpcCommandHandler{
+		transactionApi: transactionApi,
+		consensus:      consensus,
+	}
+}
+
+func (g *GrpcCommandHandler) HandleGrpcCommand(command command.ReceiveGrpc) error {
+
+	switch command.Protocol {
+
+	// gossip으로 모든 node에 전달되므로 block을 제안하는 node만 저장한다.
+	// ConnectionID는 gossip의 서명으로 확인한 transaction을 보낸 node의 id 이다.
+	// common.Deserialize는 잘못된 body에 panic 하므로 직접 decode 하고 error를 반환한다.
+	case "SendLeaderTransactionsProtocol":
+		if !g.consensus.IsProposer() {
+			return nil
+		}
+
+		transactions := make([]txpool.Transaction, 0)
+		if err := json.Unmarshal(command.Body, &transactions); err != nil {
+			return err
+		}
+
+		return g.transactionApi.SaveLeaderTransactions(command.ConnectionID, transactions)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/common/command"
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/solo"
+	"github.com/it-chain/engine/txpool"
+	"github.com/it-chain/engine/txpool/api"
+	"github.com/it-chain/engine/txpool/infra/adapter"
+	"github.com/it-chain/engine/txpool/infra/mem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrpcCommandHandler_HandleGrpcCommand(t *testing.T) {
+
+	body, err := common.Serialize([]txpool.Transaction{{ID: "tx1", PeerID: "node1"}})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input struct {
+			consensus consensus.Consensus
+			command   command.ReceiveGrpc
+		}
+		saved bool
+	}{
+		"proposer saves transactions": {
+			input: struct {
+				consensus consensus.Consensus
+				command   command.ReceiveGrpc
+			}{
+				consensus: solo.NewConsensus(),
+				command:   command.ReceiveGrpc{Protocol: "SendLeaderTransactionsProtocol", ConnectionID: "node1", Body: body},
+			},
+			saved: true,
+		},
+		"other node ignores transactions": {
+			input: struct {
+				consensus consensus.Consensus
+				command   command.ReceiveGrpc
+			}{
+				consensus: follower{solo.NewConsensus()},
+				command:   command.ReceiveGrpc{Protocol: "SendLeaderTransactionsProtocol", ConnectionID: "node1", Body: body},
+			},
+			saved: false,
+		},
+		"transactions of other node": {
+			input: struct {
+				consensus consensus.Consensus
+				command   command.ReceiveGrpc
+			}{
+				consensus: solo.NewConsensus(),
+				command:   command.ReceiveGrpc{Protocol: "SendLeaderTransactionsProtocol", ConnectionID: "node2", Body: body},
+			},
+			saved: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		transactionRepository := mem.NewTransactionRepository()
+		transactionApi := api.NewTransactionApi("me", transactionRepository, adapter.NewMembershipService(test.input.consensus, nil))
+		handler := adapter.NewGrpcCommandHandler(transactionApi, test.input.consensus)
+
+		// when
+		err := handler.HandleGrpcCommand(test.input.command)
+
+		// then
+		assert.NoError(t, err)
+		_, err = transactionRepository.FindById("tx1")
+		assert.Equal(t, test.saved, err == nil)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_MalformedBody(t *testing.T) {
+
+	// given
+	transactionRepository := mem.NewTransactionRepository()
+	transactionApi := api.NewTransactionApi("me", transactionRepository, adapter.NewMembershipService(solo.NewConsensus(), nil))
+	handler := adapter.NewGrpcCommandHandler(transactionApi, solo.NewConsensus())
+
+	// when
+	err := handler.HandleGrpcCommand(command.ReceiveGrpc{Protocol: "SendLeaderTransactionsProtocol", ConnectionID: "node1", Body: []byte("{")})
+
+	// then
+	assert.Error(t, err)
+	transactions, err := transactionRepository.FindAll()
+	assert.NoError(t, err)
+	assert.Empty(t, transactions)
+}
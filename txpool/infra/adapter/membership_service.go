@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/txpool"
+)
+
+// 합의 방식의 현재 member 목록과 epoch로 membership 변경 transaction의 approval을 확인한다.
+type MembershipService struct {
+	consensus consensus.Consensus
+	verifier  consensus.ApprovalVerifier
+}
+
+func NewMembershipService(consensus consensus.Consensus, verifier consensus.ApprovalVerifier) *MembershipService {
+	return &MembershipService{
+		consensus: consensus,
+		verifier:  verifier,
+	}
+}
+
+func (m *MembershipService) Authorize(txData txpool.TxData) error {
+
+	if txData.ICodeID != consensus.MembershipICodeID {
+		return nil
+	}
+
+	change, ok := consensus.NewMembershipChange(txData.ICodeID, txData.Function, txData.Args, txData.Signature)
+	if !ok {
+		return consensus.ErrInvalidMembershipChange
+	}
+
+	return consensus.AuthorizeMembershipChange(change, m.consensus.Members(), m.consensus.Epoch(), m.verifier)
+}
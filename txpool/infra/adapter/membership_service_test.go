@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018 It-chain
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/consensus"
+	"github.com/it-chain/engine/consensus/solo"
+	"github.com/it-chain/engine/consensus/test/mock"
+	"github.com/it-chain/engine/txpool"
+	"github.com/it-chain/engine/txpool/infra/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+type members struct {
+	*solo.Consensus
+	ids []string
+}
+
+func (m members) Members() []string {
+	return m.ids
+}
+
+func TestMembershipService_Authorize(t *testing.T) {
+
+	join := consensus.MembershipChange{Function: consensus.Join, MemberID: "node4"}
+
+	tests := map[string]struct {
+		input txpool.TxData
+		err   error
+	}{
+		"not a membership transaction": {
+			input: txpool.TxData{ICodeID: "icode1", Function: "invoke", Args: []string{"node4"}},
+			err:   nil,
+		},
+		"approved by a majority": {
+			input: txpool.TxData{ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"node4"}, Signature: mock.ApprovalSignature(join, "node1", "node2")},
+			err:   nil,
+		},
+		"approved by a minority": {
+			input: txpool.TxData{ICodeID: consensus.MembershipICodeID, Function: consensus.Join, Args: []string{"node4"}, Signature: mock.ApprovalSignature(join, "node1")},
+			err:   consensus.ErrMembershipNotApproved,
+		},
+		"invalid membership change": {
+			input: txpool.TxData{ICodeID: consensus.MembershipICodeID, Function: "kick", Args: []string{"node4"}},
+			err:   consensus.ErrInvalidMembershipChange,
+		},
+	}
+
+	membershipService := adapter.NewMembershipService(members{Consensus: solo.NewConsensus(), ids: []string{"node1", "node2", "node3"}}, mock.ApprovalVerifier{})
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := membershipService.Authorize(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
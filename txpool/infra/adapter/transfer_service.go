@@ -40,35 +40,23 @@ func NewTransferService(publisher Publisher) *TransferService {
 	}
 }
 
-func (ts TransferService) SendLeaderTransactions(transactions []txpool.Transaction, leader txpool.Leader) error {
+// leader와 직접 연결되어 있지 않아도 전달되도록 gossip으로 보낸다.
+// gossip은 모든 node에 전달되고, block을 제안하는 node만 받은 transaction을 저장한다.
+func (ts TransferService) SendLeaderTransactions(transactions []txpool.Transaction) error {
 
 	if len(transactions) == 0 {
 		return ErrTxEmpty
 	}
 
-	deliverCommand, err := createGrpcDeliverCommand("SendLeaderTransactionsProtocol", transactions)
+	body, err := common.Serialize(transactions)
 
 	if err != nil {
 		return err
 	}
 
-	deliverCommand.RecipientList = append(deliverCommand.RecipientList, leader.LeaderId.ToString())
-
-	return ts.publisher("message.deliver", deliverCommand)
-}
-
-func createGrpcDeliverCommand(protocol string, body interface{}) (command.DeliverGrpc, error) {
-
-	data, err := common.Serialize(body)
-
-	if err != nil {
-		return command.DeliverGrpc{}, err
-	}
-
-	return command.DeliverGrpc{
-		MessageId:     xid.New().String(),
-		RecipientList: make([]string, 0),
-		Body:          data,
-		Protocol:      protocol,
-	}, err
+	return ts.publisher("message.gossip", command.GossipGrpc{
+		MessageId: xid.New().String(),
+		Body:      body,
+		Protocol:  "SendLeaderTransactionsProtocol",
+	})
 }
@@ -30,27 +30,22 @@ func TestGrpcCommandService_SendLeaderTransactions(t *testing.T) {
 	tests := map[string]struct {
 		input struct {
 			transactions []txpool.Transaction
-			leader       txpool.Leader
 		}
 		err error
 	}{
 		"success": {
 			input: struct {
 				transactions []txpool.Transaction
-				leader       txpool.Leader
 			}{
 				transactions: []txpool.Transaction{{ID: txpool.TransactionId("zf")}},
-				leader:       txpool.Leader{LeaderId: txpool.LeaderId{Id: "zf2"}},
 			},
 			err: nil,
 		},
 		"transaction empty test": {
 			input: struct {
 				transactions []txpool.Transaction
-				leader       txpool.Leader
 			}{
 				transactions: []txpool.Transaction{},
-				leader:       txpool.Leader{LeaderId: txpool.LeaderId{Id: "zf2"}},
 			},
 			err: adapter.ErrTxEmpty,
 		},
@@ -58,11 +53,12 @@ func TestGrpcCommandService_SendLeaderTransactions(t *testing.T) {
 
 	publisher := func(topic string, data interface{}) (err error) {
 		txList := &[]*txpool.Transaction{}
-		deliverCommand := data.(command.DeliverGrpc)
+		gossipCommand := data.(command.GossipGrpc)
 
-		common.Deserialize(deliverCommand.Body, txList)
+		common.Deserialize(gossipCommand.Body, txList)
 
-		assert.Equal(t, topic, "message.deliver")
+		assert.Equal(t, topic, "message.gossip")
+		assert.Equal(t, "SendLeaderTransactionsProtocol", gossipCommand.Protocol)
 		assert.Equal(t, 1, len(*txList))
 
 		return nil
@@ -73,7 +69,7 @@ func TestGrpcCommandService_SendLeaderTransactions(t *testing.T) {
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
 
-		err := transferService.SendLeaderTransactions(test.input.transactions, test.input.leader)
+		err := transferService.SendLeaderTransactions(test.input.transactions)
 
 		assert.Equal(t, test.err, err)
 	}
@@ -20,14 +20,21 @@ type TxpoolQueryService interface {
 	FindUncommittedTransactions() ([]Transaction, error)
 }
 
+// leader가 아닌 node는 transaction을 leader에게 보낸다.
 type TransferService interface {
-	SendTransactionsToLeader(transactions []Transaction, leader Leader) error
+	SendLeaderTransactions(transactions []Transaction) error
 }
 
 type BlockProposalService interface {
 	ProposeBlock() error
 }
 
+// membership 변경 transaction이 현재 validator의 과반에게 승인되었는지 확인한다.
+// membership 변경이 아닌 transaction은 확인하지 않는다.
+type MembershipService interface {
+	Authorize(txData TxData) error
+}
+
 func filter(vs []Transaction, f func(Transaction) bool) []Transaction {
 	vsf := make([]Transaction, 0)
 	for _, v := range vs {